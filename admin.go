@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"spoti/auth"
+	"spoti/config"
+	"spoti/database"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/models"
+	"spoti/repository"
+	"spoti/repository/postgres"
+	"spoti/validate"
+)
+
+// adminRepos, admin komutlarının kullandığı repository'leri toplar.
+type adminRepos struct {
+	users     repository.UserRepository
+	songs     repository.SongRepository
+	playlists repository.PlaylistRepository
+}
+
+// runAdmin, "spoti admin" alt komutlarını çalıştırır.
+func runAdmin(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("admin alt komutu eksik: create-admin, promote, reset-password veya seed kullanın")
+	}
+	command, args := args[0], args[1:]
+
+	ctx := context.Background()
+	db, err := database.Connect(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	repos := adminRepos{
+		users:     postgres.NewUserRepository(db),
+		songs:     postgres.NewSongRepository(db),
+		playlists: postgres.NewPlaylistRepository(db),
+	}
+
+	switch command {
+	case "create-admin":
+		return adminCreate(ctx, repos, args)
+	case "promote":
+		return adminPromote(ctx, repos, args)
+	case "reset-password":
+		return adminResetPassword(ctx, repos, args)
+	case "seed":
+		return seedDemoData(ctx, repos)
+	default:
+		return fmt.Errorf("bilinmeyen admin komutu: %q", command)
+	}
+}
+
+// adminCreate, admin rolüne sahip yeni bir kullanıcı oluşturur.
+func adminCreate(ctx context.Context, repos adminRepos, args []string) error {
+	fs := flag.NewFlagSet("admin create-admin", flag.ExitOnError)
+	username := fs.String("username", "", "Kullanıcı adı (zorunlu)")
+	email := fs.String("email", "", "E-posta adresi (zorunlu)")
+	password := fs.String("password", "", "Şifre (boşsa $SPOTI_ADMIN_PASSWORD veya standart girdi)")
+	fs.Parse(args)
+
+	if *username == "" || *email == "" {
+		return errors.New("kullanım: spoti admin create-admin -username ad -email e-posta [-password şifre]")
+	}
+	plain, err := passwordArg(*password)
+	if err != nil {
+		return err
+	}
+	// API'deki kayıt kurallarının aynısı admin hesapları için de geçerlidir.
+	if err := validationError(dto.RegisterRequest{Username: *username, Email: *email, Password: plain}); err != nil {
+		return err
+	}
+	hashed, err := auth.HashPassword(plain)
+	if err != nil {
+		return fmt.Errorf("şifre hashlenemedi: %w", err)
+	}
+
+	user := models.User{
+		ID:        uuid.New(),
+		Username:  *username,
+		Email:     *email,
+		Password:  hashed,
+		HesapTuru: "Free",
+		Cash:      100.00,
+	}
+	err = repos.users.Create(ctx, &user, "admin")
+	if errors.Is(err, repository.ErrConflict) {
+		return fmt.Errorf("%q kullanıcı adı veya %q e-postası zaten kullanılıyor", *username, *email)
+	}
+	if err != nil {
+		return fmt.Errorf("admin oluşturulamadı: %w", err)
+	}
+
+	log.Printf("Admin kullanıcısı oluşturuldu: %s (%s)\n", user.Username, user.ID)
+	return nil
+}
+
+// adminPromote, e-posta adresiyle bulunan kullanıcının rolünü değiştirir.
+func adminPromote(ctx context.Context, repos adminRepos, args []string) error {
+	fs := flag.NewFlagSet("admin promote", flag.ExitOnError)
+	email := fs.String("email", "", "Kullanıcının e-posta adresi (zorunlu)")
+	role := fs.String("role", "admin", "Atanacak rol adı")
+	fs.Parse(args)
+
+	if *email == "" {
+		return errors.New("kullanım: spoti admin promote -email e-posta [-role rol]")
+	}
+
+	user, err := repos.users.GetByEmail(ctx, *email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%q e-postasıyla kullanıcı bulunamadı", *email)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := repos.users.SetRole(ctx, user.ID, *role); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%q rolü bulunamadı", *role)
+		}
+		return fmt.Errorf("rol değiştirilemedi: %w", err)
+	}
+
+	log.Printf("%s kullanıcısının rolü %q olarak güncellendi.\n", user.Email, *role)
+	return nil
+}
+
+// adminResetPassword, kullanıcının şifresini bcrypt ile yeniden hash'leyerek değiştirir.
+func adminResetPassword(ctx context.Context, repos adminRepos, args []string) error {
+	fs := flag.NewFlagSet("admin reset-password", flag.ExitOnError)
+	email := fs.String("email", "", "Kullanıcının e-posta adresi (zorunlu)")
+	password := fs.String("password", "", "Yeni şifre (boşsa $SPOTI_ADMIN_PASSWORD veya standart girdi)")
+	fs.Parse(args)
+
+	if *email == "" {
+		return errors.New("kullanım: spoti admin reset-password -email e-posta [-password şifre]")
+	}
+
+	user, err := repos.users.GetByEmail(ctx, *email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%q e-postasıyla kullanıcı bulunamadı", *email)
+	}
+	if err != nil {
+		return err
+	}
+
+	plain, err := passwordArg(*password)
+	if err != nil {
+		return err
+	}
+	hashed, err := auth.HashPassword(plain)
+	if err != nil {
+		return fmt.Errorf("şifre hashlenemedi: %w", err)
+	}
+	if err := repos.users.SetPassword(ctx, user.ID, hashed); err != nil {
+		return fmt.Errorf("şifre güncellenemedi: %w", err)
+	}
+
+	log.Printf("%s kullanıcısının şifresi sıfırlandı.\n", user.Email)
+	return nil
+}
+
+// passwordArg, bayrakla verilen şifreyi döndürür. Bayrak boşsa SPOTI_ADMIN_PASSWORD
+// ortam değişkenine, o da yoksa standart girdiden okunan satıra bakar.
+func passwordArg(password string) (string, error) {
+	if password == "" {
+		password = os.Getenv("SPOTI_ADMIN_PASSWORD")
+	}
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Şifre: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("şifre okunamadı: %w", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+	if password == "" {
+		return "", errors.New("şifre boş olamaz")
+	}
+	return password, nil
+}
+
+// validationError, v'yi doğrular ve alan hatalarını tek bir okunabilir hataya çevirir.
+func validationError(v any) error {
+	fieldErrs := validate.Struct(v)
+	if len(fieldErrs) == 0 {
+		return nil
+	}
+	problems := make([]string, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		problems[i] = fe.Field + ": " + i18n.Translate(i18n.TR, fe.Key, fe.Args...)
+	}
+	return errors.New("geçersiz değerler: " + strings.Join(problems, "; "))
+}
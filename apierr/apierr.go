@@ -0,0 +1,235 @@
+// Package apierr, API'nin döndürdüğü hataları sabit kodlarla tanımlar ve
+// tüm yanıtlarda aynı biçimde gösterilmelerini sağlar:
+//
+//	{"error": {"code": "PLAYLIST_LIMIT_REACHED", "message": "...", "details": [...]}}
+//
+// Tekrar denenebilecek hatalarda unlock_at alanı ve Retry-After başlığı da gönderilir.
+//
+// İstemciler mesaj metnine değil, code alanına göre davranmalıdır; mesaj
+// yalnızca kullanıcıya gösterilmek içindir ve değişebilir.
+package apierr
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Code, bir hatanın istemciler tarafından karşılaştırılabilen sabit kodudur.
+// Mevcut kodların anlamı değiştirilmemeli, yalnızca yenileri eklenmelidir.
+type Code string
+
+const (
+	// Genel hatalar
+	CodeInternal         Code = "INTERNAL_ERROR"
+	CodeTimeout          Code = "TIMEOUT"
+	CodeBadRequest       Code = "BAD_REQUEST"
+	CodeInvalidBody      Code = "INVALID_BODY"
+	CodeBodyTooLarge     Code = "BODY_TOO_LARGE"
+	CodeValidation       Code = "VALIDATION_FAILED"
+	CodeInvalidID        Code = "INVALID_ID"
+	CodeInvalidCursor    Code = "INVALID_CURSOR"
+	CodeNotFound         Code = "NOT_FOUND"
+	CodeRouteNotFound    Code = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed Code = "METHOD_NOT_ALLOWED"
+	CodeTooManyRequests  Code = "TOO_MANY_REQUESTS"
+	CodeLoginThrottled   Code = "LOGIN_THROTTLED"
+	CodeAccountLocked    Code = "ACCOUNT_LOCKED"
+
+	// Kimlik doğrulama ve yetkilendirme
+	CodeUnauthenticated    Code = "UNAUTHENTICATED"
+	CodeSessionExpired     Code = "SESSION_EXPIRED"
+	CodeSessionInvalid     Code = "SESSION_INVALID"
+	CodeInvalidCredentials Code = "INVALID_CREDENTIALS"
+	CodeTokenInvalid       Code = "TOKEN_INVALID"
+	CodeTokenExpired       Code = "TOKEN_EXPIRED"
+	CodeRefreshInvalid     Code = "REFRESH_TOKEN_INVALID"
+	CodeAPIKeyInvalid      Code = "API_KEY_INVALID"
+	CodeInsufficientScope  Code = "INSUFFICIENT_SCOPE"
+	CodeResetTokenInvalid  Code = "RESET_TOKEN_INVALID"
+	CodeVerifyTokenInvalid Code = "VERIFICATION_TOKEN_INVALID"
+	CodeEmailNotVerified   Code = "EMAIL_NOT_VERIFIED"
+	CodeEmailChangeInvalid Code = "EMAIL_CHANGE_TOKEN_INVALID"
+	CodeTwoFactorInvalid   Code = "TWO_FACTOR_CODE_INVALID"
+	CodeTwoFactorChallenge Code = "TWO_FACTOR_CHALLENGE_INVALID"
+	CodeTwoFactorRequired  Code = "TWO_FACTOR_REQUIRED"
+	CodeForbidden          Code = "FORBIDDEN"
+	CodeAdminRequired      Code = "ADMIN_REQUIRED"
+	CodePermissionDenied   Code = "PERMISSION_DENIED"
+
+	// Harici sağlayıcıyla (OIDC) giriş
+	CodeOIDCStateInvalid    Code = "OIDC_STATE_INVALID"
+	CodeOIDCDenied          Code = "OIDC_DENIED"
+	CodeOIDCProviderError   Code = "OIDC_PROVIDER_ERROR"
+	CodeOIDCEmailUnverified Code = "OIDC_EMAIL_UNVERIFIED"
+	CodeOIDCLinkConflict    Code = "OIDC_LINK_CONFLICT"
+
+	// Kullanıcılar
+	CodeUserNotFound        Code = "USER_NOT_FOUND"
+	CodeUserAlreadyExists   Code = "USER_ALREADY_EXISTS"
+	CodeUsernameTaken       Code = "USERNAME_TAKEN"
+	CodeAPIKeyNotFound      Code = "API_KEY_NOT_FOUND"
+	CodeSessionNotFound     Code = "SESSION_NOT_FOUND"
+	CodeAPIKeyExists        Code = "API_KEY_ALREADY_EXISTS"
+	CodeAlreadyVerified     Code = "EMAIL_ALREADY_VERIFIED"
+	CodeTwoFactorEnabled    Code = "TWO_FACTOR_ALREADY_ENABLED"
+	CodeTwoFactorNotEnabled Code = "TWO_FACTOR_NOT_ENABLED"
+
+	// Roller ve izinler
+	CodeRoleNotFound Code = "ROLE_NOT_FOUND"
+	CodeRoleExists   Code = "ROLE_ALREADY_EXISTS"
+	CodeRoleBuiltIn  Code = "ROLE_BUILT_IN"
+
+	// Şarkılar, sanatçılar, albümler ve çalma listeleri
+	CodeSongNotFound          Code = "SONG_NOT_FOUND"
+	CodePlaylistNotFound      Code = "PLAYLIST_NOT_FOUND"
+	CodePlaylistLimitReached  Code = "PLAYLIST_LIMIT_REACHED"
+	CodeSongAlreadyInPlaylist Code = "SONG_ALREADY_IN_PLAYLIST"
+	CodeArtistNotFound        Code = "ARTIST_NOT_FOUND"
+	CodeAlbumNotFound         Code = "ALBUM_NOT_FOUND"
+	CodeAlbumExists           Code = "ALBUM_ALREADY_EXISTS"
+
+	// Kuponlar ve premium üyelik
+	CodeCouponNotFound        Code = "COUPON_NOT_FOUND"
+	CodeCouponAlreadyExists   Code = "COUPON_ALREADY_EXISTS"
+	CodeCouponAlreadyUsed     Code = "COUPON_ALREADY_USED"
+	CodeAlreadyPremium        Code = "ALREADY_PREMIUM"
+	CodePurchaseWindowExpired Code = "PURCHASE_WINDOW_EXPIRED"
+	CodeInsufficientFunds     Code = "INSUFFICIENT_FUNDS"
+)
+
+// statuses, her kodun döndüğü HTTP durum kodudur. Listede olmayan kodlar 500 döner.
+var statuses = map[Code]int{
+	CodeInternal:         fiber.StatusInternalServerError,
+	CodeTimeout:          fiber.StatusGatewayTimeout,
+	CodeBadRequest:       fiber.StatusBadRequest,
+	CodeInvalidBody:      fiber.StatusBadRequest,
+	CodeBodyTooLarge:     fiber.StatusRequestEntityTooLarge,
+	CodeValidation:       fiber.StatusBadRequest,
+	CodeInvalidID:        fiber.StatusBadRequest,
+	CodeInvalidCursor:    fiber.StatusBadRequest,
+	CodeNotFound:         fiber.StatusNotFound,
+	CodeRouteNotFound:    fiber.StatusNotFound,
+	CodeMethodNotAllowed: fiber.StatusMethodNotAllowed,
+	CodeTooManyRequests:  fiber.StatusTooManyRequests,
+	CodeLoginThrottled:   fiber.StatusTooManyRequests,
+	CodeAccountLocked:    fiber.StatusTooManyRequests,
+
+	CodeUnauthenticated:    fiber.StatusUnauthorized,
+	CodeSessionExpired:     fiber.StatusUnauthorized,
+	CodeSessionInvalid:     fiber.StatusUnauthorized,
+	CodeInvalidCredentials: fiber.StatusUnauthorized,
+	CodeTokenInvalid:       fiber.StatusUnauthorized,
+	CodeTokenExpired:       fiber.StatusUnauthorized,
+	CodeRefreshInvalid:     fiber.StatusUnauthorized,
+	CodeAPIKeyInvalid:      fiber.StatusUnauthorized,
+	CodeInsufficientScope:  fiber.StatusForbidden,
+	CodeResetTokenInvalid:  fiber.StatusBadRequest,
+	CodeVerifyTokenInvalid: fiber.StatusBadRequest,
+	CodeEmailNotVerified:   fiber.StatusForbidden,
+	CodeEmailChangeInvalid: fiber.StatusBadRequest,
+	CodeTwoFactorInvalid:   fiber.StatusUnauthorized,
+	CodeTwoFactorChallenge: fiber.StatusUnauthorized,
+	CodeTwoFactorRequired:  fiber.StatusForbidden,
+	CodeForbidden:          fiber.StatusForbidden,
+	CodeAdminRequired:      fiber.StatusForbidden,
+	CodePermissionDenied:   fiber.StatusForbidden,
+
+	CodeOIDCStateInvalid:    fiber.StatusBadRequest,
+	CodeOIDCDenied:          fiber.StatusBadRequest,
+	CodeOIDCProviderError:   fiber.StatusBadGateway,
+	CodeOIDCEmailUnverified: fiber.StatusForbidden,
+	CodeOIDCLinkConflict:    fiber.StatusConflict,
+
+	CodeUserNotFound:        fiber.StatusNotFound,
+	CodeUserAlreadyExists:   fiber.StatusConflict,
+	CodeUsernameTaken:       fiber.StatusConflict,
+	CodeAPIKeyNotFound:      fiber.StatusNotFound,
+	CodeSessionNotFound:     fiber.StatusNotFound,
+	CodeAPIKeyExists:        fiber.StatusConflict,
+	CodeAlreadyVerified:     fiber.StatusConflict,
+	CodeTwoFactorEnabled:    fiber.StatusConflict,
+	CodeTwoFactorNotEnabled: fiber.StatusConflict,
+
+	CodeRoleNotFound: fiber.StatusNotFound,
+	CodeRoleExists:   fiber.StatusConflict,
+	CodeRoleBuiltIn:  fiber.StatusConflict,
+
+	CodeSongNotFound:          fiber.StatusNotFound,
+	CodePlaylistNotFound:      fiber.StatusNotFound,
+	CodePlaylistLimitReached:  fiber.StatusForbidden,
+	CodeSongAlreadyInPlaylist: fiber.StatusConflict,
+	CodeArtistNotFound:        fiber.StatusNotFound,
+	CodeAlbumNotFound:         fiber.StatusNotFound,
+	CodeAlbumExists:           fiber.StatusConflict,
+
+	CodeCouponNotFound:        fiber.StatusNotFound,
+	CodeCouponAlreadyExists:   fiber.StatusConflict,
+	CodeCouponAlreadyUsed:     fiber.StatusBadRequest,
+	CodeAlreadyPremium:        fiber.StatusBadRequest,
+	CodePurchaseWindowExpired: fiber.StatusBadRequest,
+	CodeInsufficientFunds:     fiber.StatusBadRequest,
+}
+
+// Status, kodun karşılık geldiği HTTP durum kodunu döndürür.
+func (c Code) Status() int {
+	if status, ok := statuses[c]; ok {
+		return status
+	}
+	return fiber.StatusInternalServerError
+}
+
+// FieldError, istek gövdesindeki veya sorgu parametrelerindeki tek bir alanın hatasını tanımlar.
+// Message, yanıt yazılırken Key ve Args'tan isteğin dilinde üretilir.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Key     string `json:"-"`
+	Args    []any  `json:"-"`
+	Message string `json:"message"`
+}
+
+// Error, handler'ların döndürdüğü yapılandırılmış API hatasıdır.
+// Handler'lar bu hatayı doğrudan return eder; yanıtı ErrorHandler yazar.
+// Key, i18n kataloğundaki mesaj anahtarıdır; Message yanıt yazılırken doldurulur.
+type Error struct {
+	Code    Code         `json:"code"`
+	Key     string       `json:"-"`
+	Args    []any        `json:"-"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+	// UnlockAt, istemcinin isteği en erken tekrar deneyebileceği andır (örneğin hesap kilidinin kalkışı).
+	UnlockAt *time.Time `json:"unlock_at,omitempty"`
+}
+
+// New, verilen kod ve mesaj anahtarıyla bir hata oluşturur. args, mesaj
+// kalıbındaki biçimlendirme yerlerini doldurur.
+func New(code Code, key string, args ...any) *Error {
+	return &Error{Code: code, Key: key, Args: args}
+}
+
+// Validation, alan bazlı hatalarla birlikte bir VALIDATION_FAILED hatası oluşturur.
+func Validation(details ...FieldError) *Error {
+	return &Error{Code: CodeValidation, Key: "validation.failed", Details: details}
+}
+
+// WithDetails, hataya alan bazlı ayrıntılar ekler.
+func (e *Error) WithDetails(details ...FieldError) *Error {
+	e.Details = append(e.Details, details...)
+	return e
+}
+
+// WithUnlockAt, hataya isteğin tekrar denenebileceği anı ekler. Yanıtta Retry-After başlığı da gönderilir.
+func (e *Error) WithUnlockAt(t time.Time) *Error {
+	e.UnlockAt = &t
+	return e
+}
+
+// Status, hatanın HTTP durum kodunu döndürür.
+func (e *Error) Status() int {
+	return e.Code.Status()
+}
+
+func (e *Error) Error() string {
+	return string(e.Code) + ": " + e.Key
+}
@@ -0,0 +1,83 @@
+package apierr
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"spoti/i18n"
+)
+
+// response, hata yanıtının JSON gövdesidir.
+type response struct {
+	Error *Error `json:"error"`
+}
+
+// Handler, fiber.Config.ErrorHandler olarak kullanılır. Handler'ların döndürdüğü
+// *Error değerlerini, Fiber'in kendi hatalarını (eşleşmeyen rota, çok büyük gövde vb.),
+// recover middleware'ının yakaladığı panikleri ve beklenmeyen diğer hataları aynı
+// biçimde yanıtlar. Beklenmeyen hatalar loglanır ama ayrıntıları istemciye gösterilmez.
+func Handler(c *fiber.Ctx, err error) error {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		apiErr = convert(err)
+		if apiErr.Status() >= fiber.StatusInternalServerError {
+			log.Printf("%s %s: beklenmeyen hata: %v\n", c.Method(), c.OriginalURL(), err)
+		}
+	}
+
+	if apiErr.UnlockAt != nil {
+		seconds := int(math.Ceil(time.Until(*apiErr.UnlockAt).Seconds()))
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(seconds, 1)))
+	}
+
+	return c.Status(apiErr.Status()).JSON(response{Error: localize(apiErr, i18n.FromCtx(c))})
+}
+
+// localize, hatanın ve alan hatalarının mesajlarını verilen dilde dolduran bir kopya döndürür.
+func localize(e *Error, lang i18n.Lang) *Error {
+	out := *e
+	if out.Key != "" {
+		out.Message = i18n.Translate(lang, out.Key, out.Args...)
+	}
+	if len(e.Details) > 0 {
+		out.Details = make([]FieldError, len(e.Details))
+		for i, d := range e.Details {
+			if d.Key != "" {
+				d.Message = i18n.Translate(lang, d.Key, d.Args...)
+			}
+			out.Details[i] = d
+		}
+	}
+	return &out
+}
+
+// convert, *Error olmayan bir hatayı en yakın API hatasına dönüştürür.
+func convert(err error) *Error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return New(CodeTimeout, "common.timeout")
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		switch {
+		case fiberErr.Code == fiber.StatusNotFound:
+			return New(CodeRouteNotFound, "request.route_not_found")
+		case fiberErr.Code == fiber.StatusMethodNotAllowed:
+			return New(CodeMethodNotAllowed, "request.method_not_allowed")
+		case fiberErr.Code == fiber.StatusRequestEntityTooLarge:
+			return New(CodeBodyTooLarge, "request.body_too_large")
+		case fiberErr.Code == fiber.StatusUnprocessableEntity:
+			return New(CodeInvalidBody, "request.invalid_body")
+		case fiberErr.Code >= 400 && fiberErr.Code < 500:
+			return New(CodeBadRequest, "request.bad_request")
+		}
+	}
+
+	return New(CodeInternal, "common.internal_error")
+}
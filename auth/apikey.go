@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+)
+
+// API anahtarı kapsamları. Session veya erişim token'ıyla gelen isteklerde kapsam
+// kontrolü yapılmaz; kapsamlar yalnızca API anahtarlarını sınırlar.
+const (
+	ScopePlaylistsRead  = "playlists:read"
+	ScopePlaylistsWrite = "playlists:write"
+	ScopeSongsRead      = "songs:read"
+	// ScopeAdminAll, tüm admin uçlarına erişim verir; yalnızca admin kullanıcılar alabilir.
+	ScopeAdminAll = "admin:*"
+)
+
+// apiKeyPrefix, API anahtarlarını erişim token'larından ayırt etmeye yarar.
+const apiKeyPrefix = "spk_"
+
+// apiKeyDisplayLength, listelemede gösterilen ve anahtarı tanımaya yetecek baş kısmın uzunluğudur.
+const apiKeyDisplayLength = 12
+
+// IsAPIKey, Bearer başlığındaki değerin bir API anahtarı olup olmadığını bildirir.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyPrefix)
+}
+
+// NewAPIKey, yeni bir API anahtarı, listelemede gösterilecek baş kısmı ve
+// veritabanında saklanacak hash'ini üretir. Anahtarın kendisi yalnızca bir kez gösterilir.
+func NewAPIKey() (key, prefix, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", err
+	}
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return key, key[:apiKeyDisplayLength], HashToken(key), nil
+}
+
+// HasScope, verilen kapsamların required kapsamını içerip içermediğini döndürür.
+// "kaynak:*" biçimindeki bir kapsam, aynı kaynağın tüm kapsamlarını karşılar.
+func HasScope(granted []string, required string) bool {
+	resource, _, _ := strings.Cut(required, ":")
+	for _, scope := range granted {
+		if scope == required || scope == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
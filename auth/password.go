@@ -0,0 +1,77 @@
+// Package auth, kimlik doğrulamayla ilgili ortak yardımcıları içerir.
+package auth
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashPassword, şifreyi bcrypt ile hash'ler.
+func HashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// CheckPassword, şifrenin verilen bcrypt hash'iyle eşleşip eşleşmediğini döndürür.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// Şifre kuralları; PasswordPolicy.Check ihlal edilen kuralları bu adlarla döndürür.
+const (
+	PasswordRuleMinLength = "min_length"
+	PasswordRuleUpper     = "upper"
+	PasswordRuleLower     = "lower"
+	PasswordRuleDigit     = "digit"
+	PasswordRuleSymbol    = "symbol"
+)
+
+// PasswordPolicy, yeni şifrelerin uyması gereken kuralları tutar.
+// Uzunluk karakter olarak sayılır; bcrypt'in 72 baytlık sınırı istek doğrulamasında uygulanır.
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// Check, şifrenin ihlal ettiği kuralları döndürür; şifre politikaya uyuyorsa boş döner.
+func (p PasswordPolicy) Check(password string) []string {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	var violations []string
+	if utf8.RuneCountInString(password) < p.MinLength {
+		violations = append(violations, PasswordRuleMinLength)
+	}
+	if p.RequireUpper && !upper {
+		violations = append(violations, PasswordRuleUpper)
+	}
+	if p.RequireLower && !lower {
+		violations = append(violations, PasswordRuleLower)
+	}
+	if p.RequireDigit && !digit {
+		violations = append(violations, PasswordRuleDigit)
+	}
+	if p.RequireSymbol && !symbol {
+		violations = append(violations, PasswordRuleSymbol)
+	}
+	return violations
+}
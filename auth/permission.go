@@ -0,0 +1,46 @@
+package auth
+
+import "slices"
+
+// Rol izinleri. Admin uçlarının her biri bu izinlerden birini ister; bir role verilen izinler
+// t_role_permissions tablosunda tutulur. Yeni bir izin eklendiğinde t_permissions tablosuna da
+// bir migration ile eklenmelidir.
+const (
+	PermSongsWrite   = "songs:write"
+	PermUsersRead    = "users:read"
+	PermUsersWrite   = "users:write"
+	PermCouponsWrite = "coupons:write"
+	PermRolesManage  = "roles:manage"
+	PermSystemRead   = "system:read"
+)
+
+// Permissions, tanımlı tüm izinlerdir.
+var Permissions = []string{
+	PermSongsWrite,
+	PermUsersRead,
+	PermUsersWrite,
+	PermCouponsWrite,
+	PermRolesManage,
+	PermSystemRead,
+}
+
+// Yerleşik roller; migration'la oluşturulur ve izinleri API üzerinden değiştirilemez.
+const (
+	RoleAdmin         = "admin"
+	RoleUser          = "user"
+	RoleContentEditor = "content-editor"
+	RoleSupport       = "support"
+)
+
+// BuiltInRoles, yerleşik rolleri ve izinlerini migration'daki tohum verisiyle aynı biçimde tutar.
+var BuiltInRoles = map[string][]string{
+	RoleAdmin:         Permissions,
+	RoleUser:          nil,
+	RoleContentEditor: {PermSongsWrite},
+	RoleSupport:       {PermUsersRead, PermUsersWrite},
+}
+
+// IsPermission, verilen adın tanımlı bir izin olup olmadığını döndürür.
+func IsPermission(name string) bool {
+	return slices.Contains(Permissions, name)
+}
@@ -0,0 +1,9 @@
+package auth
+
+// E-posta adresini doğrulamamış hesaplara kapatılabilecek işlemler.
+// Hangilerinin kapalı olduğu auth.unverified_restrictions ayarıyla belirlenir.
+const (
+	RestrictPremium        = "premium"
+	RestrictPlaylistCreate = "playlist_create"
+	RestrictAPIKeys        = "api_keys"
+)
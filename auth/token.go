@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Token doğrulama hataları. Middleware bunları farklı hata kodlarına çevirir.
+var (
+	ErrTokenExpired = errors.New("token süresi dolmuş")
+	ErrTokenInvalid = errors.New("token geçersiz")
+)
+
+// tokenIssuer, erişim token'larının "iss" alanına yazılır ve doğrulamada beklenir.
+const tokenIssuer = "spoti"
+
+// AccessClaims, erişim token'ının taşıdığı alanlardır. Kullanıcı ID'si "sub" alanındadır.
+type AccessClaims struct {
+	// Lang, token verildiği andaki dil tercihidir; boşsa Accept-Language kullanılır.
+	Lang string `json:"lang,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// UserID, "sub" alanındaki kullanıcı ID'sini döndürür.
+func (c *AccessClaims) UserID() (uuid.UUID, error) {
+	return uuid.Parse(c.Subject)
+}
+
+// TokenManager, kısa ömürlü imzalı erişim token'larını ve opak yenileme token'larını üretir.
+// Erişim token'ları durumsuzdur; yenileme token'ları ise hash'leri saklanarak sunucu
+// tarafında iptal edilebilir.
+type TokenManager struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokenManager, verilen gizli anahtar ve sürelerle bir TokenManager oluşturur.
+func NewTokenManager(secret string, accessTTL, refreshTTL time.Duration) *TokenManager {
+	return &TokenManager{secret: []byte(secret), accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+// AccessTTL, erişim token'larının geçerlilik süresini döndürür.
+func (m *TokenManager) AccessTTL() time.Duration { return m.accessTTL }
+
+// RefreshTTL, yenileme token'larının geçerlilik süresini döndürür.
+func (m *TokenManager) RefreshTTL() time.Duration { return m.refreshTTL }
+
+// IssueAccess, kullanıcı için HS256 ile imzalanmış bir erişim token'ı üretir.
+func (m *TokenManager) IssueAccess(userID uuid.UUID, lang string) (string, error) {
+	now := time.Now()
+	claims := AccessClaims{
+		Lang: lang,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   userID.String(),
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.accessTTL)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+}
+
+// ParseAccess, erişim token'ının imzasını ve süresini doğrular.
+// Süresi dolmuş token için ErrTokenExpired, diğer tüm sorunlar için ErrTokenInvalid döner.
+func (m *TokenManager) ParseAccess(token string) (*AccessClaims, error) {
+	claims := new(AccessClaims)
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrTokenExpired
+	}
+	if err != nil {
+		return nil, ErrTokenInvalid
+	}
+	if _, err := claims.UserID(); err != nil || claims.IssuedAt == nil {
+		return nil, ErrTokenInvalid
+	}
+	return claims, nil
+}
+
+// NewRefreshToken, istemciye verilecek rastgele bir yenileme token'ı ile
+// veritabanında saklanacak hash'ini üretir.
+func (m *TokenManager) NewRefreshToken() (token, hash string, err error) {
+	return NewOpaqueToken()
+}
+
+// NewOpaqueToken, yenileme ve e-posta bağlantıları gibi tek başına anlam taşımayan
+// rastgele bir token ile saklanacak hash'ini üretir.
+func NewOpaqueToken() (token, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashToken(token), nil
+}
+
+// HashToken, opak bir token'ın saklanacak SHA-256 özetini döndürür.
+// Token'lar yüksek entropili olduğundan bcrypt gibi yavaş bir hash gerekmez.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
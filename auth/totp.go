@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP ayarları RFC 6238'in varsayılanlarıdır; yaygın doğrulayıcı uygulamaların
+// hepsi bu değerleri destekler.
+const (
+	totpIssuer = "Spoti"
+	totpDigits = 6
+	totpPeriod = 30
+	// totpSkew, saat kaymasını tolere etmek için önceki ve sonraki kaç adımın kabul edileceğidir.
+	totpSkew = 1
+)
+
+// RecoveryCodeCount, iki adımlı doğrulama etkinleştirildiğinde üretilen kurtarma kodu sayısıdır.
+const RecoveryCodeCount = 10
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTOTPSecret, doğrulayıcı uygulamaya girilecek 160 bitlik base32 bir gizli anahtar üretir.
+func NewTOTPSecret() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base32NoPadding.EncodeToString(buf), nil
+}
+
+// TOTPURI, doğrulayıcı uygulamaların QR kod olarak okuyabildiği otpauth:// adresini üretir.
+func TOTPURI(account, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", totpIssuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(totpDigits))
+	v.Set("period", fmt.Sprint(totpPeriod))
+	label := url.PathEscape(totpIssuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + v.Encode()
+}
+
+// IsTOTPCode, kodun doğrulayıcı uygulamadan gelen 6 haneli bir kod olup olmadığını bildirir.
+// Diğer kodlar kurtarma kodu olarak değerlendirilir.
+func IsTOTPCode(code string) bool {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != totpDigits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateTOTP, kodu now anındaki ve komşu zaman adımlarındaki kodlarla karşılaştırır.
+// Eşleşen adımı döndürür; aynı kodun tekrar kullanılmasını önlemek için çağıran bu adımı saklamalıdır.
+func ValidateTOTP(secret, code string, now time.Time) (int64, bool) {
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+	code = strings.ReplaceAll(code, " ", "")
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpCode, RFC 4226'daki HOTP algoritmasıyla verilen adımın kodunu hesaplar.
+func totpCode(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1_000_000)
+}
+
+// NewRecoveryCodes, kullanıcıya bir kez gösterilecek kurtarma kodlarını ve saklanacak hash'lerini üretir.
+// Kodlar "xxxxx-xxxxx" biçimindedir ve büyük/küçük harf duyarsızdır.
+func NewRecoveryCodes() (codes, hashes []string, err error) {
+	codes = make([]string, RecoveryCodeCount)
+	hashes = make([]string, RecoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(base32NoPadding.EncodeToString(buf))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode, kurtarma kodunu biçiminden bağımsız olarak saklanacak hash'e çevirir.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	return HashToken(code)
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret, RFC 6238 ek B'deki SHA1 test anahtarının ("12345678901234567890") base32 hâlidir.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeRFCVectors(t *testing.T) {
+	// RFC 6238 ek B'deki 8 haneli değerlerin son 6 hanesi.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	key, err := base32NoPadding.DecodeString(rfcSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/totpPeriod); got != tt.code {
+			t.Errorf("totpCode(%d) = %s, beklenen %s", tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := now.Unix() / totpPeriod
+	key, _ := base32NoPadding.DecodeString(rfcSecret)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		step   int64
+		ok     bool
+	}{
+		{"geçerli adım", rfcSecret, "005924", current, true},
+		{"önceki adım", rfcSecret, totpCode(key, current-1), current - 1, true},
+		{"sonraki adım", rfcSecret, totpCode(key, current+1), current + 1, true},
+		{"boşluklu kod", rfcSecret, "005 924", current, true},
+		{"küçük harfli anahtar", strings.ToLower(rfcSecret), "005924", current, true},
+		{"pencere dışı", rfcSecret, totpCode(key, current-2), 0, false},
+		{"yanlış kod", rfcSecret, "000000", 0, false},
+		{"geçersiz anahtar", "!!!", "005924", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(tt.secret, tt.code, now)
+			if ok != tt.ok || step != tt.step {
+				t.Fatalf("ValidateTOTP = (%d, %v), beklenen (%d, %v)", step, ok, tt.step, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNewTOTPSecret(t *testing.T) {
+	secret, err := NewTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := base32NoPadding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("gizli anahtar 160 bit değil: %q (%v)", secret, err)
+	}
+	now := time.Now()
+	if _, ok := ValidateTOTP(secret, totpCode(key, now.Unix()/totpPeriod), now); !ok {
+		t.Fatal("üretilen anahtarın kodu doğrulanmadı")
+	}
+}
+
+func TestIsTOTPCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"123456", true},
+		{"123 456", true},
+		{"12345", false},
+		{"1234567", false},
+		{"12a456", false},
+		{"abcde-fghij", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsTOTPCode(tt.code); got != tt.want {
+			t.Errorf("IsTOTPCode(%q) = %v, beklenen %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := NewRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != RecoveryCodeCount || len(hashes) != RecoveryCodeCount {
+		t.Fatalf("%d kod, %d hash üretildi", len(codes), len(hashes))
+	}
+
+	seen := make(map[string]bool)
+	for i, code := range codes {
+		if len(code) != 11 || code[5] != '-' || code != strings.ToLower(code) {
+			t.Errorf("kurtarma kodu biçimi hatalı: %q", code)
+		}
+		if IsTOTPCode(code) {
+			t.Errorf("kurtarma kodu TOTP kodu sanılıyor: %q", code)
+		}
+		if seen[code] {
+			t.Errorf("kurtarma kodu tekrarlandı: %q", code)
+		}
+		seen[code] = true
+
+		// Hash, kullanıcının kodu nasıl yazdığından bağımsızdır.
+		raw := strings.ReplaceAll(code, "-", "")
+		for _, variant := range []string{code, strings.ToUpper(code), raw, raw[:5] + " " + raw[5:]} {
+			if HashRecoveryCode(variant) != hashes[i] {
+				t.Errorf("HashRecoveryCode(%q) saklanan hash'le eşleşmedi", variant)
+			}
+		}
+	}
+}
@@ -0,0 +1,532 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"spoti/i18n"
+)
+
+// Config, uygulamanın çalışma zamanı ayarlarını tutar.
+// Değerler sırasıyla varsayılanlardan, isteğe bağlı yapılandırma dosyasından
+// ve ortam değişkenlerinden yüklenir; sonradan gelen kaynak öncekini ezer.
+type Config struct {
+	Server   ServerConfig   `yaml:"server" toml:"server"`
+	Database DatabaseConfig `yaml:"database" toml:"database"`
+	Redis    RedisConfig    `yaml:"redis" toml:"redis"`
+	Auth     AuthConfig     `yaml:"auth" toml:"auth"`
+	Mail     MailConfig     `yaml:"mail" toml:"mail"`
+	Login    LoginConfig    `yaml:"login" toml:"login"`
+	OIDC     OIDCConfig     `yaml:"oidc" toml:"oidc"`
+	Search   SearchConfig   `yaml:"search" toml:"search"`
+}
+
+// ServerConfig, HTTP sunucusunun ayarlarını tutar.
+type ServerConfig struct {
+	Addr string `yaml:"addr" toml:"addr" env:"SPOTI_SERVER_ADDR"`
+	// RequestTimeout, bir isteğin veritabanı işlemleri için tanınan en uzun süredir.
+	RequestTimeout time.Duration `yaml:"request_timeout" toml:"request_timeout" env:"SPOTI_SERVER_REQUEST_TIMEOUT"`
+	// ShutdownTimeout, kapanma sinyalinden sonra devam eden isteklerin bitmesi için beklenen en uzun süredir.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SPOTI_SERVER_SHUTDOWN_TIMEOUT"`
+	// DefaultLanguage, Accept-Language başlığı ve kullanıcı tercihi yoksa yanıtlarda kullanılan dildir.
+	DefaultLanguage string `yaml:"default_language" toml:"default_language" env:"SPOTI_SERVER_DEFAULT_LANGUAGE"`
+	// PublicURL, e-postalardaki bağlantılarda kullanılan ve istemcinin uygulamaya eriştiği adrestir.
+	PublicURL string `yaml:"public_url" toml:"public_url" env:"SPOTI_SERVER_PUBLIC_URL"`
+	// ProxyHeader, sunucu bir ters vekilin arkasındaysa istemcinin IP adresinin okunduğu başlıktır
+	// (ör. X-Real-IP). Boşsa bağlantının karşı ucunun adresi kullanılır. Giriş kilitleri ve session
+	// listesi bu adresi kullandığından başlık yalnızca TrustedProxies'ten gelen isteklerde okunur.
+	ProxyHeader string `yaml:"proxy_header" toml:"proxy_header" env:"SPOTI_SERVER_PROXY_HEADER"`
+	// TrustedProxies, ProxyHeader'ına güvenilen vekillerin IP adresleri veya CIDR aralıklarıdır.
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies" env:"SPOTI_SERVER_TRUSTED_PROXIES"`
+}
+
+// DatabaseConfig, PostgreSQL bağlantı ayarlarını tutar.
+// DSN doluysa diğer alanlar yok sayılır.
+type DatabaseConfig struct {
+	DSN      string `yaml:"dsn" toml:"dsn" env:"SPOTI_DB_DSN"`
+	Host     string `yaml:"host" toml:"host" env:"SPOTI_DB_HOST"`
+	Port     int    `yaml:"port" toml:"port" env:"SPOTI_DB_PORT"`
+	User     string `yaml:"user" toml:"user" env:"SPOTI_DB_USER"`
+	Password string `yaml:"password" toml:"password" env:"SPOTI_DB_PASSWORD"`
+	Name     string `yaml:"name" toml:"name" env:"SPOTI_DB_NAME"`
+	SSLMode  string `yaml:"sslmode" toml:"sslmode" env:"SPOTI_DB_SSLMODE"`
+
+	// Bağlantı havuzu ayarları.
+	MaxConns          int           `yaml:"max_conns" toml:"max_conns" env:"SPOTI_DB_MAX_CONNS"`
+	MinConns          int           `yaml:"min_conns" toml:"min_conns" env:"SPOTI_DB_MIN_CONNS"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" toml:"max_conn_lifetime" env:"SPOTI_DB_MAX_CONN_LIFETIME"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" toml:"max_conn_idle_time" env:"SPOTI_DB_MAX_CONN_IDLE_TIME"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" toml:"health_check_period" env:"SPOTI_DB_HEALTH_CHECK_PERIOD"`
+	// StatsInterval sıfırdan büyükse havuz istatistikleri bu aralıkla loglanır.
+	StatsInterval time.Duration `yaml:"stats_interval" toml:"stats_interval" env:"SPOTI_DB_STATS_INTERVAL"`
+
+	// AutoMigrate true ise sunucu açılırken bekleyen migration'lar uygulanır.
+	AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate" env:"SPOTI_DB_AUTO_MIGRATE"`
+}
+
+// RedisConfig, session store için kullanılan Redis bağlantı ayarlarını tutar.
+// URL doluysa diğer alanlar yok sayılır.
+type RedisConfig struct {
+	URL      string `yaml:"url" toml:"url" env:"SPOTI_REDIS_URL"`
+	Host     string `yaml:"host" toml:"host" env:"SPOTI_REDIS_HOST"`
+	Port     int    `yaml:"port" toml:"port" env:"SPOTI_REDIS_PORT"`
+	Username string `yaml:"username" toml:"username" env:"SPOTI_REDIS_USERNAME"`
+	Password string `yaml:"password" toml:"password" env:"SPOTI_REDIS_PASSWORD"`
+	Database int    `yaml:"database" toml:"database" env:"SPOTI_REDIS_DATABASE"`
+}
+
+// AuthConfig, bearer token kimlik doğrulamasının ayarlarını tutar.
+type AuthConfig struct {
+	// JWTSecret, erişim token'larını HMAC-SHA256 ile imzalamak için kullanılan gizli anahtardır.
+	JWTSecret string `yaml:"jwt_secret" toml:"jwt_secret" env:"SPOTI_AUTH_JWT_SECRET"`
+	// AccessTokenTTL, erişim token'larının geçerlilik süresidir.
+	AccessTokenTTL time.Duration `yaml:"access_token_ttl" toml:"access_token_ttl" env:"SPOTI_AUTH_ACCESS_TOKEN_TTL"`
+	// RefreshTokenTTL, yenileme token'larının geçerlilik süresidir; her yenilemede yeniden başlar.
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" toml:"refresh_token_ttl" env:"SPOTI_AUTH_REFRESH_TOKEN_TTL"`
+	// PasswordResetTTL, şifre sıfırlama bağlantılarının geçerlilik süresidir.
+	PasswordResetTTL time.Duration `yaml:"password_reset_ttl" toml:"password_reset_ttl" env:"SPOTI_AUTH_PASSWORD_RESET_TTL"`
+	// EmailVerificationTTL, e-posta doğrulama bağlantılarının geçerlilik süresidir.
+	EmailVerificationTTL time.Duration `yaml:"email_verification_ttl" toml:"email_verification_ttl" env:"SPOTI_AUTH_EMAIL_VERIFICATION_TTL"`
+	// VerificationResendInterval, bir kullanıcıya iki doğrulama e-postası arasında geçmesi gereken süredir.
+	VerificationResendInterval time.Duration `yaml:"verification_resend_interval" toml:"verification_resend_interval" env:"SPOTI_AUTH_VERIFICATION_RESEND_INTERVAL"`
+	// UnverifiedRestrictions, e-posta adresini doğrulamamış hesaplara kapatılan işlemlerdir
+	// (premium, playlist_create, api_keys).
+	UnverifiedRestrictions []string `yaml:"unverified_restrictions" toml:"unverified_restrictions" env:"SPOTI_AUTH_UNVERIFIED_RESTRICTIONS"`
+	// EmailChangeTTL, e-posta değişikliğini onaylama bağlantılarının geçerlilik süresidir.
+	EmailChangeTTL time.Duration `yaml:"email_change_ttl" toml:"email_change_ttl" env:"SPOTI_AUTH_EMAIL_CHANGE_TTL"`
+	// RoleCacheTTL, kullanıcıların rollerinin ve izinlerinin bellekte tutulduğu süredir. Diğer
+	// sunucu örneklerinde yapılan rol değişiklikleri en geç bu süre sonunda geçerli olur; 0 önbelleği kapatır.
+	RoleCacheTTL time.Duration `yaml:"role_cache_ttl" toml:"role_cache_ttl" env:"SPOTI_AUTH_ROLE_CACHE_TTL"`
+
+	// Şifre politikası; kayıt, şifre sıfırlama ve şifre değiştirmede uygulanır.
+	PasswordMinLength     int  `yaml:"password_min_length" toml:"password_min_length" env:"SPOTI_AUTH_PASSWORD_MIN_LENGTH"`
+	PasswordRequireUpper  bool `yaml:"password_require_upper" toml:"password_require_upper" env:"SPOTI_AUTH_PASSWORD_REQUIRE_UPPER"`
+	PasswordRequireLower  bool `yaml:"password_require_lower" toml:"password_require_lower" env:"SPOTI_AUTH_PASSWORD_REQUIRE_LOWER"`
+	PasswordRequireDigit  bool `yaml:"password_require_digit" toml:"password_require_digit" env:"SPOTI_AUTH_PASSWORD_REQUIRE_DIGIT"`
+	PasswordRequireSymbol bool `yaml:"password_require_symbol" toml:"password_require_symbol" env:"SPOTI_AUTH_PASSWORD_REQUIRE_SYMBOL"`
+}
+
+// MailConfig, kullanıcılara gönderilen e-postaların ayarlarını tutar.
+// Driver "smtp" ise SMTP alanları, "file" ise Dir kullanılır; "log" mesajları yalnızca loglar.
+type MailConfig struct {
+	Driver       string `yaml:"driver" toml:"driver" env:"SPOTI_MAIL_DRIVER"`
+	From         string `yaml:"from" toml:"from" env:"SPOTI_MAIL_FROM"`
+	Dir          string `yaml:"dir" toml:"dir" env:"SPOTI_MAIL_DIR"`
+	SMTPHost     string `yaml:"smtp_host" toml:"smtp_host" env:"SPOTI_MAIL_SMTP_HOST"`
+	SMTPPort     int    `yaml:"smtp_port" toml:"smtp_port" env:"SPOTI_MAIL_SMTP_PORT"`
+	SMTPUsername string `yaml:"smtp_username" toml:"smtp_username" env:"SPOTI_MAIL_SMTP_USERNAME"`
+	SMTPPassword string `yaml:"smtp_password" toml:"smtp_password" env:"SPOTI_MAIL_SMTP_PASSWORD"`
+}
+
+// LoginConfig, başarısız giriş denemelerine uygulanan gecikme ve kilit sınırlarını tutar.
+type LoginConfig struct {
+	// FreeAttempts, bir hesapta gecikme uygulanmadan kabul edilen başarısız deneme sayısıdır.
+	FreeAttempts int `yaml:"free_attempts" toml:"free_attempts" env:"SPOTI_LOGIN_FREE_ATTEMPTS"`
+	// MaxAttempts, hesabın geçici olarak kilitlendiği başarısız deneme sayısıdır.
+	MaxAttempts int `yaml:"max_attempts" toml:"max_attempts" env:"SPOTI_LOGIN_MAX_ATTEMPTS"`
+	// IPMaxAttempts, bir IP adresinden tüm hesaplara yapılan başarısız denemeler için kilit sınırıdır.
+	IPMaxAttempts int `yaml:"ip_max_attempts" toml:"ip_max_attempts" env:"SPOTI_LOGIN_IP_MAX_ATTEMPTS"`
+	// BaseDelay, FreeAttempts aşıldıktan sonraki ilk gecikmedir; her denemede iki katına çıkar.
+	BaseDelay time.Duration `yaml:"base_delay" toml:"base_delay" env:"SPOTI_LOGIN_BASE_DELAY"`
+	// MaxDelay, iki deneme arasındaki gecikmenin üst sınırıdır.
+	MaxDelay time.Duration `yaml:"max_delay" toml:"max_delay" env:"SPOTI_LOGIN_MAX_DELAY"`
+	// Window, son başarısız denemeden sonra sayacın sıfırlanması için geçmesi gereken süredir.
+	Window time.Duration `yaml:"window" toml:"window" env:"SPOTI_LOGIN_WINDOW"`
+	// LockDuration, sınır aşıldığında uygulanan kilidin süresidir.
+	LockDuration time.Duration `yaml:"lock_duration" toml:"lock_duration" env:"SPOTI_LOGIN_LOCK_DURATION"`
+}
+
+// OIDCConfig, harici bir OpenID Connect sağlayıcısıyla girişin ayarlarını tutar.
+// Issuer boşsa OIDC girişi kapalıdır. Sağlayıcının uç noktaları Issuer altındaki
+// /.well-known/openid-configuration belgesinden okunur.
+type OIDCConfig struct {
+	// Provider, sağlayıcının kısa adıdır (ör. keycloak); bağlı hesaplar bu adla saklanır ve
+	// değiştirilirse mevcut bağlantılar eşleşmez.
+	Provider     string `yaml:"provider" toml:"provider" env:"SPOTI_OIDC_PROVIDER"`
+	Issuer       string `yaml:"issuer" toml:"issuer" env:"SPOTI_OIDC_ISSUER"`
+	ClientID     string `yaml:"client_id" toml:"client_id" env:"SPOTI_OIDC_CLIENT_ID"`
+	ClientSecret string `yaml:"client_secret" toml:"client_secret" env:"SPOTI_OIDC_CLIENT_SECRET"`
+	// Scopes, yetkilendirme isteğinde istenen kapsamlardır; openid içermelidir.
+	Scopes []string `yaml:"scopes" toml:"scopes" env:"SPOTI_OIDC_SCOPES"`
+	// RedirectURL, sağlayıcının kullanıcıyı geri gönderdiği adrestir. Boşsa
+	// server.public_url + /api/auth/oidc/callback kullanılır.
+	RedirectURL string `yaml:"redirect_url" toml:"redirect_url" env:"SPOTI_OIDC_REDIRECT_URL"`
+}
+
+// SearchConfig, şarkı aramasının ayarlarını tutar.
+type SearchConfig struct {
+	// SuggestTimeout, otomatik tamamlama sorgusuna tanınan en uzun süredir. Süre aşılırsa
+	// istemci yazmaya devam ederken bekletilmez; öneri listesi boş döner.
+	SuggestTimeout time.Duration `yaml:"suggest_timeout" toml:"suggest_timeout" env:"SPOTI_SEARCH_SUGGEST_TIMEOUT"`
+}
+
+// Enabled, OIDC girişinin yapılandırılıp yapılandırılmadığını döndürür.
+func (o OIDCConfig) Enabled() bool {
+	return o.Issuer != ""
+}
+
+// minJWTSecretLength, HS256 anahtarının tahmin edilemeyecek kadar uzun olmasını sağlar.
+const minJWTSecretLength = 32
+
+// Default, docker-compose ortamıyla uyumlu varsayılan ayarları döndürür.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:            ":3000",
+			RequestTimeout:  10 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+			DefaultLanguage: "tr",
+			PublicURL:       "http://localhost:3000",
+		},
+		Database: DatabaseConfig{
+			Host:     "db",
+			Port:     5432,
+			User:     "postgres",
+			Password: "postgres",
+			Name:     "spoti",
+			SSLMode:  "disable",
+
+			MaxConns:          10,
+			MinConns:          0,
+			MaxConnLifetime:   time.Hour,
+			MaxConnIdleTime:   30 * time.Minute,
+			HealthCheckPeriod: time.Minute,
+		},
+		Redis: RedisConfig{
+			Host: "redis",
+			Port: 6379,
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:   15 * time.Minute,
+			RefreshTokenTTL:  30 * 24 * time.Hour,
+			PasswordResetTTL: time.Hour,
+
+			EmailVerificationTTL:       48 * time.Hour,
+			VerificationResendInterval: time.Minute,
+			UnverifiedRestrictions:     []string{"premium", "playlist_create"},
+			EmailChangeTTL:             24 * time.Hour,
+			RoleCacheTTL:               30 * time.Second,
+
+			PasswordMinLength: 8,
+		},
+		Mail: MailConfig{
+			Driver:   "log",
+			From:     "Spoti <no-reply@spoti.local>",
+			Dir:      "mail",
+			SMTPPort: 587,
+		},
+		Login: LoginConfig{
+			FreeAttempts:  3,
+			MaxAttempts:   10,
+			IPMaxAttempts: 50,
+			BaseDelay:     time.Second,
+			MaxDelay:      30 * time.Second,
+			Window:        15 * time.Minute,
+			LockDuration:  15 * time.Minute,
+		},
+		OIDC: OIDCConfig{
+			Provider: "oidc",
+			Scopes:   []string{"openid", "email", "profile"},
+		},
+		Search: SearchConfig{
+			SuggestTimeout: 200 * time.Millisecond,
+		},
+	}
+}
+
+// Load, yapılandırmayı varsayılanlar, dosya ve ortam değişkenlerinden yükler ve tüm komutların
+// ortak ayarlarını doğrular; sunucuya özgü ayarlar ValidateServe ile doğrulanır.
+// path boşsa SPOTI_CONFIG ortam değişkenine bakılır; o da boşsa dosya okunmaz.
+// Dönen hata *Error tipindedir ve tüm eksik/geçersiz anahtarları listeler.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		path = os.Getenv("SPOTI_CONFIG")
+	}
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	var problems []string
+	problems = append(problems, applyEnv(&cfg)...)
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, &Error{Problems: problems}
+	}
+
+	return &cfg, nil
+}
+
+// loadFile, dosya uzantısına göre YAML veya TOML formatındaki yapılandırmayı cfg üzerine okur.
+// Bilinmeyen anahtarlar, yazım hatalarının sessizce yutulmaması için hata kabul edilir.
+func loadFile(path string, cfg *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("yapılandırma dosyası açılamadı: %w", err)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(f)
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("%s okunamadı: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.NewDecoder(f).Decode(cfg)
+		if err != nil {
+			return fmt.Errorf("%s okunamadı: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, k := range undecoded {
+				keys[i] = k.String()
+			}
+			return &Error{Problems: []string{fmt.Sprintf("%s: bilinmeyen anahtarlar: %s", path, strings.Join(keys, ", "))}}
+		}
+	default:
+		return fmt.Errorf("desteklenmeyen yapılandırma dosyası uzantısı: %q (.yaml, .yml veya .toml olmalı)", filepath.Ext(path))
+	}
+
+	return nil
+}
+
+// validate, yüklenmiş yapılandırmadaki eksik veya geçersiz değerleri toplar. Yalnızca
+// sunucunun kullandığı kimlik doğrulama ayarları ValidateServe ile ayrıca doğrulanır.
+func (c *Config) validate() []string {
+	var problems []string
+
+	if c.Server.Addr == "" {
+		problems = append(problems, "server.addr (SPOTI_SERVER_ADDR) boş olamaz")
+	}
+	if c.Server.RequestTimeout <= 0 {
+		problems = append(problems, fmt.Sprintf("server.request_timeout (SPOTI_SERVER_REQUEST_TIMEOUT) pozitif olmalı, %s verildi", c.Server.RequestTimeout))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, fmt.Sprintf("server.shutdown_timeout (SPOTI_SERVER_SHUTDOWN_TIMEOUT) pozitif olmalı, %s verildi", c.Server.ShutdownTimeout))
+	}
+	if _, ok := i18n.Parse(c.Server.DefaultLanguage); !ok {
+		problems = append(problems, fmt.Sprintf("server.default_language (SPOTI_SERVER_DEFAULT_LANGUAGE) desteklenmiyor: %q", c.Server.DefaultLanguage))
+	}
+	if u, err := url.Parse(c.Server.PublicURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		problems = append(problems, fmt.Sprintf("server.public_url (SPOTI_SERVER_PUBLIC_URL) http(s) ile başlayan tam bir adres olmalı, %q verildi", c.Server.PublicURL))
+	}
+	// Güvenilen vekil listesi olmadan başlık her istemci tarafından taklit edilebilirdi.
+	if c.Server.ProxyHeader != "" && len(c.Server.TrustedProxies) == 0 {
+		problems = append(problems, "server.trusted_proxies (SPOTI_SERVER_TRUSTED_PROXIES) server.proxy_header kullanıldığında boş olamaz")
+	}
+	if c.Server.ProxyHeader == "" && len(c.Server.TrustedProxies) > 0 {
+		problems = append(problems, "server.proxy_header (SPOTI_SERVER_PROXY_HEADER) server.trusted_proxies kullanıldığında boş olamaz")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			problems = append(problems, fmt.Sprintf("server.trusted_proxies (SPOTI_SERVER_TRUSTED_PROXIES) IP adresi veya CIDR aralığı içermeli, %q verildi", proxy))
+		}
+	}
+
+	if c.Database.DSN == "" {
+		if c.Database.Host == "" {
+			problems = append(problems, "database.host (SPOTI_DB_HOST) boş olamaz")
+		}
+		if c.Database.Port < 1 || c.Database.Port > 65535 {
+			problems = append(problems, fmt.Sprintf("database.port (SPOTI_DB_PORT) 1-65535 aralığında olmalı, %d verildi", c.Database.Port))
+		}
+		if c.Database.User == "" {
+			problems = append(problems, "database.user (SPOTI_DB_USER) boş olamaz")
+		}
+		if c.Database.Name == "" {
+			problems = append(problems, "database.name (SPOTI_DB_NAME) boş olamaz")
+		}
+		switch c.Database.SSLMode {
+		case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+		default:
+			problems = append(problems, fmt.Sprintf("database.sslmode (SPOTI_DB_SSLMODE) geçersiz: %q", c.Database.SSLMode))
+		}
+	}
+	if c.Database.MaxConns < 1 {
+		problems = append(problems, fmt.Sprintf("database.max_conns (SPOTI_DB_MAX_CONNS) en az 1 olmalı, %d verildi", c.Database.MaxConns))
+	}
+	if c.Database.MinConns < 0 || c.Database.MinConns > c.Database.MaxConns {
+		problems = append(problems, fmt.Sprintf("database.min_conns (SPOTI_DB_MIN_CONNS) 0 ile max_conns arasında olmalı, %d verildi", c.Database.MinConns))
+	}
+	if c.Database.MaxConnLifetime < 0 || c.Database.MaxConnIdleTime < 0 || c.Database.HealthCheckPeriod < 0 || c.Database.StatsInterval < 0 {
+		problems = append(problems, "database.max_conn_lifetime, max_conn_idle_time, health_check_period ve stats_interval negatif olamaz")
+	}
+
+	if c.Redis.URL == "" {
+		if c.Redis.Host == "" {
+			problems = append(problems, "redis.host (SPOTI_REDIS_HOST) boş olamaz")
+		}
+		if c.Redis.Port < 1 || c.Redis.Port > 65535 {
+			problems = append(problems, fmt.Sprintf("redis.port (SPOTI_REDIS_PORT) 1-65535 aralığında olmalı, %d verildi", c.Redis.Port))
+		}
+	}
+	if c.Redis.Database < 0 {
+		problems = append(problems, fmt.Sprintf("redis.database (SPOTI_REDIS_DATABASE) negatif olamaz, %d verildi", c.Redis.Database))
+	}
+
+	if _, err := mail.ParseAddress(c.Mail.From); err != nil {
+		problems = append(problems, fmt.Sprintf("mail.from (SPOTI_MAIL_FROM) geçerli bir adres olmalı, %q verildi", c.Mail.From))
+	}
+	switch c.Mail.Driver {
+	case "log":
+	case "file":
+		if c.Mail.Dir == "" {
+			problems = append(problems, "mail.dir (SPOTI_MAIL_DIR) file sürücüsü için boş olamaz")
+		}
+	case "smtp":
+		if c.Mail.SMTPHost == "" {
+			problems = append(problems, "mail.smtp_host (SPOTI_MAIL_SMTP_HOST) smtp sürücüsü için boş olamaz")
+		}
+		if c.Mail.SMTPPort < 1 || c.Mail.SMTPPort > 65535 {
+			problems = append(problems, fmt.Sprintf("mail.smtp_port (SPOTI_MAIL_SMTP_PORT) 1-65535 aralığında olmalı, %d verildi", c.Mail.SMTPPort))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("mail.driver (SPOTI_MAIL_DRIVER) log, file veya smtp olmalı, %q verildi", c.Mail.Driver))
+	}
+
+	if c.Login.FreeAttempts < 0 {
+		problems = append(problems, fmt.Sprintf("login.free_attempts (SPOTI_LOGIN_FREE_ATTEMPTS) negatif olamaz, %d verildi", c.Login.FreeAttempts))
+	}
+	if c.Login.MaxAttempts <= c.Login.FreeAttempts {
+		problems = append(problems, fmt.Sprintf("login.max_attempts (SPOTI_LOGIN_MAX_ATTEMPTS) free_attempts değerinden büyük olmalı, %d verildi", c.Login.MaxAttempts))
+	}
+	if c.Login.IPMaxAttempts < c.Login.MaxAttempts {
+		problems = append(problems, fmt.Sprintf("login.ip_max_attempts (SPOTI_LOGIN_IP_MAX_ATTEMPTS) max_attempts değerinden küçük olamaz, %d verildi", c.Login.IPMaxAttempts))
+	}
+	if c.Login.BaseDelay <= 0 || c.Login.MaxDelay < c.Login.BaseDelay {
+		problems = append(problems, "login.base_delay (SPOTI_LOGIN_BASE_DELAY) pozitif, login.max_delay (SPOTI_LOGIN_MAX_DELAY) en az base_delay kadar olmalı")
+	}
+	if c.Login.Window <= 0 || c.Login.LockDuration <= 0 {
+		problems = append(problems, "login.window (SPOTI_LOGIN_WINDOW) ve login.lock_duration (SPOTI_LOGIN_LOCK_DURATION) pozitif olmalı")
+	}
+
+	if c.OIDC.Enabled() {
+		if u, err := url.Parse(c.OIDC.Issuer); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("oidc.issuer (SPOTI_OIDC_ISSUER) http(s) ile başlayan tam bir adres olmalı, %q verildi", c.OIDC.Issuer))
+		}
+		if c.OIDC.Provider == "" {
+			problems = append(problems, "oidc.provider (SPOTI_OIDC_PROVIDER) boş olamaz")
+		}
+		if c.OIDC.ClientID == "" {
+			problems = append(problems, "oidc.client_id (SPOTI_OIDC_CLIENT_ID) boş olamaz")
+		}
+		if !slices.Contains(c.OIDC.Scopes, "openid") {
+			problems = append(problems, "oidc.scopes (SPOTI_OIDC_SCOPES) openid kapsamını içermeli")
+		}
+		if c.OIDC.RedirectURL != "" {
+			if u, err := url.Parse(c.OIDC.RedirectURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				problems = append(problems, fmt.Sprintf("oidc.redirect_url (SPOTI_OIDC_REDIRECT_URL) http(s) ile başlayan tam bir adres olmalı, %q verildi", c.OIDC.RedirectURL))
+			}
+		}
+	}
+
+	if c.Search.SuggestTimeout <= 0 {
+		problems = append(problems, fmt.Sprintf("search.suggest_timeout (SPOTI_SEARCH_SUGGEST_TIMEOUT) pozitif olmalı, %s verildi", c.Search.SuggestTimeout))
+	}
+
+	return problems
+}
+
+// ValidateServe, yalnızca HTTP sunucusunun kullandığı kimlik doğrulama ayarlarını doğrular.
+// auth.jwt_secret gibi bazı ayarların varsayılanı olmadığından bu kontrol Load'da yapılmaz;
+// böylece migrate ve admin komutları yalnızca veritabanı ayarlarıyla çalışabilir.
+func (c *Config) ValidateServe() error {
+	if problems := c.validateAuth(); len(problems) > 0 {
+		return &Error{Problems: problems}
+	}
+	return nil
+}
+
+// validateAuth, kimlik doğrulama ayarlarındaki eksik veya geçersiz değerleri toplar.
+func (c *Config) validateAuth() []string {
+	var problems []string
+
+	if len(c.Auth.JWTSecret) < minJWTSecretLength {
+		problems = append(problems, fmt.Sprintf("auth.jwt_secret (SPOTI_AUTH_JWT_SECRET) en az %d karakter olmalı", minJWTSecretLength))
+	}
+	if c.Auth.AccessTokenTTL <= 0 {
+		problems = append(problems, fmt.Sprintf("auth.access_token_ttl (SPOTI_AUTH_ACCESS_TOKEN_TTL) pozitif olmalı, %s verildi", c.Auth.AccessTokenTTL))
+	}
+	if c.Auth.RefreshTokenTTL <= c.Auth.AccessTokenTTL {
+		problems = append(problems, fmt.Sprintf("auth.refresh_token_ttl (SPOTI_AUTH_REFRESH_TOKEN_TTL) access_token_ttl değerinden uzun olmalı, %s verildi", c.Auth.RefreshTokenTTL))
+	}
+	if c.Auth.PasswordResetTTL <= 0 {
+		problems = append(problems, fmt.Sprintf("auth.password_reset_ttl (SPOTI_AUTH_PASSWORD_RESET_TTL) pozitif olmalı, %s verildi", c.Auth.PasswordResetTTL))
+	}
+	if c.Auth.EmailVerificationTTL <= 0 {
+		problems = append(problems, fmt.Sprintf("auth.email_verification_ttl (SPOTI_AUTH_EMAIL_VERIFICATION_TTL) pozitif olmalı, %s verildi", c.Auth.EmailVerificationTTL))
+	}
+	if c.Auth.VerificationResendInterval < 0 {
+		problems = append(problems, fmt.Sprintf("auth.verification_resend_interval (SPOTI_AUTH_VERIFICATION_RESEND_INTERVAL) negatif olamaz, %s verildi", c.Auth.VerificationResendInterval))
+	}
+	for _, action := range c.Auth.UnverifiedRestrictions {
+		switch action {
+		case "premium", "playlist_create", "api_keys":
+		default:
+			problems = append(problems, fmt.Sprintf("auth.unverified_restrictions (SPOTI_AUTH_UNVERIFIED_RESTRICTIONS) premium, playlist_create veya api_keys içerebilir, %q verildi", action))
+		}
+	}
+	if c.Auth.EmailChangeTTL <= 0 {
+		problems = append(problems, fmt.Sprintf("auth.email_change_ttl (SPOTI_AUTH_EMAIL_CHANGE_TTL) pozitif olmalı, %s verildi", c.Auth.EmailChangeTTL))
+	}
+	if c.Auth.RoleCacheTTL < 0 {
+		problems = append(problems, fmt.Sprintf("auth.role_cache_ttl (SPOTI_AUTH_ROLE_CACHE_TTL) negatif olamaz, %s verildi", c.Auth.RoleCacheTTL))
+	}
+	// İstek doğrulaması 8-72 karakter dışındaki şifreleri zaten reddeder.
+	if c.Auth.PasswordMinLength < 8 || c.Auth.PasswordMinLength > 72 {
+		problems = append(problems, fmt.Sprintf("auth.password_min_length (SPOTI_AUTH_PASSWORD_MIN_LENGTH) 8-72 aralığında olmalı, %d verildi", c.Auth.PasswordMinLength))
+	}
+
+	return problems
+}
+
+// ConnString, pgx'in kabul ettiği bağlantı dizesini üretir.
+func (d DatabaseConfig) ConnString() string {
+	if d.DSN != "" {
+		return d.DSN
+	}
+	return fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%d sslmode=%s",
+		quoteDSN(d.User), quoteDSN(d.Password), quoteDSN(d.Name), quoteDSN(d.Host), d.Port, d.SSLMode)
+}
+
+// quoteDSN, anahtar=değer biçimindeki bağlantı dizesi için değeri gerekirse tırnak içine alır.
+func quoteDSN(v string) string {
+	if v != "" && !strings.ContainsAny(v, ` '\`) {
+		return v
+	}
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
+
+// Error, yapılandırma yüklenirken bulunan tüm sorunları tek bir hatada toplar.
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString("geçersiz yapılandırma:")
+	for _, p := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(p)
+	}
+	return b.String()
+}
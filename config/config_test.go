@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAuthSettingsAreServeOnly(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		serveOK   bool
+		wantInErr string
+	}{
+		{"anahtar yok", "", false, "auth.jwt_secret"},
+		{"kısa anahtar", "kisa", false, "auth.jwt_secret"},
+		{"geçerli anahtar", strings.Repeat("k", minJWTSecretLength), true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SPOTI_CONFIG", "")
+			t.Setenv("SPOTI_AUTH_JWT_SECRET", tt.secret)
+
+			// migrate ve admin komutları yalnızca Load'un doğruladığı ayarlara ihtiyaç duyar.
+			cfg, err := Load("")
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			err = cfg.ValidateServe()
+			if tt.serveOK {
+				if err != nil {
+					t.Fatalf("ValidateServe: %v", err)
+				}
+				return
+			}
+			var cfgErr *Error
+			if !errors.As(err, &cfgErr) || !strings.Contains(err.Error(), tt.wantInErr) {
+				t.Fatalf("ValidateServe hatası = %v, %q içermeli", err, tt.wantInErr)
+			}
+		})
+	}
+}
+
+func TestTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		proxies   string
+		wantInErr string
+	}{
+		{"vekil yok", "", "", ""},
+		{"IP ve CIDR", "X-Real-IP", "10.0.0.1,172.16.0.0/12,::1", ""},
+		{"güvenilen vekil yok", "X-Real-IP", "", "server.trusted_proxies"},
+		{"başlık yok", "", "10.0.0.1", "server.proxy_header"},
+		{"geçersiz adres", "X-Forwarded-For", "10.0.0.1,vekil", `"vekil"`},
+		{"geçersiz aralık", "X-Forwarded-For", "10.0.0.0/33", `"10.0.0.0/33"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SPOTI_CONFIG", "")
+			t.Setenv("SPOTI_SERVER_PROXY_HEADER", tt.header)
+			t.Setenv("SPOTI_SERVER_TRUSTED_PROXIES", tt.proxies)
+
+			_, err := Load("")
+			if tt.wantInErr == "" {
+				if err != nil {
+					t.Fatalf("Load: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantInErr) {
+				t.Fatalf("Load hatası = %v, %q içermeli", err, tt.wantInErr)
+			}
+		})
+	}
+}
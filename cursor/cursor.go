@@ -0,0 +1,47 @@
+// Package cursor, anahtar tabanlı (keyset) sayfalanan liste uçlarının opak sayfa
+// belirteçlerini üretir ve çözer.
+//
+// Belirteç, sayfanın sınırındaki satırın sıralama anahtarını ve istenen yönü taşır;
+// istemci içeriğini yorumlamaz, yalnızca cursor parametresiyle aynen geri gönderir.
+// Belirteç imzalanmaz: değiştirilmiş bir belirteç yalnızca farklı bir konumdan
+// okumaya yol açar, çünkü anahtar değerleri sorgulara parametre olarak geçer.
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalid, çözülemeyen bir belirteç için döner.
+var ErrInvalid = errors.New("geçersiz sayfa belirteci")
+
+// token, belirtecin çözülmüş hâlidir.
+type token[K any] struct {
+	Before bool `json:"b,omitempty"`
+	Key    K    `json:"k"`
+}
+
+// Encode, key anahtarlı satırdan sonraki (before true ise önceki) sayfayı isteyen bir
+// belirteç üretir.
+func Encode[K any](key K, before bool) string {
+	data, err := json.Marshal(token[K]{Before: before, Key: key})
+	if err != nil {
+		// Anahtarlar yalnızca serileştirilebilir alanlardan oluşur; hata programlama hatasıdır.
+		panic("cursor: anahtar serileştirilemedi: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode, Encode ile üretilmiş belirteci çözer ve anahtarı, istenen yönle birlikte döndürür.
+func Decode[K any](s string) (key K, before bool, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return key, false, ErrInvalid
+	}
+	var t token[*K]
+	if err := json.Unmarshal(data, &t); err != nil || t.Key == nil {
+		return key, false, ErrInvalid
+	}
+	return *t.Key, t.Before, nil
+}
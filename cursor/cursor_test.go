@@ -0,0 +1,74 @@
+package cursor_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"spoti/cursor"
+
+	"github.com/google/uuid"
+)
+
+// key, liste uçlarının anahtarlarına benzeyen bir test anahtarıdır.
+type key struct {
+	Title     string    `json:"t,omitempty"`
+	Year      *int      `json:"y,omitempty"`
+	CreatedAt time.Time `json:"ca,omitzero"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	year := 1997
+	tests := []struct {
+		name   string
+		key    key
+		before bool
+	}{
+		{"sonraki sayfa", key{Title: "Şımarık", ID: uuid.New()}, false},
+		{"önceki sayfa", key{Title: "Kuzu Kuzu", ID: uuid.New()}, true},
+		{"isteğe bağlı alanlar", key{Year: &year, CreatedAt: time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC), ID: uuid.New()}, false},
+		{"sıfır anahtar", key{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := cursor.Encode(tt.key, tt.before)
+			if _, err := base64.RawURLEncoding.DecodeString(token); err != nil {
+				t.Fatalf("belirteç URL güvenli değil: %q", token)
+			}
+
+			got, before, err := cursor.Decode[key](token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if before != tt.before || !reflect.DeepEqual(got, tt.key) {
+				t.Fatalf("Decode = (%+v, %v), beklenen (%+v, %v)", got, before, tt.key, tt.before)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"base64 değil", "***"},
+		{"dolgulu base64", base64.URLEncoding.EncodeToString([]byte(`{"k":{}}`))},
+		{"JSON değil", encode("merhaba")},
+		{"anahtar yok", encode(`{"b":true}`)},
+		{"boş anahtar", encode(`{"k":null}`)},
+		{"yanlış tür", encode(`{"k":{"t":5}}`)},
+		{"geçersiz ID", encode(`{"k":{"id":"abc"}}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := cursor.Decode[key](tt.token); !errors.Is(err, cursor.ErrInvalid) {
+				t.Fatalf("Decode(%q) hatası = %v, beklenen ErrInvalid", tt.token, err)
+			}
+		})
+	}
+}
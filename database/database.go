@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"spoti/config"
+)
+
+// Connect, yapılandırmaya göre bir bağlantı havuzu oluşturur ve veritabanına erişilebildiğini doğrular.
+func Connect(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.ConnString())
+	if err != nil {
+		return nil, fmt.Errorf("bağlantı ayarları çözümlenemedi: %w", err)
+	}
+
+	poolConfig.MaxConns = int32(cfg.MaxConns)
+	poolConfig.MinConns = int32(cfg.MinConns)
+	if cfg.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+
+	pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("veritabanına bağlanılamadı: %w", err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("veritabanı bağlantısı başarısız: %w", err)
+	}
+
+	return pool, nil
+}
+
+// PoolStats, bağlantı havuzunun anlık durumunu JSON olarak sunmak için kullanılır.
+type PoolStats struct {
+	MaxConns             int32  `json:"max_conns"`
+	TotalConns           int32  `json:"total_conns"`
+	AcquiredConns        int32  `json:"acquired_conns"`
+	IdleConns            int32  `json:"idle_conns"`
+	ConstructingConns    int32  `json:"constructing_conns"`
+	AcquireCount         int64  `json:"acquire_count"`
+	EmptyAcquireCount    int64  `json:"empty_acquire_count"`
+	CanceledAcquireCount int64  `json:"canceled_acquire_count"`
+	AcquireDuration      string `json:"acquire_duration"`
+	// AvgAcquireWait, bir bağlantı için ortalama bekleme süresidir; havuz boyutunu ayarlarken
+	// empty_acquire_count ile birlikte izlenmelidir.
+	AvgAcquireWait          string `json:"avg_acquire_wait"`
+	NewConnsCount           int64  `json:"new_conns_count"`
+	MaxLifetimeDestroyCount int64  `json:"max_lifetime_destroy_count"`
+	MaxIdleDestroyCount     int64  `json:"max_idle_destroy_count"`
+}
+
+// Stats, havuzun istatistiklerini toplar.
+func Stats(pool *pgxpool.Pool) PoolStats {
+	s := pool.Stat()
+
+	var avgWait time.Duration
+	if s.AcquireCount() > 0 {
+		avgWait = s.AcquireDuration() / time.Duration(s.AcquireCount())
+	}
+
+	return PoolStats{
+		MaxConns:                s.MaxConns(),
+		TotalConns:              s.TotalConns(),
+		AcquiredConns:           s.AcquiredConns(),
+		IdleConns:               s.IdleConns(),
+		ConstructingConns:       s.ConstructingConns(),
+		AcquireCount:            s.AcquireCount(),
+		EmptyAcquireCount:       s.EmptyAcquireCount(),
+		CanceledAcquireCount:    s.CanceledAcquireCount(),
+		AcquireDuration:         s.AcquireDuration().String(),
+		AvgAcquireWait:          avgWait.String(),
+		NewConnsCount:           s.NewConnsCount(),
+		MaxLifetimeDestroyCount: s.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     s.MaxIdleDestroyCount(),
+	}
+}
+
+// LogStats, ctx iptal edilene kadar havuz istatistiklerini belirtilen aralıkla loglar.
+func LogStats(ctx context.Context, pool *pgxpool.Pool, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s := Stats(pool)
+			log.Printf("DB havuzu: toplam=%d kullanımda=%d boşta=%d max=%d bekleyen_alım=%d ort_bekleme=%s\n",
+				s.TotalConns, s.AcquiredConns, s.IdleConns, s.MaxConns, s.EmptyAcquireCount, s.AvgAcquireWait)
+		}
+	}
+}
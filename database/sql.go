@@ -0,0 +1,31 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
+
+	"spoti/config"
+)
+
+// OpenSQL, database/sql arayüzü bekleyen araçlar (örneğin goose) için pgx sürücüsüyle
+// tek bağlantılık bir *sql.DB açar ve veritabanına erişilebildiğini doğrular.
+func OpenSQL(ctx context.Context, cfg config.DatabaseConfig) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(cfg.ConnString())
+	if err != nil {
+		return nil, fmt.Errorf("bağlantı ayarları çözümlenemedi: %w", err)
+	}
+
+	db := stdlib.OpenDB(*connConfig)
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("veritabanı bağlantısı başarısız: %w", err)
+	}
+
+	return db, nil
+}
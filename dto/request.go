@@ -0,0 +1,211 @@
+// Package dto, HTTP isteklerinin ve yanıtlarının gövde tiplerini tanımlar.
+// İstek tipleri `validate` etiketleriyle middleware.ValidateBody tarafından doğrulanır.
+package dto
+
+import "github.com/google/uuid"
+
+// ListQuery, anahtar tabanlı sayfalanan liste uçlarının sorgu parametreleridir. cursor,
+// önceki yanıttaki next_cursor veya prev_cursor değeridir; verilmezse ilk sayfa döner.
+// include_total, toplam kayıt sayısını da ister; sayım her istekte ek bir sorgu olduğundan
+// varsayılan olarak yapılmaz.
+type ListQuery struct {
+	Cursor       string `query:"cursor" json:"cursor" validate:"max=1024"`
+	PageSize     int    `query:"page_size" json:"page_size" validate:"min=1,max=100"`
+	IncludeTotal bool   `query:"include_total" json:"include_total"`
+}
+
+// Defaults, parametre gönderilmediğinde kullanılacak değerleri atar.
+func (q *ListQuery) Defaults() {
+	q.PageSize = 20
+}
+
+// SongListQuery, GET /api/user/song uçunun sorgu parametreleridir. Sıfır değerli filtreler
+// uygulanmaz; sort verilmezse arama varken ilgiye, yokken popülerliğe göre sıralanır. order
+// verilmezse metin alanları ve süre artan, popülerlik, yenilik ve ilgi azalan sıralanır.
+// Sayfalama ListQuery ile aynıdır; cursor, aynı filtre ve sıralamayla kullanılmalıdır.
+type SongListQuery struct {
+	Cursor       string `query:"cursor" json:"cursor" validate:"max=1024"`
+	PageSize     int    `query:"page_size" json:"page_size" validate:"min=1,max=100"`
+	IncludeTotal bool   `query:"include_total" json:"include_total"`
+	Search       string `query:"search" json:"search" validate:"max=200"`
+	Artist       string `query:"artist" json:"artist" validate:"max=255"`
+	Album        string `query:"album" json:"album" validate:"max=255"`
+	Genre        string `query:"genre" json:"genre" validate:"max=100"`
+	MinDuration  int    `query:"min_duration" json:"min_duration" validate:"min=0"`
+	MaxDuration  int    `query:"max_duration" json:"max_duration" validate:"omitempty,gtefield=MinDuration"`
+	YearFrom     int    `query:"year_from" json:"year_from" validate:"omitempty,min=1800,max=2100"`
+	YearTo       int    `query:"year_to" json:"year_to" validate:"omitempty,min=1800,max=2100,gtefield=YearFrom"`
+	Sort         string `query:"sort" json:"sort" validate:"omitempty,oneof=relevance title artist duration popularity newest"`
+	Order        string `query:"order" json:"order" validate:"omitempty,oneof=asc desc"`
+}
+
+// Defaults, parametre gönderilmediğinde kullanılacak değerleri atar.
+func (q *SongListQuery) Defaults() {
+	q.PageSize = 10
+}
+
+// SuggestQuery, GET /api/user/song/suggest uçunun sorgu parametreleridir.
+type SuggestQuery struct {
+	Q     string `query:"q" json:"q" validate:"required,min=2,max=100"`
+	Limit int    `query:"limit" json:"limit" validate:"min=1,max=20"`
+}
+
+// Defaults, parametre gönderilmediğinde kullanılacak değerleri atar.
+func (q *SuggestQuery) Defaults() {
+	q.Limit = 10
+}
+
+// RegisterRequest, POST /api/user/register gövdesidir.
+type RegisterRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=50"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	// bcrypt yalnızca ilk 72 baytı kullandığından daha uzun şifreler kabul edilmez.
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// LoginRequest, POST /api/user/login gövdesidir.
+// Tokens true ise session yerine bearer erişim ve yenileme token'ları döner.
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+	Tokens   bool   `json:"tokens"`
+}
+
+// RefreshRequest, POST /api/auth/refresh ve /api/auth/revoke gövdesidir.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// ForgotPasswordRequest, POST /api/auth/forgot-password gövdesidir.
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest, POST /api/auth/reset-password gövdesidir.
+// Şifre kuralları RegisterRequest ile aynıdır.
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// VerifyEmailRequest, POST /api/auth/verify-email gövdesidir.
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ChangePasswordRequest, PUT /api/user/password gövdesidir. Yeni şifre ayrıca
+// yapılandırılmış şifre politikasına göre kontrol edilir.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required,max=72"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=72"`
+}
+
+// ChangeEmailRequest, PUT /api/user/email gövdesidir.
+type ChangeEmailRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required,max=72"`
+	NewEmail        string `json:"new_email" validate:"required,email,max=255"`
+}
+
+// ConfirmEmailChangeRequest, POST /api/auth/confirm-email gövdesidir.
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// TwoFactorCodeRequest, iki adımlı doğrulamayı etkinleştirme, kapatma ve kurtarma kodlarını
+// yenileme isteklerinin gövdesidir. Code, 6 haneli TOTP kodu veya bir kurtarma kodudur.
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+// TwoFactorLoginRequest, POST /api/auth/2fa gövdesidir.
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required,max=32"`
+}
+
+// UpdateProfileRequest, PUT /api/user gövdesidir. Kullanıcı yalnızca profil alanlarını
+// değiştirebilir; hesap türü ve bakiye satın alma ve admin akışlarıyla değişir.
+type UpdateProfileRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=50"`
+}
+
+// UpdateAccountRequest, PUT /api/admin/user/:userID gövdesidir; hesap türünü ve bakiyeyi değiştirir.
+type UpdateAccountRequest struct {
+	HesapTuru string  `json:"hesap_turu" validate:"required,oneof=Free Premium"`
+	Cash      float64 `json:"cash" validate:"min=0"`
+}
+
+// LanguageRequest, PUT /api/user/language gövdesidir. Boş dil tercihi kaldırır.
+type LanguageRequest struct {
+	Language string `json:"language" validate:"omitempty,oneof=tr en"`
+}
+
+// CreateAPIKeyRequest, POST /api/user/api-keys gövdesidir.
+// Kapsamlar auth paketindeki Scope sabitleriyle aynı olmalıdır.
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required,max=4,dive,oneof=playlists:read playlists:write songs:read admin:*"`
+}
+
+// CreateRoleRequest, POST /api/admin/roles gövdesidir.
+// İzin adları handler'da auth.Permissions listesine göre doğrulanır.
+type CreateRoleRequest struct {
+	Name        string   `json:"name" validate:"required,min=2,max=50,slug"`
+	Permissions []string `json:"permissions" validate:"max=20,dive,required,max=50"`
+}
+
+// UpdateRoleRequest, PUT /api/admin/roles/:name gövdesidir; rolün tüm izinlerini değiştirir.
+// İzin adları handler'da auth.Permissions listesine göre doğrulanır.
+type UpdateRoleRequest struct {
+	Permissions []string `json:"permissions" validate:"max=20,dive,required,max=50"`
+}
+
+// AssignRoleRequest, PUT /api/admin/user/:userID/role gövdesidir.
+type AssignRoleRequest struct {
+	Role string `json:"role" validate:"required,max=50"`
+}
+
+// SongRequest, şarkı ekleme ve güncelleme isteklerinin gövdesidir.
+type SongRequest struct {
+	Title    string `json:"title" validate:"required,max=255"`
+	Artist   string `json:"artist" validate:"required,max=255"`
+	Album    string `json:"album" validate:"max=255"`
+	Duration int    `json:"duration" validate:"required,min=1"`
+	Genre    string `json:"genre" validate:"max=100"`
+	// ReleaseYear sıfırsa yayın yılı bilinmiyor kabul edilir.
+	ReleaseYear int `json:"release_year" validate:"omitempty,min=1800,max=2100"`
+	// Featuring, ana sanatçıdan sonra adı geçen diğer sanatçılardır.
+	Featuring []string `json:"featuring" validate:"max=10,dive,required,max=255"`
+	// TrackNumber sıfırsa albümdeki sırası bilinmiyor kabul edilir.
+	TrackNumber int `json:"track_number" validate:"omitempty,min=1,max=999"`
+}
+
+// AlbumRequest, PUT /api/admin/album/:albumID gövdesidir.
+type AlbumRequest struct {
+	Title string `json:"title" validate:"required,max=255"`
+	// ReleaseDate boşsa yayın tarihi bilinmiyor kabul edilir.
+	ReleaseDate string `json:"release_date" validate:"omitempty,date"`
+}
+
+// CreatePlaylistRequest, POST /api/user/playlist gövdesidir.
+type CreatePlaylistRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// CreateCouponRequest, POST /api/admin/coupon gövdesidir.
+type CreateCouponRequest struct {
+	Code string `json:"code" validate:"required,min=4,max=255"`
+}
+
+// AssignCouponRequest, POST /api/admin/coupon/assign gövdesidir.
+// UserID boş bırakılırsa kupon tüm kullanıcılara atanır.
+type AssignCouponRequest struct {
+	CouponID uuid.UUID `json:"cuponID" validate:"required"`
+	UserID   uuid.UUID `json:"userID"`
+}
+
+// PurchasePremiumRequest, POST /api/user/premium gövdesidir.
+type PurchasePremiumRequest struct {
+	UseCoupon bool   `json:"useCoupon"`
+	CouponID  string `json:"couponID" validate:"omitempty,uuid"`
+}
@@ -0,0 +1,276 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"spoti/lockout"
+	"spoti/models"
+	"spoti/sessions"
+)
+
+// Kullanıcı yanıtları, isteği yapanın yetkisine göre üç görünüme ayrılır.
+// models.User hiçbir zaman doğrudan serileştirilmez; böylece şifre hash'i
+// ve iç alanlar yanlışlıkla yanıta girmez.
+
+// PublicUser, başka kullanıcıların görebileceği profil bilgisidir.
+type PublicUser struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+}
+
+// SelfUser, oturum sahibinin kendi hesabına ait bilgileridir.
+type SelfUser struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	HesapTuru string    `json:"hesap_turu"`
+	Cash      float64   `json:"cash"`
+	Language  string    `json:"language"`
+	// EmailVerified false ise bazı işlemler e-posta doğrulanana kadar kapalı olabilir.
+	EmailVerified bool `json:"email_verified"`
+}
+
+// AdminUser, admin uçlarının döndürdüğü ayrıntılı kullanıcı görünümüdür.
+type AdminUser struct {
+	ID              uuid.UUID  `json:"id"`
+	RoleID          uuid.UUID  `json:"role_id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	HesapTuru       string     `json:"hesap_turu"`
+	Cash            float64    `json:"cash"`
+	Language        string     `json:"language"`
+	CreatedAt       time.Time  `json:"created_at"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+}
+
+// NewPublicUser, kullanıcının herkese açık görünümünü oluşturur.
+func NewPublicUser(u *models.User) PublicUser {
+	return PublicUser{ID: u.ID, Username: u.Username}
+}
+
+// NewSelfUser, kullanıcının kendi hesabı için görünümünü oluşturur.
+func NewSelfUser(u *models.User) SelfUser {
+	return SelfUser{
+		ID:            u.ID,
+		Username:      u.Username,
+		Email:         u.Email,
+		HesapTuru:     u.HesapTuru,
+		Cash:          u.Cash,
+		Language:      u.Language,
+		EmailVerified: u.EmailVerified(),
+	}
+}
+
+// NewAdminUser, kullanıcının admin görünümünü oluşturur.
+func NewAdminUser(u *models.User) AdminUser {
+	return AdminUser{
+		ID:              u.ID,
+		RoleID:          u.RoleID,
+		Username:        u.Username,
+		Email:           u.Email,
+		HesapTuru:       u.HesapTuru,
+		Cash:            u.Cash,
+		Language:        u.Language,
+		CreatedAt:       u.CreatedAt,
+		EmailVerifiedAt: u.EmailVerifiedAt,
+	}
+}
+
+// NewAdminUsers, kullanıcı listesini admin görünümüne çevirir.
+func NewAdminUsers(users []models.User) []AdminUser {
+	out := make([]AdminUser, len(users))
+	for i := range users {
+		out[i] = NewAdminUser(&users[i])
+	}
+	return out
+}
+
+// Role, admin uçlarında gösterilen rol bilgisidir.
+type Role struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	BuiltIn     bool      `json:"built_in"`
+	Permissions []string  `json:"permissions"`
+}
+
+// NewRole, rolün görünümünü oluşturur. İzni olmayan roller boş liste olarak döner.
+func NewRole(r *models.Role) Role {
+	permissions := r.Permissions
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return Role{ID: r.ID, Name: r.Name, BuiltIn: r.BuiltIn, Permissions: permissions}
+}
+
+// NewRoles, rol listesini görünüme çevirir.
+func NewRoles(roles []models.Role) []Role {
+	out := make([]Role, len(roles))
+	for i := range roles {
+		out[i] = NewRole(&roles[i])
+	}
+	return out
+}
+
+// TokenResponse, bearer token girişinin ve token yenilemenin yanıtıdır.
+// Süreler saniye cinsindendir.
+type TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int    `json:"refresh_expires_in"`
+}
+
+// APIKey, bir API anahtarının listelemede gösterilen bilgileridir; anahtarın kendisi yer almaz.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// CreatedAPIKey, yeni oluşturulan API anahtarının yanıtıdır. Key yalnızca bu yanıtta döner.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// NewAPIKey, anahtarın listeleme görünümünü oluşturur.
+func NewAPIKey(k *models.APIKey) APIKey {
+	return APIKey{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+	}
+}
+
+// NewAPIKeys, anahtar listesini listeleme görünümüne çevirir.
+func NewAPIKeys(keys []models.APIKey) []APIKey {
+	out := make([]APIKey, len(keys))
+	for i := range keys {
+		out[i] = NewAPIKey(&keys[i])
+	}
+	return out
+}
+
+// Session, kullanıcının açık bir session'ının listelemede gösterilen bilgileridir.
+// Current, isteğin bu session'la yapıldığını gösterir.
+type Session struct {
+	ID        string    `json:"id"`
+	Device    string    `json:"device"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+	Current   bool      `json:"current"`
+}
+
+// NewSessions, session dizinini listeleme görünümüne çevirir; currentKey isteğin session anahtarıdır.
+func NewSessions(entries []sessions.Entry, currentKey string) []Session {
+	out := make([]Session, len(entries))
+	for i, e := range entries {
+		out[i] = Session{
+			ID:        e.ID,
+			Device:    e.Device,
+			IP:        e.IP,
+			UserAgent: e.UserAgent,
+			CreatedAt: e.CreatedAt,
+			LastSeen:  e.LastSeen,
+			Current:   currentKey != "" && e.Key == currentKey,
+		}
+	}
+	return out
+}
+
+// TwoFactorChallenge, iki adımlı doğrulaması etkin kullanıcının şifre adımından sonra dönen yanıttır.
+// Giriş, ChallengeToken ve doğrulama koduyla POST /api/auth/2fa üzerinden tamamlanır.
+type TwoFactorChallenge struct {
+	Message           string `json:"message"`
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int    `json:"expires_in"`
+}
+
+// TwoFactorSetup, TOTP kurulumunun yanıtıdır. OTPAuthURI, doğrulayıcı uygulamaların QR kod
+// olarak okuyabildiği adrestir.
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// TwoFactorStatus, kullanıcının iki adımlı doğrulama durumudur.
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// RecoveryCodes, yeni üretilen kurtarma kodlarının yanıtıdır; kodlar yalnızca bu yanıtta döner.
+type RecoveryCodes struct {
+	Message       string   `json:"message"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// LockoutStatus, bir hesabın veya IP adresinin başarısız giriş sayacının admin görünümüdür.
+type LockoutStatus struct {
+	Kind        string     `json:"kind"`
+	Subject     string     `json:"subject"`
+	Failures    int        `json:"failures"`
+	Locked      bool       `json:"locked"`
+	LastFailure *time.Time `json:"last_failure,omitempty"`
+	RetryAt     *time.Time `json:"retry_at,omitempty"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+}
+
+// NewLockoutStatus, sayaç durumunu admin görünümüne çevirir; geçmişte kalan zamanlar gösterilmez.
+func NewLockoutStatus(kind, subject string, state lockout.State, now time.Time) LockoutStatus {
+	status := LockoutStatus{Kind: kind, Subject: subject, Failures: state.Failures}
+	if !state.LastFailure.IsZero() {
+		status.LastFailure = &state.LastFailure
+	}
+	if now.Before(state.RetryAt) {
+		status.RetryAt = &state.RetryAt
+	}
+	if now.Before(state.LockedUntil) {
+		status.Locked = true
+		status.LockedUntil = &state.LockedUntil
+	}
+	return status
+}
+
+// PageInfo, anahtar tabanlı sayfalanan liste yanıtlarının sayfa bilgisidir. Belirteçler
+// opaktır; istemci bunları cursor parametresiyle aynen geri gönderir. O yönde kayıt yoksa
+// ilgili belirteç yanıtta yer almaz. Total yalnızca include_total istendiğinde döner.
+type PageInfo struct {
+	PageSize   int    `json:"page_size"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Total      *int   `json:"total,omitempty"`
+}
+
+// SongPage, şarkı kataloğunun bir sayfasıdır.
+type SongPage struct {
+	Songs []models.Song `json:"songs"`
+	PageInfo
+	// DidYouMean, sonuç bulunamayan bir aramada önerilen en yakın addır.
+	DidYouMean string `json:"did_you_mean,omitempty"`
+}
+
+// UserPage, admin kullanıcı listesinin bir sayfasıdır.
+type UserPage struct {
+	Users []AdminUser `json:"users"`
+	PageInfo
+}
+
+// PlaylistPage, bir kullanıcının çalma listelerinin bir sayfasıdır.
+type PlaylistPage struct {
+	Playlists []models.Playlist `json:"playlists"`
+	PageInfo
+}
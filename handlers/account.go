@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/mail"
+	"spoti/middleware"
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ChangePassword, mevcut şifreyi doğruladıktan sonra oturumdaki kullanıcının şifresini değiştirir.
+// Diğer cihazlardaki tüm session ve token'lar geçersiz olur; istek session'la yapıldıysa yeni
+// ID'li bir session açılır, bearer token'la yapıldıysa yanıt olarak yeni bir token çifti döner.
+func (h *Handler) ChangePassword(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+	req := middleware.Body[dto.ChangePasswordRequest](c)
+
+	user, err := h.reauthenticate(c, userID, req.CurrentPassword)
+	if err != nil {
+		return err
+	}
+	if auth.CheckPassword(user.Password, req.NewPassword) {
+		return apierr.Validation(apierr.FieldError{Field: "new_password", Code: "unchanged", Key: "account.password_unchanged"})
+	}
+	if err := h.checkPasswordPolicy("new_password", req.NewPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		log.Printf("Şifre hashleme hatası: %v\n", err)
+		return apierr.New(apierr.CodeInternal, "auth.hash_failed")
+	}
+	if err := h.Users.SetPassword(c.UserContext(), user.ID, hashedPassword); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.not_found")
+		}
+		log.Println("Şifre güncelleme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "account.password_change_failed")
+	}
+
+	if err := h.revokeAllSessions(c, user.ID); err != nil {
+		return err
+	}
+	if middleware.AuthMethod(c) == middleware.AuthBearer {
+		return h.respondWithTokens(c, user, uuid.New())
+	}
+	if err := h.startSession(c, user); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "account.password_changed")})
+}
+
+// ChangeEmail, mevcut şifreyi doğruladıktan sonra yeni adrese bir onay bağlantısı gönderir.
+// Adres, bağlantı ConfirmEmailChange ile açılana kadar değişmez; eski adrese de bilgi verilir.
+func (h *Handler) ChangeEmail(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+	req := middleware.Body[dto.ChangeEmailRequest](c)
+	ctx := c.UserContext()
+
+	user, err := h.reauthenticate(c, userID, req.CurrentPassword)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(req.NewEmail, user.Email) {
+		return apierr.Validation(apierr.FieldError{Field: "new_email", Code: "unchanged", Key: "account.email_unchanged"})
+	}
+	if _, err := h.Users.GetByEmail(ctx, req.NewEmail); err == nil {
+		return apierr.New(apierr.CodeUserAlreadyExists, "account.email_taken")
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		log.Println("Veritabanı sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "account.email_change_failed")
+	}
+
+	plain, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		log.Println("E-posta değişikliği token'ı üretme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "account.email_change_failed")
+	}
+	token := models.UserToken{
+		UserID:    user.ID,
+		Purpose:   models.TokenPurposeEmailChange,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(h.EmailChangeTTL),
+		NewEmail:  req.NewEmail,
+	}
+	if err := h.UserTokens.Create(ctx, &token); err != nil {
+		log.Println("E-posta değişikliği token'ı kaydetme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "account.email_change_failed")
+	}
+
+	lang := mailLanguage(c, user)
+	msg := mail.Message{
+		To:      req.NewEmail,
+		Subject: i18n.Translate(lang, "mail.email_change_subject"),
+		Body:    i18n.Translate(lang, "mail.email_change_body", user.Username, h.link("/confirm-email", plain), int(math.Ceil(h.EmailChangeTTL.Hours()))),
+	}
+	if err := h.Mailer.Send(ctx, msg); err != nil {
+		log.Printf("E-posta değişikliği onayı gönderilemedi (%s): %v\n", req.NewEmail, err)
+		return apierr.New(apierr.CodeInternal, "account.email_change_failed")
+	}
+	// Hesabı ele geçiren biri adresi değiştirmeye çalışıyorsa asıl sahibi haberdar olur.
+	notice := mail.Message{
+		To:      user.Email,
+		Subject: i18n.Translate(lang, "mail.email_change_notice_subject"),
+		Body:    i18n.Translate(lang, "mail.email_change_notice_body", user.Username, req.NewEmail),
+	}
+	if err := h.Mailer.Send(ctx, notice); err != nil {
+		log.Printf("E-posta değişikliği bildirimi gönderilemedi (%s): %v\n", user.Email, err)
+	}
+
+	if middleware.AuthMethod(c) == middleware.AuthSession {
+		if err := h.startSession(c, user); err != nil {
+			return err
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "account.email_change_sent")})
+}
+
+// ConfirmEmailChange, yeni adrese gönderilen token'la e-posta değişikliğini tamamlar.
+// Oturum gerektirmez; yeni adres bağlantı açıldığı için doğrulanmış sayılır.
+func (h *Handler) ConfirmEmailChange(c *fiber.Ctx) error {
+	req := middleware.Body[dto.ConfirmEmailChangeRequest](c)
+	ctx := c.UserContext()
+
+	token, err := h.UserTokens.Consume(ctx, auth.HashToken(req.Token), models.TokenPurposeEmailChange)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierr.New(apierr.CodeEmailChangeInvalid, "account.email_change_token_invalid")
+	}
+	if err != nil {
+		log.Println("E-posta değişikliği token'ı doğrulama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "account.email_change_failed")
+	}
+
+	if err := h.Users.ChangeEmail(ctx, token.UserID, token.NewEmail); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return apierr.New(apierr.CodeUserAlreadyExists, "account.email_taken")
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeEmailChangeInvalid, "account.email_change_token_invalid")
+		}
+		log.Println("E-posta güncelleme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "account.email_change_failed")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "account.email_changed")})
+}
+
+// reauthenticate, hassas hesap işlemlerinden önce kullanıcının mevcut şifresini doğrular ve
+// şifre hash'i dahil kullanıcıyı döndürür. Yanlış denemeler girişle aynı sayaçlara yazılır;
+// böylece ele geçirilmiş bir oturumla şifre tahmin edilemez.
+func (h *Handler) reauthenticate(c *fiber.Ctx, userID uuid.UUID, password string) (*models.User, error) {
+	ctx := c.UserContext()
+
+	user, err := h.Users.GetByID(ctx, userID)
+	if err == nil {
+		user, err = h.Users.GetByEmail(ctx, user.Email)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, apierr.New(apierr.CodeUserNotFound, "user.not_found")
+		}
+		log.Println("Veritabanı sorgu hatası:", err)
+		return nil, apierr.New(apierr.CodeInternal, "user.fetch_failed")
+	}
+
+	block, err := h.Lockout.Check(user.Email, c.IP(), time.Now())
+	if err != nil {
+		log.Println("Giriş denemesi sayacı okuma hatası:", err)
+		return nil, apierr.New(apierr.CodeInternal, "account.reauth_failed")
+	}
+	if block != nil {
+		return nil, loginBlocked(block)
+	}
+
+	if !auth.CheckPassword(user.Password, password) {
+		block, err := h.Lockout.Fail(user.Email, c.IP(), time.Now())
+		if err != nil {
+			log.Println("Giriş denemesi sayacı güncelleme hatası:", err)
+		}
+		if block != nil {
+			return nil, loginBlocked(block)
+		}
+		return nil, apierr.New(apierr.CodeInvalidCredentials, "account.current_password_invalid")
+	}
+	if err := h.Lockout.Succeed(user.Email); err != nil {
+		log.Println("Giriş denemesi sayacı sıfırlama hatası:", err)
+	}
+	return user, nil
+}
+
+// checkPasswordPolicy, şifre yapılandırılmış politikaya uymuyorsa ihlal edilen her kural için
+// field alanında bir ayrıntı içeren doğrulama hatası döndürür.
+func (h *Handler) checkPasswordPolicy(field, password string) error {
+	violations := h.PasswordPolicy.Check(password)
+	if len(violations) == 0 {
+		return nil
+	}
+	details := make([]apierr.FieldError, len(violations))
+	for i, rule := range violations {
+		details[i] = apierr.FieldError{Field: field, Code: "password_" + rule, Key: "validation.password_" + rule}
+		if rule == auth.PasswordRuleMinLength {
+			details[i].Args = []any{h.PasswordPolicy.MinLength}
+		}
+	}
+	return apierr.Validation(details...)
+}
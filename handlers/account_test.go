@@ -0,0 +1,173 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/middleware"
+)
+
+// mountAccount, şifre ve e-posta değiştirme rotalarını session veya bearer token'la doğrulanan
+// istekler için ekler.
+func mountAccount(env *testEnv) {
+	authRequired := middleware.AuthRequired(env.h.AuthConfig())
+	env.app.Post("/login", middleware.ValidateBody[dto.LoginRequest](), env.h.LoginUser)
+	env.app.Post("/refresh", middleware.ValidateBody[dto.RefreshRequest](), env.h.RefreshToken)
+	env.app.Get("/me", authRequired, env.h.GetUser)
+	env.app.Put("/password", authRequired, middleware.ValidateBody[dto.ChangePasswordRequest](), env.h.ChangePassword)
+	env.app.Put("/email", authRequired, middleware.ValidateBody[dto.ChangeEmailRequest](), env.h.ChangeEmail)
+	env.app.Post("/confirm-email", middleware.ValidateBody[dto.ConfirmEmailChangeRequest](), env.h.ConfirmEmailChange)
+}
+
+func TestChangePasswordWrongCurrentPasswordFeedsLockout(t *testing.T) {
+	env := newTestEnv(t)
+	mountAccount(env)
+	user := env.createUser("alice", auth.RoleUser)
+	session := login(env, user.Email, testPassword)
+	session.expect(t, http.StatusOK, "")
+
+	req := dto.ChangePasswordRequest{CurrentPassword: "yanlis-sifre", NewPassword: "Yeni-sifre-1"}
+	for i := 1; i < 5; i++ {
+		env.do(http.MethodPut, "/password", req, session.cookie).expect(t, http.StatusUnauthorized, apierr.CodeInvalidCredentials)
+	}
+	if got := accountFailures(t, env, user.Email); got != 4 {
+		t.Fatalf("hatalı deneme sayısı = %d, beklenen 4", got)
+	}
+	env.do(http.MethodPut, "/password", req, session.cookie).expect(t, http.StatusTooManyRequests, apierr.CodeAccountLocked)
+
+	// Kilit girişle ortaktır; ne doğru şifreyle yeniden doğrulama ne de giriş kabul edilir.
+	req.CurrentPassword = testPassword
+	env.do(http.MethodPut, "/password", req, session.cookie).expect(t, http.StatusTooManyRequests, apierr.CodeAccountLocked)
+	login(env, user.Email, testPassword).expect(t, http.StatusTooManyRequests, apierr.CodeAccountLocked)
+}
+
+func TestChangePasswordRevokesOtherSessions(t *testing.T) {
+	env := newTestEnv(t)
+	mountAccount(env)
+	user := env.createUser("alice", auth.RoleUser)
+
+	current := login(env, user.Email, testPassword)
+	current.expect(t, http.StatusOK, "")
+	other := login(env, user.Email, testPassword)
+	other.expect(t, http.StatusOK, "")
+	tokens := tokenLogin(t, env, user.Email)
+
+	nextSecond()
+	changed := env.do(http.MethodPut, "/password", dto.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: "Yeni-sifre-1"}, current.cookie)
+	changed.expect(t, http.StatusOK, "")
+
+	// İsteği yapan cihaz yeni bir session'la devam eder; diğerleri kapanır.
+	if changed.cookie == current.cookie {
+		t.Fatal("şifre değişikliğinden sonra session yenilenmedi")
+	}
+	env.do(http.MethodGet, "/me", nil, changed.cookie).expect(t, http.StatusOK, "")
+	env.do(http.MethodGet, "/me", nil, current.cookie).expect(t, http.StatusUnauthorized, "")
+	env.do(http.MethodGet, "/me", nil, other.cookie).expect(t, http.StatusUnauthorized, apierr.CodeSessionExpired)
+	env.doBearer(http.MethodGet, "/me", nil, tokens.AccessToken).expect(t, http.StatusUnauthorized, apierr.CodeTokenExpired)
+	resp, _ := refresh(t, env, tokens.RefreshToken)
+	resp.expect(t, http.StatusUnauthorized, apierr.CodeRefreshInvalid)
+
+	list, err := env.h.Sessions.List(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("açık session'lar = %+v, beklenen yalnızca yeni session", list)
+	}
+	login(env, user.Email, "Yeni-sifre-1").expect(t, http.StatusOK, "")
+}
+
+func TestChangePasswordWithBearerReturnsNewTokens(t *testing.T) {
+	env := newTestEnv(t)
+	mountAccount(env)
+	user := env.createUser("alice", auth.RoleUser)
+	old := tokenLogin(t, env, user.Email)
+
+	nextSecond()
+	resp := env.doBearer(http.MethodPut, "/password", dto.ChangePasswordRequest{CurrentPassword: testPassword, NewPassword: "Yeni-sifre-1"}, old.AccessToken)
+	resp.expect(t, http.StatusOK, "")
+	var fresh dto.TokenResponse
+	resp.decode(t, &fresh)
+
+	env.doBearer(http.MethodGet, "/me", nil, fresh.AccessToken).expect(t, http.StatusOK, "")
+	env.doBearer(http.MethodGet, "/me", nil, old.AccessToken).expect(t, http.StatusUnauthorized, apierr.CodeTokenExpired)
+	r, _ := refresh(t, env, old.RefreshToken)
+	r.expect(t, http.StatusUnauthorized, apierr.CodeRefreshInvalid)
+	r, _ = refresh(t, env, fresh.RefreshToken)
+	r.expect(t, http.StatusOK, "")
+}
+
+func TestEmailChangeTokenIsBoundToNewAddress(t *testing.T) {
+	env := newTestEnv(t)
+	mountAccount(env)
+	user := env.createUser("alice", auth.RoleUser)
+	session := login(env, user.Email, testPassword)
+	session.expect(t, http.StatusOK, "")
+
+	changed := env.do(http.MethodPut, "/email", dto.ChangeEmailRequest{CurrentPassword: testPassword, NewEmail: "yeni@spoti.test"}, session.cookie)
+	changed.expect(t, http.StatusOK, "")
+	to, first := env.mailLink("/confirm-email")
+	if to != "yeni@spoti.test" {
+		t.Fatalf("onay bağlantısı %s adresine gönderildi, beklenen yeni adres", to)
+	}
+	// Yeniden doğrulama session'ı yenilediğinden sonraki istek yeni çerezle yapılır.
+	env.do(http.MethodPut, "/email", dto.ChangeEmailRequest{CurrentPassword: testPassword, NewEmail: "baska@spoti.test"}, changed.cookie).
+		expect(t, http.StatusOK, "")
+	to, second := env.mailLink("/confirm-email")
+	if to != "baska@spoti.test" {
+		t.Fatalf("onay bağlantısı %s adresine gönderildi, beklenen baska@spoti.test", to)
+	}
+
+	// Yeni istek öncekinin bağlantısını geçersiz kılar; eski bağlantıyı ele geçiren biri
+	// adresi ilk istenen adrese çeviremez.
+	env.do(http.MethodPost, "/confirm-email", dto.ConfirmEmailChangeRequest{Token: first}, "").
+		expect(t, http.StatusBadRequest, apierr.CodeEmailChangeInvalid)
+
+	// Token, kendi isteğindeki adrese bağlıdır.
+	env.do(http.MethodPost, "/confirm-email", dto.ConfirmEmailChangeRequest{Token: second}, "").expect(t, http.StatusOK, "")
+	got, err := env.mem.Users().GetByID(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Email != "baska@spoti.test" || got.EmailVerifiedAt == nil {
+		t.Fatalf("adres = %s (doğrulandı: %v), beklenen baska@spoti.test", got.Email, got.EmailVerifiedAt != nil)
+	}
+	env.do(http.MethodPost, "/confirm-email", dto.ConfirmEmailChangeRequest{Token: second}, "").
+		expect(t, http.StatusBadRequest, apierr.CodeEmailChangeInvalid)
+
+	login(env, "baska@spoti.test", testPassword).expect(t, http.StatusOK, "")
+	login(env, user.Email, testPassword).expect(t, http.StatusUnauthorized, apierr.CodeInvalidCredentials)
+}
+
+func TestChangeEmailConflicts(t *testing.T) {
+	env := newTestEnv(t)
+	mountAccount(env)
+	user := env.createUser("alice", auth.RoleUser)
+	bob := env.createUser("bob", auth.RoleUser)
+	session := login(env, user.Email, testPassword)
+	session.expect(t, http.StatusOK, "")
+
+	// Kayıtlı bir adres istenemez.
+	env.do(http.MethodPut, "/email", dto.ChangeEmailRequest{CurrentPassword: testPassword, NewEmail: bob.Email}, session.cookie).
+		expect(t, http.StatusConflict, apierr.CodeUserAlreadyExists)
+
+	// Adres bağlantı gönderildikten sonra başka bir hesaba alınırsa onay reddedilir.
+	env.do(http.MethodPut, "/email", dto.ChangeEmailRequest{CurrentPassword: testPassword, NewEmail: "carol@spoti.test"}, session.cookie).
+		expect(t, http.StatusOK, "")
+	token := env.mailToken("/confirm-email")
+	env.createUser("carol", auth.RoleUser)
+	env.do(http.MethodPost, "/confirm-email", dto.ConfirmEmailChangeRequest{Token: token}, "").
+		expect(t, http.StatusConflict, apierr.CodeUserAlreadyExists)
+
+	got, err := env.mem.Users().GetByID(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Email != user.Email {
+		t.Fatalf("çakışmadan sonra adres = %s, beklenen %s", got.Email, user.Email)
+	}
+}
@@ -1,109 +1,117 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"log"
 
-	"spoti/models"
+	"spoti/apierr"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/middleware"
+	"spoti/repository"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v4"
 )
 
-// GetAllUsers, veritabanındaki tüm kullanıcıları listeler.
-func GetAllUsers(c *fiber.Ctx) error {
-	var users []models.User
-	// Sorguya username ve email sütunları eklendi.
-	rows, err := DB.Query(context.Background(), `SELECT id, username, email, role_id, hesap_turu, cash FROM t_users`)
+// GetAllUsers, kullanıcıları kullanıcı adına göre sıralayarak sayfa sayfa listeler.
+func (h *Handler) GetAllUsers(c *fiber.Ctx) error {
+	q := middleware.Query[dto.ListQuery](c)
+	page, err := pageRequest[repository.UserKey](q.Cursor, q.PageSize, q.IncludeTotal)
 	if err != nil {
-		log.Println("Tüm kullanıcıları sorgulama hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kullanıcılar listelenemedi."})
+		return err
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var user models.User
-		// Scan fonksiyonu, sorgudaki yeni sütunları içerecek şekilde güncellendi.
-		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.RoleID, &user.HesapTuru, &user.Cash); err != nil {
-			log.Println("Satır tarama hatası:", err)
-			continue
-		}
-		users = append(users, user)
+
+	users, info, err := h.Users.List(c.UserContext(), page)
+	if err != nil {
+		log.Println("Tüm kullanıcıları sorgulama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "user.list_failed")
 	}
 
-	return c.JSON(users)
+	return c.JSON(dto.UserPage{
+		Users:    dto.NewAdminUsers(users),
+		PageInfo: newPageInfo(users, info, q.PageSize, repository.NewUserKey),
+	})
 }
 
 // GetUserByID, belirli bir kullanıcıyı ID'sine göre getirir (Admin yetkilendirmesi gereklidir).
-func GetUserByID(c *fiber.Ctx) error {
+func (h *Handler) GetUserByID(c *fiber.Ctx) error {
 	userID := c.Params("userID")
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz kullanıcı ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "user.invalid_id")
 	}
 
-	var user models.User
-	// Sorguya username ve email sütunları eklendi.
-	query := `SELECT id, username, email, role_id, hesap_turu, cash FROM t_users WHERE id = $1`
-	// Scan fonksiyonu, sorgudaki yeni sütunları içerecek şekilde güncellendi.
-	err = DB.QueryRow(context.Background(), query, parsedUserID).Scan(&user.ID, &user.Username, &user.Email, &user.RoleID, &user.HesapTuru, &user.Cash)
+	user, err := h.Users.GetByID(c.UserContext(), parsedUserID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Kullanıcı bulunamadı."})
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.not_found")
 		}
 		log.Println("Kullanıcı ID ile sorgu hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kullanıcı bilgileri alınamadı."})
+		return apierr.New(apierr.CodeInternal, "user.fetch_failed")
 	}
 
-	return c.JSON(user)
+	return c.JSON(dto.NewAdminUser(user))
 }
 
-// UpdateUserByID, belirli bir kullanıcının bilgilerini günceller.
-func UpdateUserByID(c *fiber.Ctx) error {
+// UpdateUserByID, belirli bir kullanıcının bilgilerini günceller. Çağırandan yetkili
+// kullanıcılar güncellenemez (bkz. checkTargetRole).
+func (h *Handler) UpdateUserByID(c *fiber.Ctx) error {
 	userID := c.Params("userID")
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz kullanıcı ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "user.invalid_id")
 	}
 
-	var updatedUser models.User
-	if err := c.BodyParser(&updatedUser); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi."})
-	}
+	req := middleware.Body[dto.UpdateAccountRequest](c)
 
-	query := `UPDATE t_users SET hesap_turu = $1, cash = $2 WHERE id = $3`
-	commandTag, err := DB.Exec(context.Background(), query, updatedUser.HesapTuru, updatedUser.Cash, parsedUserID)
-	if err != nil {
-		log.Println("Veritabanı güncelleme hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kullanıcı bilgileri güncellenemedi."})
+	if err := h.checkTargetRole(c, parsedUserID, "user.update_not_found"); err != nil {
+		return err
 	}
-
-	if commandTag.RowsAffected() == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Güncellenecek kullanıcı bulunamadı."})
+	if err := h.Users.UpdateAccount(c.UserContext(), parsedUserID, req.HesapTuru, req.Cash); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.update_not_found")
+		}
+		log.Println("Veritabanı güncelleme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "user.update_failed")
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Kullanıcı başarıyla güncellendi."})
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "user.updated")})
 }
 
-// DeleteUserByID, belirli bir kullanıcıyı ID'sine göre siler.
-func DeleteUserByID(c *fiber.Ctx) error {
+// DeleteUserByID, belirli bir kullanıcıyı ID'sine göre siler. Çağırandan yetkili
+// kullanıcılar silinemez (bkz. checkTargetRole).
+func (h *Handler) DeleteUserByID(c *fiber.Ctx) error {
 	userID := c.Params("userID")
 	parsedUserID, err := uuid.Parse(userID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz kullanıcı ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "user.invalid_id")
 	}
 
-	query := `DELETE FROM t_users WHERE id = $1`
-	commandTag, err := DB.Exec(context.Background(), query, parsedUserID)
-	if err != nil {
+	if err := h.checkTargetRole(c, parsedUserID, "user.delete_not_found"); err != nil {
+		return err
+	}
+	if err := h.Users.Delete(c.UserContext(), parsedUserID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.delete_not_found")
+		}
 		log.Println("Veritabanı silme hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kullanıcı silinemedi."})
+		return apierr.New(apierr.CodeInternal, "user.delete_failed")
 	}
 
-	if commandTag.RowsAffected() == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Silinecek kullanıcı bulunamadı."})
+	// Kullanıcının açık oturumları kapatılır. Hesap silindiği için hata yalnızca loglanır.
+	if err := h.revokeAllSessions(c, parsedUserID); err != nil {
+		log.Printf("Silinen hesabın oturumları kapatılamadı (%s): %v\n", parsedUserID, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Kullanıcı başarıyla silindi."})
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "user.deleted")})
+}
+
+// GetDBStats, veritabanı bağlantı havuzunun istatistiklerini döndürür.
+// Yük altında havuz boyutunu ayarlamak için kullanılır.
+func (h *Handler) GetDBStats(c *fiber.Ctx) error {
+	if h.PoolStats == nil {
+		return apierr.New(apierr.CodeNotFound, "admin.db_stats_unavailable")
+	}
+	return c.JSON(h.PoolStats())
 }
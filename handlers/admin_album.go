@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"spoti/apierr"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/middleware"
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Admin, bir albümün adını ve yayın tarihini günceller. Yeni ad albümün şarkılarına da
+// yansır; sanatçının aynı adda başka bir albümü varsa CodeAlbumExists döner.
+func (h *Handler) AdminUpdateAlbum(c *fiber.Ctx) error {
+	albumID, err := uuid.Parse(c.Params("albumID"))
+	if err != nil {
+		return apierr.New(apierr.CodeInvalidID, "album.invalid_id")
+	}
+	req := middleware.Body[dto.AlbumRequest](c)
+
+	album := models.Album{ID: albumID, Title: req.Title}
+	if req.ReleaseDate != "" {
+		// Biçim doğrulama middleware'ında kontrol edildi.
+		date, _ := time.Parse(time.DateOnly, req.ReleaseDate)
+		album.ReleaseDate = &date
+	}
+
+	if err := h.Albums.Update(c.UserContext(), &album); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return apierr.New(apierr.CodeAlbumNotFound, "album.update_not_found")
+		case errors.Is(err, repository.ErrConflict):
+			return apierr.New(apierr.CodeAlbumExists, "album.title_exists")
+		}
+		log.Println("Albüm güncelleme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "album.update_failed")
+	}
+
+	return c.JSON(fiber.Map{"message": i18n.Msg(c, "album.updated")})
+}
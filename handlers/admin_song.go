@@ -1,78 +1,92 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"log"
 
+	"spoti/apierr"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/middleware"
 	"spoti/models"
+	"spoti/repository"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 )
 
 // Admin, yeni bir şarkı ekler.
-func AdminCreateSong(c *fiber.Ctx) error {
-	var song models.Song
-	if err := c.BodyParser(&song); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi."})
-	}
+func (h *Handler) AdminCreateSong(c *fiber.Ctx) error {
+	req := middleware.Body[dto.SongRequest](c)
+	song := songFromRequest(req)
 
 	// Veritabanına yeni şarkıyı ekle
-	query := `INSERT INTO t_songs (title, artist, album, duration) VALUES ($1, $2, $3, $4) RETURNING id`
-	err := DB.QueryRow(context.Background(), query, song.Title, song.Artist, song.Album, song.Duration).Scan(&song.ID)
-	if err != nil {
+	if err := h.Songs.Create(c.UserContext(), &song); err != nil {
 		log.Println("Şarkı ekleme hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Şarkı eklenemedi."})
+		return apierr.New(apierr.CodeInternal, "song.create_failed")
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Şarkı başarıyla eklendi.", "song_id": song.ID})
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": i18n.Msg(c, "song.created"), "song_id": song.ID})
 }
 
 // Admin, bir şarkıyı siler.
-func AdminDeleteSong(c *fiber.Ctx) error {
+func (h *Handler) AdminDeleteSong(c *fiber.Ctx) error {
 	songID := c.Params("songID")
 	parsedSongID, err := uuid.Parse(songID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz şarkı ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "song.invalid_id")
 	}
 
-	commandTag, err := DB.Exec(context.Background(), `DELETE FROM t_songs WHERE id = $1`, parsedSongID)
-	if err != nil {
+	if err := h.Songs.Delete(c.UserContext(), parsedSongID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeSongNotFound, "song.delete_not_found")
+		}
 		log.Println("Şarkı silme hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Şarkı silinemedi."})
+		return apierr.New(apierr.CodeInternal, "song.delete_failed")
 	}
 
-	if commandTag.RowsAffected() == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Silinecek şarkı bulunamadı."})
-	}
-
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Şarkı başarıyla silindi."})
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "song.deleted")})
 }
 
 // Admin, bir şarkının bilgilerini günceller.
-func AdminUpdateSong(c *fiber.Ctx) error {
+func (h *Handler) AdminUpdateSong(c *fiber.Ctx) error {
 	songID := c.Params("songID")
 	parsedSongID, err := uuid.Parse(songID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz şarkı ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "song.invalid_id")
 	}
 
-	var updatedSong models.Song
-	if err := c.BodyParser(&updatedSong); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi."})
-	}
+	req := middleware.Body[dto.SongRequest](c)
+	updatedSong := songFromRequest(req)
+	updatedSong.ID = parsedSongID
 
 	// Veritabanında güncelleme yap
-	query := `UPDATE t_songs SET title = $1, artist = $2, album = $3, duration = $4 WHERE id = $5`
-	commandTag, err := DB.Exec(context.Background(), query, updatedSong.Title, updatedSong.Artist, updatedSong.Album, updatedSong.Duration, parsedSongID)
-	if err != nil {
+	if err := h.Songs.Update(c.UserContext(), &updatedSong); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeSongNotFound, "song.update_not_found")
+		}
 		log.Println("Şarkı güncelleme hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Şarkı güncellenemedi."})
+		return apierr.New(apierr.CodeInternal, "song.update_failed")
 	}
 
-	if commandTag.RowsAffected() == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Güncellenecek şarkı bulunamadı."})
-	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "song.updated")})
+}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Şarkı başarıyla güncellendi."})
+// songFromRequest, ekleme ve güncelleme isteğinin alanlarıyla bir şarkı modeli oluşturur.
+func songFromRequest(req *dto.SongRequest) models.Song {
+	song := models.Song{Title: req.Title, Artist: req.Artist, Album: req.Album, Duration: req.Duration, Genre: req.Genre}
+	if req.ReleaseYear != 0 {
+		year := req.ReleaseYear
+		song.ReleaseYear = &year
+	}
+	if req.TrackNumber != 0 {
+		track := req.TrackNumber
+		song.TrackNumber = &track
+	}
+	// Ana sanatçı her zaman ilk sıradadır; depo adları kayıtlı sanatçılara çözer.
+	song.Artists = []models.SongArtist{{Name: req.Artist}}
+	for _, name := range req.Featuring {
+		song.Artists = append(song.Artists, models.SongArtist{Name: name})
+	}
+	return song
 }
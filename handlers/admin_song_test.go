@@ -0,0 +1,108 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"spoti/apierr"
+	"spoti/dto"
+	"spoti/middleware"
+	"spoti/models"
+
+	"github.com/google/uuid"
+)
+
+// mountCatalog, şarkı ekleme, albüm güncelleme ve sanatçı rotalarını ekler.
+func mountCatalog(env *testEnv) {
+	env.app.Post("/song", middleware.ValidateBody[dto.SongRequest](), env.h.AdminCreateSong)
+	env.app.Put("/album/:albumID", middleware.ValidateBody[dto.AlbumRequest](), env.h.AdminUpdateAlbum)
+	env.app.Get("/artist/:artistID/albums", env.h.GetArtistAlbums)
+}
+
+func createSongRequest(t *testing.T, env *testEnv, req dto.SongRequest) *models.Song {
+	t.Helper()
+	resp := env.do(http.MethodPost, "/song", req, "")
+	resp.expect(t, http.StatusCreated, "")
+	var body struct {
+		SongID uuid.UUID `json:"song_id"`
+	}
+	resp.decode(t, &body)
+	song, err := env.mem.Songs().GetByID(context.Background(), body.SongID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return song
+}
+
+func TestCreateSongNormalisesNames(t *testing.T) {
+	env := newTestEnv(t)
+	mountCatalog(env)
+
+	first := createSongRequest(t, env, dto.SongRequest{Title: "Şımarık", Artist: "Tarkan", Album: "Ölürüm Sana", Duration: 240})
+	second := createSongRequest(t, env, dto.SongRequest{
+		Title: "Şıkıdım", Artist: "  TARKAN ", Album: "ölürüm   sana", Duration: 230,
+		Featuring: []string{"Sezen Aksu", "sezen  aksu", "tarkan"},
+	})
+
+	// Boşluk ve harf farkı olan adlar ilk yazımıyla kayıtlı sanatçıya ve albüme çözülür.
+	if first.Artists[0].ID != second.Artists[0].ID || second.Artists[0].Name != "Tarkan" {
+		t.Fatalf("sanatçılar birleşmedi: %+v / %+v", first.Artists, second.Artists)
+	}
+	if first.AlbumID == nil || second.AlbumID == nil || *first.AlbumID != *second.AlbumID || second.Album != "Ölürüm Sana" {
+		t.Fatalf("albümler birleşmedi: %v %q / %v %q", first.AlbumID, first.Album, second.AlbumID, second.Album)
+	}
+	// Aynı sanatçı farklı yazımlarla birden çok kez anılsa da bir kez yer alır.
+	if len(second.Artists) != 2 || second.Artists[1].Name != "Sezen Aksu" || second.Artist != "Tarkan, Sezen Aksu" {
+		t.Fatalf("sanatçı listesi = %+v (%q)", second.Artists, second.Artist)
+	}
+
+	var body struct {
+		Albums []models.Album `json:"albums"`
+	}
+	resp := env.do(http.MethodGet, "/artist/"+first.Artists[0].ID.String()+"/albums", nil, "")
+	resp.expect(t, http.StatusOK, "")
+	resp.decode(t, &body)
+	if len(body.Albums) != 1 {
+		t.Fatalf("sanatçının albümleri = %s", resp.body)
+	}
+}
+
+func TestUpdateAlbumTitle(t *testing.T) {
+	env := newTestEnv(t)
+	mountCatalog(env)
+	song := createSongRequest(t, env, dto.SongRequest{Title: "Şımarık", Artist: "Tarkan", Album: "Ölürüm Sana", Duration: 240})
+	createSongRequest(t, env, dto.SongRequest{Title: "Kuzu Kuzu", Artist: "Tarkan", Album: "Karma", Duration: 230})
+	// Başka bir sanatçının aynı adlı albümü çakışma sayılmaz.
+	createSongRequest(t, env, dto.SongRequest{Title: "Başka", Artist: "Sezen Aksu", Album: "Ölürüm  Sana 2", Duration: 200})
+	path := "/album/" + song.AlbumID.String()
+
+	tests := []struct {
+		name   string
+		path   string
+		req    dto.AlbumRequest
+		status int
+		code   apierr.Code
+	}{
+		{"geçersiz ID", "/album/abc", dto.AlbumRequest{Title: "Yeni"}, http.StatusBadRequest, apierr.CodeInvalidID},
+		{"olmayan albüm", "/album/" + uuid.NewString(), dto.AlbumRequest{Title: "Yeni"}, http.StatusNotFound, apierr.CodeAlbumNotFound},
+		{"geçersiz tarih", path, dto.AlbumRequest{Title: "Yeni", ReleaseDate: "1999-13-01"}, http.StatusBadRequest, apierr.CodeValidation},
+		{"sanatçının başka albümüyle aynı ad", path, dto.AlbumRequest{Title: " KARMA "}, http.StatusConflict, apierr.CodeAlbumExists},
+		{"başka sanatçının albümüyle aynı ad", path, dto.AlbumRequest{Title: "ölürüm sana 2"}, http.StatusOK, ""},
+		{"aynı albümün farklı yazımı", path, dto.AlbumRequest{Title: "Ölürüm   Sana", ReleaseDate: "1997-06-01"}, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env.do(http.MethodPut, tt.path, tt.req, "").expect(t, tt.status, tt.code)
+		})
+	}
+
+	// Yeni ad, boşlukları sadeleştirilmiş olarak albümün şarkılarına da yansır.
+	updated, err := env.mem.Songs().GetByID(context.Background(), song.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Album != "Ölürüm Sana" {
+		t.Fatalf("şarkının albüm adı = %q", updated.Album)
+	}
+}
@@ -0,0 +1,61 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/middleware"
+	"spoti/repository"
+)
+
+func TestDeleteUserByIDChecksTargetRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller string
+		target string
+		status int
+		code   apierr.Code
+	}{
+		{"destek admini silemez", auth.RoleSupport, auth.RoleAdmin, http.StatusForbidden, apierr.CodeForbidden},
+		{"destek içerik editörünü silemez", auth.RoleSupport, auth.RoleContentEditor, http.StatusForbidden, apierr.CodeForbidden},
+		{"destek başka bir destek hesabını siler", auth.RoleSupport, auth.RoleSupport, http.StatusOK, ""},
+		{"destek kullanıcıyı siler", auth.RoleSupport, auth.RoleUser, http.StatusOK, ""},
+		{"admin destek hesabını siler", auth.RoleAdmin, auth.RoleSupport, http.StatusOK, ""},
+		{"admin başka bir admini silemez", auth.RoleAdmin, auth.RoleAdmin, http.StatusForbidden, apierr.CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			caller := env.createUser("caller", tt.caller)
+			target := env.createUser("target", tt.target)
+			env.app.Delete("/admin/user/:userID", as(caller.ID), env.h.DeleteUserByID)
+
+			env.do(http.MethodDelete, "/admin/user/"+target.ID.String(), nil, "").expect(t, tt.status, tt.code)
+
+			_, err := env.mem.Users().GetByID(context.Background(), target.ID)
+			if deleted := errors.Is(err, repository.ErrNotFound); deleted != (tt.status == http.StatusOK) {
+				t.Fatalf("kullanıcı silindi = %v, beklenen %v", deleted, tt.status == http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestSupportCannotManageAdmin(t *testing.T) {
+	env := newTestEnv(t)
+	support := env.createUser("support", auth.RoleSupport)
+	admin := env.createUser("admin", auth.RoleAdmin)
+	env.app.Put("/admin/user/:userID", as(support.ID), middleware.ValidateBody[dto.UpdateAccountRequest](), env.h.UpdateUserByID)
+	env.app.Post("/admin/user/:userID/logout", as(support.ID), env.h.ForceLogoutUser)
+	env.app.Delete("/admin/lockouts/user/:userID", as(support.ID), env.h.ClearUserLockout)
+
+	path := "/admin/user/" + admin.ID.String()
+	env.do(http.MethodPut, path, dto.UpdateAccountRequest{HesapTuru: "Premium", Cash: 1000}, "").expect(t, http.StatusForbidden, apierr.CodeForbidden)
+	env.do(http.MethodPost, path+"/logout", nil, "").expect(t, http.StatusForbidden, apierr.CodeForbidden)
+	env.do(http.MethodDelete, "/admin/lockouts/user/"+admin.ID.String(), nil, "").expect(t, http.StatusForbidden, apierr.CodeForbidden)
+	env.do(http.MethodPost, "/admin/user/00000000-0000-0000-0000-000000000001/logout", nil, "").expect(t, http.StatusNotFound, apierr.CodeUserNotFound)
+}
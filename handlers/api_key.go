@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"slices"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/middleware"
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// CreateAPIKey, oturumdaki kullanıcı için kapsamlı yeni bir API anahtarı oluşturur.
+// Anahtarın kendisi yalnızca bu yanıtta döner; sonradan tekrar gösterilemez.
+func (h *Handler) CreateAPIKey(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+
+	req := middleware.Body[dto.CreateAPIKeyRequest](c)
+
+	// admin:* kapsamı yalnızca admin uçlarına erişebilen, yani en az bir izni olan
+	// kullanıcıların anahtarlarına verilebilir. Anahtar, rolün izinlerinden fazlasını açmaz.
+	if slices.Contains(req.Scopes, auth.ScopeAdminAll) {
+		role, err := h.Permissions.Role(c.UserContext(), userID)
+		if err != nil {
+			log.Println("API anahtarı için rol sorgulama hatası:", err)
+			return apierr.New(apierr.CodeInternal, "auth.permission_check_failed")
+		}
+		if len(role.Permissions) == 0 {
+			return apierr.New(apierr.CodeAdminRequired, "api_key.admin_scope_forbidden")
+		}
+	}
+
+	plain, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		log.Println("API anahtarı üretme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "api_key.create_failed")
+	}
+	key := models.APIKey{
+		UserID:  userID,
+		Name:    req.Name,
+		Prefix:  prefix,
+		KeyHash: hash,
+		Scopes:  slices.Compact(slices.Sorted(slices.Values(req.Scopes))),
+	}
+	if err := h.APIKeys.Create(c.UserContext(), &key); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return apierr.New(apierr.CodeAPIKeyExists, "api_key.name_taken")
+		}
+		log.Println("API anahtarı kaydetme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "api_key.create_failed")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CreatedAPIKey{APIKey: dto.NewAPIKey(&key), Key: plain})
+}
+
+// ListAPIKeys, oturumdaki kullanıcının API anahtarlarını listeler.
+func (h *Handler) ListAPIKeys(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+
+	keys, err := h.APIKeys.ListByUser(c.UserContext(), userID)
+	if err != nil {
+		log.Println("API anahtarlarını listeleme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "api_key.list_failed")
+	}
+
+	return c.JSON(dto.NewAPIKeys(keys))
+}
+
+// RevokeAPIKey, oturumdaki kullanıcının bir API anahtarını iptal eder.
+func (h *Handler) RevokeAPIKey(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+
+	keyID, err := uuid.Parse(c.Params("keyID"))
+	if err != nil {
+		return apierr.New(apierr.CodeInvalidID, "api_key.invalid_id")
+	}
+
+	if err := h.APIKeys.Delete(c.UserContext(), keyID, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeAPIKeyNotFound, "api_key.not_found")
+		}
+		log.Println("API anahtarı silme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "api_key.revoke_failed")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "api_key.revoked")})
+}
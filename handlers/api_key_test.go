@@ -0,0 +1,135 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/middleware"
+)
+
+// mountAPIKeys, API anahtarı yönetimini ve kapsamla korunan çalma listesi rotalarını
+// uygulamadaki gibi ekler.
+func mountAPIKeys(env *testEnv) {
+	authRequired := middleware.AuthRequired(env.h.AuthConfig())
+	env.app.Post("/login", middleware.ValidateBody[dto.LoginRequest](), env.h.LoginUser)
+	env.app.Post("/api-keys", authRequired, env.h.RequireVerified(auth.RestrictAPIKeys), middleware.ValidateBody[dto.CreateAPIKeyRequest](), env.h.CreateAPIKey)
+	env.app.Get("/api-keys", authRequired, env.h.ListAPIKeys)
+	env.app.Delete("/api-keys/:keyID", authRequired, env.h.RevokeAPIKey)
+	env.app.Get("/playlist", authRequired, middleware.RequireScope(auth.ScopePlaylistsRead), middleware.ValidateListQuery, env.h.GetUserPlaylists)
+	env.app.Post("/playlist", authRequired, middleware.RequireScope(auth.ScopePlaylistsWrite), middleware.ValidateBody[dto.CreatePlaylistRequest](), env.h.CreatePlaylist)
+	env.app.Get("/me", authRequired, env.h.GetUser)
+}
+
+// createAPIKey, session'la verilen kapsamlarda bir anahtar oluşturur.
+func createAPIKey(t *testing.T, env *testEnv, cookie, name string, scopes ...string) dto.CreatedAPIKey {
+	t.Helper()
+	resp := env.do(http.MethodPost, "/api-keys", dto.CreateAPIKeyRequest{Name: name, Scopes: scopes}, cookie)
+	resp.expect(t, http.StatusCreated, "")
+	var key dto.CreatedAPIKey
+	resp.decode(t, &key)
+	return key
+}
+
+func TestAPIKeyScopesAreEnforced(t *testing.T) {
+	env := newTestEnv(t)
+	mountAPIKeys(env)
+	user := env.createUser("alice", auth.RoleUser)
+	session := login(env, user.Email, testPassword)
+	session.expect(t, http.StatusOK, "")
+
+	reader := createAPIKey(t, env, session.cookie, "okuyucu", auth.ScopePlaylistsRead)
+	writer := createAPIKey(t, env, session.cookie, "yazici", auth.ScopePlaylistsWrite)
+
+	env.doBearer(http.MethodGet, "/playlist", nil, reader.Key).expect(t, http.StatusOK, "")
+	env.doBearer(http.MethodPost, "/playlist", dto.CreatePlaylistRequest{Name: "Yolculuk"}, reader.Key).
+		expect(t, http.StatusForbidden, apierr.CodeInsufficientScope)
+
+	// Yazma kapsamı okumayı kapsamaz.
+	env.doBearer(http.MethodPost, "/playlist", dto.CreatePlaylistRequest{Name: "Yolculuk"}, writer.Key).expect(t, http.StatusCreated, "")
+	env.doBearer(http.MethodGet, "/playlist", nil, writer.Key).expect(t, http.StatusForbidden, apierr.CodeInsufficientScope)
+
+	// RequireScope kullanmayan rotalar API anahtarını oturum saymaz.
+	env.doBearer(http.MethodGet, "/me", nil, reader.Key).expect(t, http.StatusUnauthorized, "")
+}
+
+func TestAPIKeyAdminScopeRequiresAdminRole(t *testing.T) {
+	env := newTestEnv(t)
+	mountAPIKeys(env)
+	user := env.createUser("alice", auth.RoleUser)
+	session := login(env, user.Email, testPassword)
+	session.expect(t, http.StatusOK, "")
+
+	env.do(http.MethodPost, "/api-keys", dto.CreateAPIKeyRequest{Name: "admin", Scopes: []string{auth.ScopeAdminAll}}, session.cookie).
+		expect(t, http.StatusForbidden, apierr.CodeAdminRequired)
+}
+
+func TestRevokedAPIKeyIsRejected(t *testing.T) {
+	env := newTestEnv(t)
+	mountAPIKeys(env)
+	user := env.createUser("alice", auth.RoleUser)
+	session := login(env, user.Email, testPassword)
+	session.expect(t, http.StatusOK, "")
+	key := createAPIKey(t, env, session.cookie, "betik", auth.ScopePlaylistsRead)
+	env.doBearer(http.MethodGet, "/playlist", nil, key.Key).expect(t, http.StatusOK, "")
+
+	env.do(http.MethodDelete, "/api-keys/"+key.ID.String(), nil, session.cookie).expect(t, http.StatusOK, "")
+	env.doBearer(http.MethodGet, "/playlist", nil, key.Key).expect(t, http.StatusUnauthorized, apierr.CodeAPIKeyInvalid)
+	env.do(http.MethodDelete, "/api-keys/"+key.ID.String(), nil, session.cookie).expect(t, http.StatusNotFound, apierr.CodeAPIKeyNotFound)
+
+	// Bilinmeyen anahtarlar da aynı hatayı alır.
+	env.doBearer(http.MethodGet, "/playlist", nil, "spk_bilinmeyen").expect(t, http.StatusUnauthorized, apierr.CodeAPIKeyInvalid)
+}
+
+func TestAPIKeyStopsWorkingWhenOwnerIsDeleted(t *testing.T) {
+	env := newTestEnv(t)
+	mountAPIKeys(env)
+	user := env.createUser("alice", auth.RoleUser)
+	session := login(env, user.Email, testPassword)
+	session.expect(t, http.StatusOK, "")
+	key := createAPIKey(t, env, session.cookie, "betik", auth.ScopePlaylistsRead)
+
+	if err := env.mem.Users().Delete(context.Background(), user.ID); err != nil {
+		t.Fatal(err)
+	}
+	env.doBearer(http.MethodGet, "/playlist", nil, key.Key).expect(t, http.StatusUnauthorized, apierr.CodeAPIKeyInvalid)
+}
+
+func TestAPIKeyCannotManageAPIKeys(t *testing.T) {
+	tests := []struct {
+		name         string
+		restrictions []string
+	}{
+		{"kısıtlama yok", nil},
+		// Anahtar oluşturma doğrulanmış hesaplara kısıtlandığında istek önce RequireVerified'dan geçer.
+		{"doğrulama kısıtlaması", []string{auth.RestrictAPIKeys}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.h.UnverifiedRestrictions = tt.restrictions
+			mountAPIKeys(env)
+			user := env.createUser("alice", auth.RoleUser)
+			session := login(env, user.Email, testPassword)
+			session.expect(t, http.StatusOK, "")
+			key := createAPIKey(t, env, session.cookie, "betik", auth.ScopePlaylistsRead, auth.ScopePlaylistsWrite, auth.ScopeSongsRead)
+
+			// Ele geçirilen bir anahtar kendine yeni anahtar çıkaramaz, anahtarları göremez ve iptal edemez.
+			env.doBearer(http.MethodPost, "/api-keys", dto.CreateAPIKeyRequest{Name: "yeni", Scopes: []string{auth.ScopeSongsRead}}, key.Key).
+				expect(t, http.StatusUnauthorized, apierr.CodeUnauthenticated)
+			env.doBearer(http.MethodGet, "/api-keys", nil, key.Key).expect(t, http.StatusUnauthorized, apierr.CodeUnauthenticated)
+			env.doBearer(http.MethodDelete, "/api-keys/"+key.ID.String(), nil, key.Key).expect(t, http.StatusUnauthorized, apierr.CodeUnauthenticated)
+
+			list, err := env.mem.APIKeys().ListByUser(context.Background(), user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != 1 || list[0].ID != key.ID {
+				t.Fatalf("anahtarlar = %+v, beklenen yalnızca session'la oluşturulan", list)
+			}
+		})
+	}
+}
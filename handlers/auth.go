@@ -1,106 +1,172 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"log"
+	"time"
 
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/middleware"
 	"spoti/models"
+	"spoti/repository"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v4"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // RegisterUser, yeni bir kullanıcı kaydı oluşturur.
-func RegisterUser(c *fiber.Ctx) error {
-	var user models.User
-	if err := c.BodyParser(&user); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi."})
+func (h *Handler) RegisterUser(c *fiber.Ctx) error {
+	req := middleware.Body[dto.RegisterRequest](c)
+	if err := h.checkPasswordPolicy("password", req.Password); err != nil {
+		return err
 	}
 
-	var userRoleID uuid.UUID
-	err := DB.QueryRow(context.Background(), "SELECT id FROM t_roles WHERE name = 'user'").Scan(&userRoleID)
-	if err == pgx.ErrNoRows {
+	hashedPassword, err := auth.HashPassword(req.Password)
+	if err != nil {
+		log.Printf("Şifre hashleme hatası: %v\n", err)
+		return apierr.New(apierr.CodeInternal, "auth.hash_failed")
+	}
+	user := models.User{
+		ID:        uuid.New(),
+		Username:  req.Username,
+		Email:     req.Email,
+		Password:  hashedPassword,
+		HesapTuru: "Free",
+		Cash:      100.00,
+	}
 
+	err = h.Users.Create(c.UserContext(), &user, "user")
+	if errors.Is(err, repository.ErrNotFound) {
 		log.Println("Veritabanında 'user' rolü bulunamadı.")
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "'user' rolü veritabanında bulunamadı. Lütfen yöneticiyle iletişime geçin."})
+		return apierr.New(apierr.CodeInternal, "auth.user_role_missing")
 	}
-	if err != nil {
-		log.Printf("'user' rolü sorgulama hatası: %v\n", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Rol ataması sırasında bir hata oluştu."})
+	if errors.Is(err, repository.ErrConflict) {
+		return apierr.New(apierr.CodeUserAlreadyExists, "auth.user_exists")
 	}
-
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
-		log.Printf("Şifre hashleme hatası: %v\n", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Şifre hashleme hatası."})
+		log.Printf("Kullanıcı kaydı oluşturma hatası: %v\n", err)
+		return apierr.New(apierr.CodeInternal, "auth.register_failed")
 	}
-	user.Password = string(hashedPassword)
-	user.ID = uuid.New()
-	user.HesapTuru = "Free"
-	user.Cash = 100.00
-	user.RoleID = userRoleID
 
-	query := `INSERT INTO t_users (id, username, email, password, hesap_turu, cash, role_id) VALUES ($1, $2, $3, $4, $5, $6, $7)`
-	_, err = DB.Exec(context.Background(), query, user.ID, user.Username, user.Email, user.Password, user.HesapTuru, user.Cash, user.RoleID)
-	if err != nil {
-		log.Printf("Kullanıcı kaydı oluşturma hatası: %v\n", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kullanıcı kaydı yapılamadı."})
+	// Doğrulama e-postası gönderilemese de kayıt geçerlidir; kullanıcı yeniden gönderim isteyebilir.
+	if err := h.sendVerification(c, &user); err != nil {
+		log.Printf("Doğrulama e-postası gönderilemedi (%s): %v\n", user.Email, err)
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Kullanıcı başarıyla kaydedildi.", "userID": user.ID})
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": i18n.Msg(c, "auth.registered"), "userID": user.ID})
 }
 
 // LoginUser, kullanıcının oturum açmasını sağlar.
-func LoginUser(c *fiber.Ctx) error {
-	var loginData struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+func (h *Handler) LoginUser(c *fiber.Ctx) error {
+	loginData := middleware.Body[dto.LoginRequest](c)
+
+	// Kilitli veya beklemesi gereken hesaplarda şifre hiç kontrol edilmez.
+	block, err := h.Lockout.Check(loginData.Email, c.IP(), time.Now())
+	if err != nil {
+		log.Println("Giriş denemesi sayacı okuma hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.login_failed")
 	}
-	if err := c.BodyParser(&loginData); err != nil {
-		log.Printf("Giriş verilerini çözümleme hatası: %v\n", err)
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi."})
+	if block != nil {
+		return loginBlocked(block)
 	}
 
-	var user models.User
-	query := `SELECT id, password FROM t_users WHERE email = $1`
-	err := DB.QueryRow(context.Background(), query, loginData.Email).Scan(&user.ID, &user.Password)
+	user, err := h.Users.GetByEmail(c.UserContext(), loginData.Email)
 	if err != nil {
 		log.Printf("Kullanıcı bulunamadı veya veritabanı hatası: %v\n", err)
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Geçersiz e-posta veya şifre."})
+		if errors.Is(err, repository.ErrNotFound) {
+			// Kayıtlı olmayan adresler de sayılır; aksi hâlde kilit davranışı adresin varlığını sızdırır.
+			return h.loginFailed(c, loginData.Email)
+		}
+		return apierr.New(apierr.CodeInvalidCredentials, "auth.invalid_credentials")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginData.Password)); err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Geçersiz e-posta veya şifre."})
+	if !auth.CheckPassword(user.Password, loginData.Password) {
+		return h.loginFailed(c, loginData.Email)
+	}
+
+	// İki adımlı doğrulaması etkin kullanıcılar için giriş, kod doğrulanana kadar tamamlanmaz.
+	// Sayaç da ancak o zaman sıfırlanır; aksi hâlde şifreyi bilen biri her yeni denemede
+	// kodu sınırsızca tahmin edebilirdi.
+	twoFactor, err := h.TwoFactor.Get(c.UserContext(), user.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Println("İki adımlı doğrulama sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.login_failed")
+	}
+	if err == nil && twoFactor.Enabled() {
+		return h.startTwoFactorChallenge(c, user.ID, loginData.Tokens)
 	}
 
-	// Oturum oluştur ve kullanıcı ID'sini sakla
-	sess, err := Store.Get(c)
+	h.loginSucceeded(loginData.Email)
+	return h.completeLogin(c, user, loginData.Tokens)
+}
+
+// completeLogin, kimliği doğrulanmış kullanıcı için tokens true ise bearer token çifti,
+// değilse session oluşturur.
+func (h *Handler) completeLogin(c *fiber.Ctx, user *models.User, tokens bool) error {
+	// Mobil ve CLI istemcileri çerez yerine bearer token ister.
+	if tokens {
+		return h.respondWithTokens(c, user, uuid.New())
+	}
+
+	if err := h.startSession(c, user); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "auth.logged_in")})
+}
+
+// startSession, kullanıcı için yeni ID'li bir session açar ve dizine ekler. İstekte mevcut bir
+// session varsa silinir; böylece girişten veya şifre değişikliğinden önceki session ID'si işe yaramaz.
+func (h *Handler) startSession(c *fiber.Ctx, user *models.User) error {
+	sess, err := h.Store.Get(c)
 	if err != nil {
 		log.Printf("Session oluşturma/alma hatası: %v\n", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Session oluşturulamadı."})
+		return apierr.New(apierr.CodeInternal, "session.create_failed")
+	}
+	if err := sess.Reset(); err != nil {
+		log.Printf("Session yenileme hatası: %v\n", err)
+		return apierr.New(apierr.CodeInternal, "session.create_failed")
 	}
+
+	// Oturuma kullanıcı ID'sini sakla
 	sess.Set("userID", user.ID)
+	// authAt, session'ın şifre sıfırlama gibi toplu iptallerden önce mi açıldığını anlamak için tutulur.
+	sess.Set("authAt", time.Now())
+	if user.Language != "" {
+		sess.Set("lang", user.Language)
+	}
+	// Save session'ı havuza geri bıraktığından ID önceden alınır.
+	sessionID := sess.ID()
 	if err := sess.Save(); err != nil {
 		log.Printf("Session kaydetme hatası: %v\n", err) // Hata kaynağını daha iyi anlamak için log ekledik.
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Session kaydedilemedi."})
+		return apierr.New(apierr.CodeInternal, "session.save_failed")
 	}
-
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Giriş başarılı."})
+	// Dizin yalnızca listeleme içindir; yazılamaması girişi engellemez.
+	if err := h.Sessions.Add(user.ID, sessionID, c.IP(), c.Get(fiber.HeaderUserAgent), time.Now()); err != nil {
+		log.Println("Session dizine eklenemedi:", err)
+	}
+	return nil
 }
 
 // LogoutUser, kullanıcının oturumunu kapatır.
-func LogoutUser(c *fiber.Ctx) error {
-	sess, err := Store.Get(c)
+func (h *Handler) LogoutUser(c *fiber.Ctx) error {
+	sess, err := h.Store.Get(c)
 	if err != nil {
 		log.Printf("Session alma hatası: %v\n", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Session alınamadı."})
+		return apierr.New(apierr.CodeInternal, "session.get_failed")
+	}
+	if userID, ok := sess.Get("userID").(uuid.UUID); ok {
+		if err := h.Sessions.Remove(userID, sess.ID()); err != nil {
+			log.Println("Session dizinden çıkarılamadı:", err)
+		}
 	}
 	if err := sess.Destroy(); err != nil {
 		log.Printf("Session sonlandırma hatası: %v\n", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Session sonlandırılamadı."})
+		return apierr.New(apierr.CodeInternal, "session.destroy_failed")
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Çıkış başarılı."})
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "auth.logged_out")})
 }
@@ -0,0 +1,93 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/middleware"
+)
+
+func TestRegisterUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    dto.RegisterRequest
+		status int
+		code   apierr.Code
+	}{
+		{"yeni kullanıcı", dto.RegisterRequest{Username: "bob", Email: "bob@spoti.test", Password: testPassword}, http.StatusCreated, ""},
+		{"kayıtlı e-posta", dto.RegisterRequest{Username: "bob2", Email: "alice@spoti.test", Password: testPassword}, http.StatusConflict, apierr.CodeUserAlreadyExists},
+		{"kayıtlı kullanıcı adı", dto.RegisterRequest{Username: "alice", Email: "bob@spoti.test", Password: testPassword}, http.StatusConflict, apierr.CodeUserAlreadyExists},
+		{"geçersiz e-posta", dto.RegisterRequest{Username: "bob", Email: "bob", Password: testPassword}, http.StatusBadRequest, apierr.CodeValidation},
+		{"kısa şifre", dto.RegisterRequest{Username: "bob", Email: "bob@spoti.test", Password: "kisa"}, http.StatusBadRequest, apierr.CodeValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.createUser("alice", auth.RoleUser)
+			env.app.Post("/register", middleware.ValidateBody[dto.RegisterRequest](), env.h.RegisterUser)
+
+			env.do(http.MethodPost, "/register", tt.req, "").expect(t, tt.status, tt.code)
+
+			// Yalnızca başarılı kayıtta doğrulama e-postası gönderilir.
+			if sent := len(env.mailer.msgs) == 1; sent != (tt.status == http.StatusCreated) {
+				t.Fatalf("gönderilen e-posta sayısı = %d", len(env.mailer.msgs))
+			}
+		})
+	}
+}
+
+func TestLoginUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		status   int
+		code     apierr.Code
+	}{
+		{"doğru şifre", "alice@spoti.test", testPassword, http.StatusOK, ""},
+		{"yanlış şifre", "alice@spoti.test", "yanlis-sifre", http.StatusUnauthorized, apierr.CodeInvalidCredentials},
+		{"kayıtlı olmayan e-posta", "bob@spoti.test", testPassword, http.StatusUnauthorized, apierr.CodeInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.createUser("alice", auth.RoleUser)
+			env.app.Post("/login", middleware.ValidateBody[dto.LoginRequest](), env.h.LoginUser)
+
+			env.do(http.MethodPost, "/login", dto.LoginRequest{Email: tt.email, Password: tt.password}, "").expect(t, tt.status, tt.code)
+		})
+	}
+}
+
+func TestSessionLoginAndLogout(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser("alice", auth.RoleUser)
+	env.app.Post("/login", middleware.ValidateBody[dto.LoginRequest](), env.h.LoginUser)
+	env.app.Post("/logout", env.h.LogoutUser)
+	env.app.Get("/me", middleware.AuthRequired(env.h.AuthConfig()), env.h.GetUser)
+
+	env.do(http.MethodGet, "/me", nil, "").expect(t, http.StatusUnauthorized, "")
+
+	login := env.do(http.MethodPost, "/login", dto.LoginRequest{Email: user.Email, Password: testPassword}, "")
+	login.expect(t, http.StatusOK, "")
+	if login.cookie == "" {
+		t.Fatal("girişte session çerezi verilmedi")
+	}
+
+	me := env.do(http.MethodGet, "/me", nil, login.cookie)
+	me.expect(t, http.StatusOK, "")
+	var self struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	}
+	me.decode(t, &self)
+	if self.ID != user.ID.String() || self.Username != user.Username {
+		t.Fatalf("GET /me = %s, beklenen %s", me.body, user.ID)
+	}
+
+	env.do(http.MethodPost, "/logout", nil, login.cookie).expect(t, http.StatusOK, "")
+	env.do(http.MethodGet, "/me", nil, login.cookie).expect(t, http.StatusUnauthorized, "")
+}
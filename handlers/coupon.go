@@ -2,201 +2,161 @@ package handlers
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log"
 	"sync"
 	"time"
 
+	"spoti/apierr"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/middleware"
 	"spoti/models"
+	"spoti/repository"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v4"
 )
 
 // Race condition'ları önlemek için global mutex
 var mu sync.Mutex
 
+// purchaseWindow, premium satın alma işleminin başlatıldıktan sonra tamamlanması gereken süredir.
+const purchaseWindow = 5 * time.Minute
+
+// purchaseWindowKey, kullanıcının satın alma penceresinin session storage'ındaki anahtarıdır.
+// Pencere session yerine doğrudan storage'da tutulur; böylece bearer token'lı istemciler de
+// satın alma yapabilir.
+func purchaseWindowKey(userID uuid.UUID) string {
+	return "purchase_window:" + userID.String()
+}
+
 // CreateCoupon, adminin yeni bir kupon oluşturmasını sağlar.
-func CreateCoupon(c *fiber.Ctx) error {
-	var coupon models.Coupon
-	if err := c.BodyParser(&coupon); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi."})
-	}
+func (h *Handler) CreateCoupon(c *fiber.Ctx) error {
+	req := middleware.Body[dto.CreateCouponRequest](c)
+	coupon := models.Coupon{Code: req.Code}
 
-	// Kupon kodunun benzersiz olup olmadığını kontrol et
-	var existingCouponID uuid.UUID
-	err := DB.QueryRow(context.Background(), "SELECT id FROM t_cupons WHERE code = $1", coupon.Code).Scan(&existingCouponID)
-	if err != nil && err != pgx.ErrNoRows {
-		log.Println("Kupon kontrol hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kupon oluşturulamadı."})
-	}
-	if err == nil {
-		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Bu kupon kodu zaten mevcut."})
+	// Kupon kodu benzersiz değilse repository ErrConflict döndürür
+	err := h.Coupons.Create(c.UserContext(), &coupon)
+	if errors.Is(err, repository.ErrConflict) {
+		return apierr.New(apierr.CodeCouponAlreadyExists, "coupon.code_exists")
 	}
-
-	query := `INSERT INTO t_cupons (code) VALUES ($1) RETURNING id`
-	err = DB.QueryRow(context.Background(), query, coupon.Code).Scan(&coupon.ID)
 	if err != nil {
 		log.Println("Kupon ekleme hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kupon oluşturulamadı."})
+		return apierr.New(apierr.CodeInternal, "coupon.create_failed")
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Kupon başarıyla oluşturuldu.", "couponID": coupon.ID})
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": i18n.Msg(c, "coupon.created"), "couponID": coupon.ID})
 }
 
 // AssignCoupon, belirli bir kuponu belirli bir kullanıcıya veya tüm kullanıcılara atar.
-func AssignCoupon(c *fiber.Ctx) error {
-	var req struct {
-		CouponID uuid.UUID `json:"cuponID"`
-		UserID   uuid.UUID `json:"userID"`
-	}
-
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi."})
-	}
+func (h *Handler) AssignCoupon(c *fiber.Ctx) error {
+	req := middleware.Body[dto.AssignCouponRequest](c)
 
 	// Kuponun var olup olmadığını kontrol et
-	var exists bool
-	err := DB.QueryRow(context.Background(), "SELECT EXISTS(SELECT 1 FROM t_cupons WHERE id = $1)", req.CouponID).Scan(&exists)
+	exists, err := h.Coupons.Exists(c.UserContext(), req.CouponID)
 	if err != nil {
 		log.Println("Kupon kontrol hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kupon atanamadı."})
+		return apierr.New(apierr.CodeInternal, "coupon.assign_failed")
 	}
 	if !exists {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Kupon bulunamadı."})
+		return apierr.New(apierr.CodeCouponNotFound, "coupon.not_found")
 	}
 
 	// userID null uuid ise tüm kullanıcılara kupon ata
 	if req.UserID == uuid.Nil {
-		query := `UPDATE t_cupons SET user_id = u.id FROM t_users u WHERE t_cupons.id = $1`
-		_, err := DB.Exec(context.Background(), query, req.CouponID)
-		if err != nil {
+		if err := h.Coupons.AssignToAll(c.UserContext(), req.CouponID); err != nil {
 			log.Println("Tüm kullanıcılara kupon atama hatası:", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kupon tüm kullanıcılara atanamadı."})
+			return apierr.New(apierr.CodeInternal, "coupon.assign_all_failed")
 		}
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Kupon tüm kullanıcılara başarıyla atandı."})
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "coupon.assigned_all")})
 	}
 
 	// Belirli bir kullanıcıya kupon ata
-	query := `UPDATE t_cupons SET user_id = $1 WHERE id = $2`
-	commandTag, err := DB.Exec(context.Background(), query, req.UserID, req.CouponID)
-	if err != nil {
+	if err := h.Coupons.AssignToUser(c.UserContext(), req.CouponID, req.UserID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeNotFound, "coupon.or_user_not_found")
+		}
 		log.Println("Kullanıcıya kupon atama hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kupon kullanıcıya atanamadı."})
+		return apierr.New(apierr.CodeInternal, "coupon.assign_user_failed")
 	}
 
-	if commandTag.RowsAffected() == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Kupon veya kullanıcı bulunamadı."})
-	}
-
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Kupon kullanıcıya başarıyla atandı."})
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "coupon.assigned_user")})
 }
 
 // GetUserCoupons, kullanıcının sahip olduğu kuponları listeler.
-func GetUserCoupons(c *fiber.Ctx) error {
-	sess, err := Store.Get(c)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Session alınamadı."})
+func (h *Handler) GetUserCoupons(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "session.user_missing")
 	}
 
-	userID := sess.Get("userID")
-	if userID == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Yetkilendirme hatası: Kullanıcı ID'si session'da bulunamadı."})
-	}
-
-	var coupons []models.Coupon
-	rows, err := DB.Query(context.Background(), `SELECT id, code, is_used FROM t_cupons WHERE user_id = $1`, userID)
+	coupons, err := h.Coupons.ListByUser(c.UserContext(), userID)
 	if err != nil {
 		log.Println("Kuponları sorgulama hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kuponlar listelenemedi."})
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var coupon models.Coupon
-		if err := rows.Scan(&coupon.ID, &coupon.Code, &coupon.IsUsed); err != nil {
-			log.Println("Satır tarama hatası:", err)
-			continue
-		}
-		coupons = append(coupons, coupon)
+		return apierr.New(apierr.CodeInternal, "coupon.list_failed")
 	}
 
 	return c.Status(fiber.StatusOK).JSON(coupons)
 }
 
-// StartPremiumPurchase, kullanıcının premium satın alma işlemini başlatır ve session'a bir zaman damgası ekler.
-func StartPremiumPurchase(c *fiber.Ctx) error {
-	sess, err := Store.Get(c)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Session alınamadı."})
-	}
-
-	// Session'da userID kontrolü
-	if sess.Get("userID") == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Yetkilendirme hatası: Kullanıcı ID'si session'da bulunamadı."})
+// StartPremiumPurchase, kullanıcının premium satın alma işlemini başlatır ve storage'a bir zaman damgası ekler.
+func (h *Handler) StartPremiumPurchase(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "session.user_missing")
 	}
 
-	// Satın alma oturumu için son kullanma zamanı belirle (örneğin 5 dakika)
-	purchaseExpirationTime := time.Now().Add(5 * time.Minute)
-	sess.Set("purchaseExpirationTime", purchaseExpirationTime)
-
-	if err := sess.Save(); err != nil {
-		fmt.Println(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Session kaydedilemedi."})
+	// Satın alma oturumu için son kullanma zamanı belirle; kayıt pencereyle birlikte kendiliğinden silinir.
+	purchaseExpirationTime := time.Now().Add(purchaseWindow)
+	if err := h.Store.Storage.Set(purchaseWindowKey(userID), []byte(purchaseExpirationTime.Format(time.RFC3339Nano)), purchaseWindow); err != nil {
+		log.Println("Satın alma penceresi kaydetme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "session.save_failed")
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Premium satın alma işlemi başlatıldı. İşleminizi tamamlamak için 5 dakikanız var."})
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "premium.purchase_started", int(purchaseWindow.Minutes()))})
 }
 
 // PurchasePremium, kullanıcının premium üyelik satın almasını sağlar.
-func PurchasePremium(c *fiber.Ctx) error {
+func (h *Handler) PurchasePremium(c *fiber.Ctx) error {
 	// Mutex kilidi kullanarak race condition'ı önle
 	mu.Lock()
 	defer mu.Unlock()
 
-	var req struct {
-		UseCoupon bool   `json:"useCoupon"`
-		CouponID  string `json:"couponID"`
-	}
+	req := middleware.Body[dto.PurchasePremiumRequest](c)
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi."})
-	}
-
-	sess, err := Store.Get(c)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Session alınamadı."})
-	}
-
-	// Session'da userID kontrolü
-	userID := sess.Get("userID")
-	if userID == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Yetkilendirme hatası: Kullanıcı ID'si session'da bulunamadı."})
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "session.user_missing")
 	}
 
 	// Kullanıcının mevcut hesap türünü kontrol et
-	var currentUser models.User
-	err = DB.QueryRow(c.Context(), "SELECT hesap_turu FROM t_users WHERE id = $1", userID).Scan(&currentUser.HesapTuru)
-	if err == pgx.ErrNoRows {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Kullanıcı bulunamadı."})
+	currentUser, err := h.Users.GetByID(c.UserContext(), userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierr.New(apierr.CodeUserNotFound, "user.not_found")
 	}
 	if err != nil {
 		log.Println("Hesap türü sorgulama hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İşlem sırasında bir hata oluştu."})
+		return apierr.New(apierr.CodeInternal, "common.operation_error")
 	}
 	if currentUser.HesapTuru == "Premium" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Kullanıcı zaten premium üyedir."})
+		return apierr.New(apierr.CodeAlreadyPremium, "premium.already_premium")
 	}
 
 	// Satın alma oturumu zaman aşımı kontrolü
-	expirationTime, ok := sess.Get("purchaseExpirationTime").(time.Time)
-	if !ok || time.Now().After(expirationTime) {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Satın alma oturumu geçerli değil veya süresi dolmuş. Lütfen işlemi yeniden başlatın."})
+	windowKey := purchaseWindowKey(userID)
+	rawExpiration, err := h.Store.Storage.Get(windowKey)
+	if err != nil {
+		log.Println("Satın alma penceresi okuma hatası:", err)
+		return apierr.New(apierr.CodeInternal, "session.get_failed")
+	}
+	expirationTime, err := time.Parse(time.RFC3339Nano, string(rawExpiration))
+	if err != nil || time.Now().After(expirationTime) {
+		return apierr.New(apierr.CodePurchaseWindowExpired, "premium.window_expired")
 	}
 
 	// Timeout için select ifadesini kullan
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(c.UserContext(), 10*time.Second)
 	defer cancel()
 
 	if req.UseCoupon {
@@ -205,106 +165,72 @@ func PurchasePremium(c *fiber.Ctx) error {
 		if req.CouponID != "" {
 			couponID, err = uuid.Parse(req.CouponID)
 			if err != nil {
-				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz kupon ID formatı."})
+				return apierr.New(apierr.CodeInvalidID, "coupon.invalid_id")
 			}
 		} else {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Kupon ID'si sağlanmadı."})
+			return apierr.Validation(apierr.FieldError{Field: "couponID", Code: "required", Key: "coupon.id_required"})
 		}
 
 		// Kuponun geçerliliğini ve kullanım durumunu kontrol et
-		var coupon models.Coupon
-		err = DB.QueryRow(ctx, "SELECT id, is_used FROM t_cupons WHERE id = $1 AND user_id = $2", couponID, userID).Scan(&coupon.ID, &coupon.IsUsed)
-		if err == pgx.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Kupon bulunamadı veya bu kupon size ait değil."})
+		coupon, err := h.Coupons.GetForUser(ctx, couponID, userID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeCouponNotFound, "coupon.not_owned")
 		}
 		if err != nil {
 			log.Println("Kupon kontrol hatası:", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İşlem sırasında bir hata oluştu."})
+			return apierr.New(apierr.CodeInternal, "common.operation_error")
 		}
 		if coupon.IsUsed {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Bu kupon zaten kullanılmış."})
+			return apierr.New(apierr.CodeCouponAlreadyUsed, "coupon.already_used")
 		}
 
 		// Veritabanı işlemleri için select/timeout
 		select {
 		case <-ctx.Done():
-			return c.Status(fiber.StatusGatewayTimeout).JSON(fiber.Map{"error": "İşlem zaman aşımına uğradı."})
+			return apierr.New(apierr.CodeTimeout, "common.timeout")
 		default:
-			tx, err := DB.Begin(ctx)
-			if err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İşlem başlatılamadı."})
-			}
-			defer tx.Rollback(ctx)
-
-			// Kuponu kullanıldı olarak işaretle
-			if _, err := tx.Exec(ctx, "UPDATE t_cupons SET is_used = TRUE WHERE id = $1", couponID); err != nil {
-				log.Println("Kupon güncelleme hatası:", err)
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İşlem sırasında bir hata oluştu."})
+			// Kuponu kullanıldı olarak işaretle ve hesabı Premium yap
+			err := h.Coupons.Redeem(ctx, couponID, userID)
+			if errors.Is(err, repository.ErrCouponUsed) {
+				return apierr.New(apierr.CodeCouponAlreadyUsed, "coupon.already_used")
 			}
-
-			// Kullanıcının hesap türünü Premium yap
-			if _, err := tx.Exec(ctx, "UPDATE t_users SET hesap_turu = 'Premium' WHERE id = $1", userID); err != nil {
-				log.Println("Kullanıcı güncelleme hatası:", err)
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İşlem sırasında bir hata oluştu."})
-			}
-
-			if err := tx.Commit(ctx); err != nil {
-				log.Println("İşlem taahhüt hatası:", err)
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İşlem tamamlanamadı."})
+			if err != nil {
+				log.Println("Kupon kullanım hatası:", err)
+				return apierr.New(apierr.CodeInternal, "common.operation_failed")
 			}
 
-			// Oturumu temizle
-			sess.Delete("purchaseExpirationTime")
-			sess.Save()
+			// Satın alma penceresini kapat
+			h.Store.Storage.Delete(windowKey)
 
-			return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Premium üyelik kupon ile başarıyla etkinleştirildi."})
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "premium.activated_with_coupon")})
 		}
 	} else {
 		// Nakit kullanarak satın alma
-		var user models.User
-		err = DB.QueryRow(ctx, "SELECT cash FROM t_users WHERE id = $1", userID).Scan(&user.Cash)
-		if err == pgx.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Kullanıcı bulunamadı."})
-		}
-		if err != nil {
-			log.Println("Kullanıcı nakit sorgulama hatası:", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İşlem sırasında bir hata oluştu."})
-		}
-
 		const price = 100.0
 
-		if user.Cash < price {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Yetersiz bakiye."})
+		if currentUser.Cash < price {
+			return apierr.New(apierr.CodeInsufficientFunds, "premium.insufficient_funds")
 		}
 
 		// Veritabanı işlemleri için select/timeout
 		select {
 		case <-ctx.Done():
-			return c.Status(fiber.StatusGatewayTimeout).JSON(fiber.Map{"error": "İşlem zaman aşımına uğradı."})
+			return apierr.New(apierr.CodeTimeout, "common.timeout")
 		default:
-			tx, err := DB.Begin(ctx)
-			if err != nil {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İşlem başlatılamadı."})
-			}
-			defer tx.Rollback(ctx)
-
 			// Kullanıcının bakiyesini güncelle
-			newCash := user.Cash - price
-			if _, err := tx.Exec(ctx, "UPDATE t_users SET cash = $1, hesap_turu = 'Premium' WHERE id = $2", newCash, userID); err != nil {
-				log.Println("Nakit güncelleme hatası:", err)
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İşlem sırasında bir hata oluştu."})
+			newCash, err := h.Users.PurchasePremium(ctx, userID, price)
+			if errors.Is(err, repository.ErrInsufficientFunds) {
+				return apierr.New(apierr.CodeInsufficientFunds, "premium.insufficient_funds")
 			}
-
-			if err := tx.Commit(ctx); err != nil {
-				log.Println("İşlem taahhüt hatası:", err)
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "İşlem tamamlanamadı."})
+			if err != nil {
+				log.Println("Nakit güncelleme hatası:", err)
+				return apierr.New(apierr.CodeInternal, "common.operation_failed")
 			}
 
-			// Oturumu temizle
-			sess.Delete("purchaseExpirationTime")
-			sess.Save()
+			// Satın alma penceresini kapat
+			h.Store.Storage.Delete(windowKey)
 
-			return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Premium üyelik başarıyla satın alındı.", "newCash": newCash})
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "premium.purchased"), "newCash": newCash})
 		}
 	}
 }
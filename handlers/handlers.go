@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"slices"
+	"time"
+
+	"spoti/auth"
+	"spoti/database"
+	"spoti/kv"
+	"spoti/lockout"
+	"spoti/mail"
+	"spoti/middleware"
+	"spoti/oidc"
+	"spoti/rbac"
+	"spoti/repository"
+	"spoti/sessions"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// Deps, handler'ların ihtiyaç duyduğu bağımlılıkları toplar.
+// Testlerde repository alanlarına bellek içi gerçeklemeler verilebilir.
+type Deps struct {
+	Users     repository.UserRepository
+	Songs     repository.SongRepository
+	Artists   repository.ArtistRepository
+	Albums    repository.AlbumRepository
+	Playlists repository.PlaylistRepository
+	Coupons   repository.CouponRepository
+	Roles     repository.RoleRepository
+	Store     *session.Store
+	// KV, Store'un storage'ına atomik sayaç işlemleriyle erişir; aynı storage'ı kullanmalıdır.
+	KV kv.Store
+
+	// Tokens, bearer erişim token'larını imzalar; RefreshTokens yenileme token'larını saklar.
+	Tokens        *auth.TokenManager
+	RefreshTokens repository.RefreshTokenRepository
+	APIKeys       repository.APIKeyRepository
+	UserTokens    repository.UserTokenRepository
+	TwoFactor     repository.TwoFactorRepository
+
+	// OIDC, harici sağlayıcıyla girişi yürütür; yapılandırılmamışsa nil'dir.
+	// Identities, sağlayıcı hesaplarının kullanıcılara bağlantılarını saklar.
+	OIDC       *oidc.Provider
+	Identities repository.IdentityRepository
+
+	// Mailer, kullanıcılara e-posta gönderir. PublicURL, e-postalardaki bağlantıların
+	// kök adresidir; TTL alanları bağlantıların geçerlilik süreleridir.
+	Mailer                     mail.Mailer
+	PublicURL                  string
+	PasswordResetTTL           time.Duration
+	EmailVerificationTTL       time.Duration
+	VerificationResendInterval time.Duration
+	EmailChangeTTL             time.Duration
+	// SuggestTimeout, otomatik tamamlama ve "bunu mu demek istediniz" sorgularının süre sınırıdır;
+	// sıfırsa yalnızca isteğin kendi süre sınırı geçerlidir.
+	SuggestTimeout time.Duration
+	// PasswordPolicy, kayıt, şifre sıfırlama ve şifre değiştirmede yeni şifrelere uygulanır.
+	PasswordPolicy auth.PasswordPolicy
+	// UnverifiedRestrictions, e-posta adresini doğrulamamış hesaplara kapatılan işlemlerdir (auth.Restrict*).
+	UnverifiedRestrictions []string
+
+	// Permissions, kullanıcıların rollerini ve izinlerini önbellekli olarak çözümler.
+	Permissions *rbac.Resolver
+
+	// Sessions, kullanıcıların açık session'larının dizinidir.
+	Sessions *sessions.Index
+
+	// Lockout, başarısız giriş denemelerini sayar ve kaba kuvvet saldırılarını kilitler.
+	Lockout *lockout.Guard
+
+	// PoolStats, veritabanı bağlantı havuzunun istatistiklerini döndürür.
+	// Veritabanı olmadan çalışırken nil bırakılabilir.
+	PoolStats func() database.PoolStats
+}
+
+// Handler, tüm HTTP handler'larını Deps üzerinden çalışan metotlar olarak toplar.
+type Handler struct {
+	Deps
+}
+
+// New, verilen bağımlılıklarla bir Handler oluşturur.
+func New(deps Deps) *Handler {
+	return &Handler{Deps: deps}
+}
+
+// AuthConfig, kimlik doğrulama middleware'larının ihtiyaç duyduğu bağımlılıkları döndürür.
+func (h *Handler) AuthConfig() middleware.AuthConfig {
+	return middleware.AuthConfig{Store: h.Store, Tokens: h.Tokens, APIKeys: h.APIKeys, TwoFactor: h.TwoFactor, Sessions: h.Sessions, Permissions: h.Permissions}
+}
+
+// RequireVerified, action doğrulanmamış hesaplara kapatılmışsa e-posta doğrulamasını
+// zorunlu kılan middleware'ı döndürür; kapatılmamışsa istek olduğu gibi geçer.
+func (h *Handler) RequireVerified(action string) fiber.Handler {
+	if !slices.Contains(h.UnverifiedRestrictions, action) {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+	return middleware.VerifiedRequired(h.Users)
+}
@@ -0,0 +1,294 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/handlers"
+	"spoti/i18n"
+	"spoti/kv"
+	"spoti/lockout"
+	"spoti/mail"
+	"spoti/middleware"
+	"spoti/models"
+	"spoti/rbac"
+	"spoti/repository/memory"
+	"spoti/sessions"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/google/uuid"
+)
+
+// testPassword, testlerde oluşturulan kullanıcıların şifre politikasına uyan şifresidir.
+const testPassword = "Sifre-1234"
+
+var registerGob sync.Once
+
+// testEnv, bellek içi repository'lerle kurulmuş bir Handler ve onu çalıştıran uygulamadır.
+// Testler yalnızca ihtiyaç duydukları rotaları app'e ekler.
+type testEnv struct {
+	t      *testing.T
+	mem    *memory.Store
+	h      *handlers.Handler
+	app    *fiber.App
+	mailer *captureMailer
+}
+
+// captureMailer, gönderilen e-postaları saklar.
+type captureMailer struct {
+	mu   sync.Mutex
+	msgs []mail.Message
+}
+
+func (m *captureMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.msgs = append(m.msgs, msg)
+	return nil
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	// Session'lar değerleri gob ile saklar.
+	registerGob.Do(func() {
+		gob.Register(uuid.UUID{})
+		gob.Register(time.Time{})
+	})
+
+	mem := memory.NewStore()
+	shared := kv.NewMemory()
+	store := session.New(session.Config{Storage: shared})
+	mailer := &captureMailer{}
+	h := handlers.New(handlers.Deps{
+		Users:         mem.Users(),
+		Songs:         mem.Songs(),
+		Artists:       mem.Artists(),
+		Albums:        mem.Albums(),
+		Playlists:     mem.Playlists(),
+		Coupons:       mem.Coupons(),
+		Roles:         mem.Roles(),
+		Store:         store,
+		KV:            shared,
+		Tokens:        auth.NewTokenManager(strings.Repeat("k", 32), time.Minute, time.Hour),
+		RefreshTokens: mem.RefreshTokens(),
+		APIKeys:       mem.APIKeys(),
+		UserTokens:    mem.UserTokens(),
+		TwoFactor:     mem.TwoFactor(),
+		Identities:    mem.Identities(),
+
+		Mailer:               mailer,
+		PublicURL:            "http://spoti.test",
+		PasswordResetTTL:     time.Hour,
+		EmailVerificationTTL: time.Hour,
+		EmailChangeTTL:       time.Hour,
+		PasswordPolicy:       auth.PasswordPolicy{MinLength: 8},
+
+		Permissions: rbac.New(mem.Roles(), time.Minute),
+		Sessions:    sessions.New(shared, store.Expiration),
+		Lockout: lockout.New(shared, lockout.Policy{
+			FreeAttempts:  100,
+			MaxAttempts:   5,
+			IPMaxAttempts: 100,
+			Window:        time.Minute,
+			LockDuration:  time.Minute,
+		}),
+	})
+
+	app := fiber.New(fiber.Config{ErrorHandler: apierr.Handler})
+	app.Use(i18n.Middleware(i18n.EN))
+	app.Use(middleware.RequestContext(context.Background(), 5*time.Second))
+	return &testEnv{t: t, mem: mem, h: h, app: app, mailer: mailer}
+}
+
+// response, test isteğinin sonucudur.
+type response struct {
+	status int
+	body   []byte
+	cookie string
+	header http.Header
+}
+
+// do, isteği uygulamaya gönderir. body boş değilse JSON olarak kodlanır; cookie boş değilse
+// isteğe eklenir.
+func (e *testEnv) do(method, path string, body any, cookie string) response {
+	e.t.Helper()
+	req := e.request(method, path, body)
+	if cookie != "" {
+		req.Header.Set("Cookie", cookie)
+	}
+	return e.send(req, cookie)
+}
+
+// doBearer, isteği Authorization: Bearer başlığındaki erişim token'ı veya API anahtarıyla gönderir.
+func (e *testEnv) doBearer(method, path string, body any, token string) response {
+	e.t.Helper()
+	req := e.request(method, path, body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return e.send(req, "")
+}
+
+func (e *testEnv) request(method, path string, body any) *http.Request {
+	e.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			e.t.Fatal(err)
+		}
+		reader = strings.NewReader(string(data))
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func (e *testEnv) send(req *http.Request, cookie string) response {
+	e.t.Helper()
+	resp, err := e.app.Test(req, -1)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	r := response{status: resp.StatusCode, body: data, cookie: cookie, header: resp.Header}
+	for _, sc := range resp.Header.Values("Set-Cookie") {
+		r.cookie = strings.Split(sc, ";")[0]
+	}
+	return r
+}
+
+// mailToken, path bağlantısı içeren en son e-postadaki token'ı döndürür.
+func (e *testEnv) mailToken(path string) string {
+	e.t.Helper()
+	_, token := e.mailLink(path)
+	return token
+}
+
+// mailLink, path bağlantısı içeren en son e-postanın alıcısını ve bağlantıdaki token'ı döndürür.
+func (e *testEnv) mailLink(path string) (to, token string) {
+	e.t.Helper()
+	e.mailer.mu.Lock()
+	defer e.mailer.mu.Unlock()
+	for i := len(e.mailer.msgs) - 1; i >= 0; i-- {
+		msg := e.mailer.msgs[i]
+		_, rest, ok := strings.Cut(msg.Body, path+"?token=")
+		if !ok {
+			continue
+		}
+		token, err := url.QueryUnescape(strings.Fields(rest)[0])
+		if err != nil {
+			e.t.Fatal(err)
+		}
+		return msg.To, token
+	}
+	e.t.Fatalf("%s bağlantısı içeren bir e-posta gönderilmedi", path)
+	return "", ""
+}
+
+// sent, gönderilen e-posta sayısını döndürür.
+func (e *testEnv) sent() int {
+	e.mailer.mu.Lock()
+	defer e.mailer.mu.Unlock()
+	return len(e.mailer.msgs)
+}
+
+// decode, yanıt gövdesini v'ye çözer.
+func (r response) decode(t *testing.T, v any) {
+	t.Helper()
+	if err := json.Unmarshal(r.body, v); err != nil {
+		t.Fatalf("yanıt çözülemedi (%s): %v", r.body, err)
+	}
+}
+
+// errorCode, hata yanıtının kodunu döndürür; hata yoksa boş döner.
+func (r response) errorCode(t *testing.T) string {
+	t.Helper()
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	r.decode(t, &body)
+	return body.Error.Code
+}
+
+// expect, yanıtın durum kodunu ve varsa hata kodunu kontrol eder.
+func (r response) expect(t *testing.T, status int, code apierr.Code) {
+	t.Helper()
+	if r.status != status {
+		t.Fatalf("durum kodu = %d, beklenen %d (%s)", r.status, status, r.body)
+	}
+	if code != "" {
+		if got := r.errorCode(t); got != string(code) {
+			t.Fatalf("hata kodu = %q, beklenen %q", got, code)
+		}
+	}
+}
+
+// createUser, verilen rolde, e-postası doğrulanmış ve testPassword şifreli bir kullanıcı oluşturur.
+func (e *testEnv) createUser(username, role string) *models.User {
+	e.t.Helper()
+	hash, err := auth.HashPassword(testPassword)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	now := time.Now()
+	user := &models.User{
+		Username:        username,
+		Email:           username + "@spoti.test",
+		Password:        hash,
+		EmailVerifiedAt: &now,
+	}
+	if err := e.mem.Users().Create(context.Background(), user, role); err != nil {
+		e.t.Fatal(err)
+	}
+	return user
+}
+
+// createUnverifiedUser, e-posta adresini henüz doğrulamamış, 'user' rolünde bir kullanıcı oluşturur.
+func (e *testEnv) createUnverifiedUser(username string) *models.User {
+	e.t.Helper()
+	hash, err := auth.HashPassword(testPassword)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	user := &models.User{Username: username, Email: username + "@spoti.test", Password: hash}
+	if err := e.mem.Users().Create(context.Background(), user, auth.RoleUser); err != nil {
+		e.t.Fatal(err)
+	}
+	return user
+}
+
+// createSong, bellek içi depoya bir şarkı ekler.
+func (e *testEnv) createSong(title, artist string) *models.Song {
+	e.t.Helper()
+	song := &models.Song{Title: title, Artist: artist, Duration: 180}
+	if err := e.mem.Songs().Create(context.Background(), song); err != nil {
+		e.t.Fatal(err)
+	}
+	return song
+}
+
+// as, isteği kimlik doğrulaması yapılmış gibi userID adına çalıştıran bir middleware döndürür.
+// Kimlik doğrulama middleware'larının kendisi bu testlerin konusu olmadığında kullanılır.
+func as(userID uuid.UUID) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("userID", userID)
+		return c.Next()
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net"
+	"time"
+
+	"spoti/apierr"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/lockout"
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ListLockouts, süresi dolmamış tüm hesap ve IP kilitlerini listeler.
+func (h *Handler) ListLockouts(c *fiber.Ctx) error {
+	list, err := h.Lockout.Active(time.Now())
+	if err != nil {
+		log.Println("Giriş kilitlerini listeleme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "lockout.list_failed")
+	}
+	if list == nil {
+		list = []lockout.Lockout{}
+	}
+	return c.JSON(list)
+}
+
+// GetUserLockout, kullanıcının e-posta adresine ait başarısız giriş sayacını döndürür.
+func (h *Handler) GetUserLockout(c *fiber.Ctx) error {
+	user, err := h.lockoutUser(c)
+	if err != nil {
+		return err
+	}
+	return h.lockoutStatus(c, lockout.KindAccount, user.Email)
+}
+
+// ClearUserLockout, kullanıcının başarısız giriş sayacını ve kilidini kaldırır. Çağırandan
+// yetkili kullanıcıların kilidi kaldırılamaz (bkz. checkTargetRole).
+func (h *Handler) ClearUserLockout(c *fiber.Ctx) error {
+	user, err := h.lockoutUser(c)
+	if err != nil {
+		return err
+	}
+	if err := h.checkTargetRole(c, user.ID, "user.not_found"); err != nil {
+		return err
+	}
+	return h.clearLockout(c, lockout.KindAccount, user.Email)
+}
+
+// GetIPLockout, IP adresinin başarısız giriş sayacını döndürür.
+func (h *Handler) GetIPLockout(c *fiber.Ctx) error {
+	ip := net.ParseIP(c.Params("ip"))
+	if ip == nil {
+		return apierr.New(apierr.CodeBadRequest, "lockout.invalid_ip")
+	}
+	return h.lockoutStatus(c, lockout.KindIP, ip.String())
+}
+
+// ClearIPLockout, IP adresinin başarısız giriş sayacını ve kilidini kaldırır.
+func (h *Handler) ClearIPLockout(c *fiber.Ctx) error {
+	ip := net.ParseIP(c.Params("ip"))
+	if ip == nil {
+		return apierr.New(apierr.CodeBadRequest, "lockout.invalid_ip")
+	}
+	return h.clearLockout(c, lockout.KindIP, ip.String())
+}
+
+// lockoutUser, rota parametresindeki kullanıcıyı getirir.
+func (h *Handler) lockoutUser(c *fiber.Ctx) (*models.User, error) {
+	userID, err := uuid.Parse(c.Params("userID"))
+	if err != nil {
+		return nil, apierr.New(apierr.CodeInvalidID, "user.invalid_id")
+	}
+	user, err := h.Users.GetByID(c.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, apierr.New(apierr.CodeUserNotFound, "user.not_found")
+		}
+		log.Println("Kullanıcı ID ile sorgu hatası:", err)
+		return nil, apierr.New(apierr.CodeInternal, "user.fetch_failed")
+	}
+	return user, nil
+}
+
+func (h *Handler) lockoutStatus(c *fiber.Ctx, kind, subject string) error {
+	state, err := h.Lockout.Status(kind, subject)
+	if err != nil {
+		log.Println("Giriş denemesi sayacı okuma hatası:", err)
+		return apierr.New(apierr.CodeInternal, "lockout.fetch_failed")
+	}
+	return c.JSON(dto.NewLockoutStatus(kind, lockout.NormalizeSubject(kind, subject), state, time.Now()))
+}
+
+func (h *Handler) clearLockout(c *fiber.Ctx, kind, subject string) error {
+	if err := h.Lockout.Clear(kind, subject); err != nil {
+		log.Println("Giriş kilidi kaldırma hatası:", err)
+		return apierr.New(apierr.CodeInternal, "lockout.clear_failed")
+	}
+	log.Printf("Giriş kilidi admin tarafından kaldırıldı (%s %s).\n", kind, lockout.NormalizeSubject(kind, subject))
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "lockout.cleared")})
+}
+
+// loginFailed, başarısız denemeyi sayar. Deneme bir kilidi başlattıysa kilit hatası,
+// değilse genel geçersiz kimlik bilgisi hatası döner.
+func (h *Handler) loginFailed(c *fiber.Ctx, email string) error {
+	block, err := h.Lockout.Fail(email, c.IP(), time.Now())
+	if err != nil {
+		log.Println("Giriş denemesi sayacı güncelleme hatası:", err)
+	}
+	if block != nil {
+		return loginBlocked(block)
+	}
+	return apierr.New(apierr.CodeInvalidCredentials, "auth.invalid_credentials")
+}
+
+// secondFactorFailed, hatalı iki adımlı doğrulama kodunu giriş denemeleriyle aynı sayaçlara
+// yazar; böylece şifreyi bilen biri kodu sınırsızca tahmin edemez.
+func (h *Handler) secondFactorFailed(c *fiber.Ctx, email string) error {
+	block, err := h.Lockout.Fail(email, c.IP(), time.Now())
+	if err != nil {
+		log.Println("Giriş denemesi sayacı güncelleme hatası:", err)
+	}
+	if block != nil {
+		return loginBlocked(block)
+	}
+	return apierr.New(apierr.CodeTwoFactorInvalid, "two_factor.code_invalid")
+}
+
+// loginSucceeded, giriş tüm adımlarıyla tamamlandığında hesabın sayacını sıfırlar.
+func (h *Handler) loginSucceeded(email string) {
+	if err := h.Lockout.Succeed(email); err != nil {
+		log.Println("Giriş denemesi sayacı sıfırlama hatası:", err)
+	}
+}
+
+// loginBlocked, engellenen giriş denemesinin hatasını kilidin kalkacağı anla birlikte üretir.
+func loginBlocked(block *lockout.Block) error {
+	switch {
+	case block.Locked && block.Kind == lockout.KindIP:
+		return apierr.New(apierr.CodeAccountLocked, "auth.ip_locked").WithUnlockAt(block.Until)
+	case block.Locked:
+		return apierr.New(apierr.CodeAccountLocked, "auth.account_locked").WithUnlockAt(block.Until)
+	default:
+		return apierr.New(apierr.CodeLoginThrottled, "auth.login_throttled").WithUnlockAt(block.Until)
+	}
+}
@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/models"
+	"spoti/oidc"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// oidcStateTTL, kullanıcının sağlayıcıda girişi tamamlaması için tanınan süredir.
+const oidcStateTTL = 10 * time.Minute
+
+// oidcStateCookie, state değerini tarayıcıya bağlayan çerezin adıdır. Callback'e gelen state
+// bu çerezdekiyle eşleşmezse istek başka bir tarayıcıda başlatılmış sayılır.
+const oidcStateCookie = "oidc_state"
+
+// oidcUsernameAttempts, yeni hesap için kullanıcı adı çakıştığında denenen rastgele ek sayısıdır.
+const oidcUsernameAttempts = 3
+
+// oidcLoginState, giriş başlatılırken saklanan ve callback'te tüketilen bilgilerdir.
+type oidcLoginState struct {
+	Verifier string `json:"verifier"`
+	Nonce    string `json:"nonce"`
+	Tokens   bool   `json:"tokens"`
+}
+
+// oidcStateKey, giriş durumunun storage anahtarıdır; state'in kendisi değil hash'i kullanılır.
+func oidcStateKey(state string) string {
+	return "oidc_state:" + auth.HashToken(state)
+}
+
+// OIDCLogin, kullanıcıyı PKCE ile yapılandırılmış sağlayıcının giriş sayfasına yönlendirir.
+// tokens=true verilirse giriş session yerine bearer token çiftiyle tamamlanır.
+func (h *Handler) OIDCLogin(c *fiber.Ctx) error {
+	if h.OIDC == nil {
+		return apierr.New(apierr.CodeNotFound, "oidc.disabled")
+	}
+
+	var values [3]string
+	for i := range values {
+		v, err := oidc.NewVerifier()
+		if err != nil {
+			log.Println("OIDC state üretme hatası:", err)
+			return apierr.New(apierr.CodeInternal, "oidc.login_failed")
+		}
+		values[i] = v
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	raw, err := json.Marshal(oidcLoginState{Verifier: verifier, Nonce: nonce, Tokens: c.QueryBool("tokens")})
+	if err != nil {
+		log.Println("OIDC state kodlama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "oidc.login_failed")
+	}
+	if err := h.Store.Storage.Set(oidcStateKey(state), raw, oidcStateTTL); err != nil {
+		log.Println("OIDC state kaydetme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "oidc.login_failed")
+	}
+
+	authURL, err := h.OIDC.AuthURL(c.UserContext(), state, nonce, verifier)
+	if err != nil {
+		log.Println("OIDC yetkilendirme adresi oluşturma hatası:", err)
+		return apierr.New(apierr.CodeOIDCProviderError, "oidc.provider_error")
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/api/auth/oidc",
+		MaxAge:   int(oidcStateTTL.Seconds()),
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		// Sağlayıcıdan dönüş site dışı bir yönlendirme olduğundan Strict çerezi göndermez.
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// OIDCCallback, sağlayıcının geri yönlendirmesini karşılar. Yetkilendirme kodu token'a
+// çevrilir ve sağlayıcı hesabı bir kullanıcıya bağlanarak giriş yapılır:
+//
+//   - Hesap daha önce bağlanmışsa bağlı kullanıcıyla giriş yapılır.
+//   - Aynı e-postaya sahip bir kullanıcı varsa hesap ona bağlanır. Bunun için e-postanın hem
+//     sağlayıcıda hem de burada doğrulanmış olması gerekir; aksi hâlde adresi sahiplenmemiş
+//     biri başkasının hesabına girebilirdi.
+//   - Kullanıcı yoksa 'user' rolüyle, şifresiz ve e-postası doğrulanmış yeni bir hesap açılır.
+func (h *Handler) OIDCCallback(c *fiber.Ctx) error {
+	if h.OIDC == nil {
+		return apierr.New(apierr.CodeNotFound, "oidc.disabled")
+	}
+
+	state := c.Query("state")
+	cookie := c.Cookies(oidcStateCookie)
+	// Çerez başarılı ya da başarısız her dönüşte silinir; state yalnızca bir kez kullanılabilir.
+	c.ClearCookie(oidcStateCookie)
+	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(cookie)) != 1 {
+		return apierr.New(apierr.CodeOIDCStateInvalid, "oidc.state_invalid")
+	}
+	loginState, err := h.consumeOIDCState(state)
+	if err != nil {
+		return err
+	}
+
+	if reason := c.Query("error"); reason != "" {
+		return apierr.New(apierr.CodeOIDCDenied, "oidc.denied", reason)
+	}
+	code := c.Query("code")
+	if code == "" {
+		return apierr.New(apierr.CodeOIDCDenied, "oidc.denied", "missing_code")
+	}
+
+	claims, err := h.OIDC.Exchange(c.UserContext(), code, loginState.Verifier, loginState.Nonce)
+	if err != nil {
+		log.Println("OIDC kod değişimi hatası:", err)
+		return apierr.New(apierr.CodeOIDCProviderError, "oidc.provider_error")
+	}
+
+	user, err := h.oidcUser(c, claims)
+	if err != nil {
+		return err
+	}
+
+	// Harici sağlayıcıyla giriş iki adımlı doğrulamayı atlatmaz.
+	twoFactor, err := h.TwoFactor.Get(c.UserContext(), user.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Println("İki adımlı doğrulama sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "oidc.login_failed")
+	}
+	if err == nil && twoFactor.Enabled() {
+		return h.startTwoFactorChallenge(c, user.ID, loginState.Tokens)
+	}
+
+	return h.completeLogin(c, user, loginState.Tokens)
+}
+
+// consumeOIDCState, giriş durumunu okur ve tekrar kullanılamaması için siler.
+func (h *Handler) consumeOIDCState(state string) (*oidcLoginState, error) {
+	key := oidcStateKey(state)
+	raw, err := h.Store.Storage.Get(key)
+	if err != nil {
+		log.Println("OIDC state okuma hatası:", err)
+		return nil, apierr.New(apierr.CodeInternal, "oidc.login_failed")
+	}
+	if raw == nil {
+		return nil, apierr.New(apierr.CodeOIDCStateInvalid, "oidc.state_invalid")
+	}
+	if err := h.Store.Storage.Delete(key); err != nil {
+		log.Println("OIDC state silme hatası:", err)
+		return nil, apierr.New(apierr.CodeInternal, "oidc.login_failed")
+	}
+
+	var loginState oidcLoginState
+	if err := json.Unmarshal(raw, &loginState); err != nil {
+		log.Println("OIDC state çözme hatası:", err)
+		return nil, apierr.New(apierr.CodeOIDCStateInvalid, "oidc.state_invalid")
+	}
+	return &loginState, nil
+}
+
+// oidcUser, sağlayıcı hesabına bağlı kullanıcıyı döndürür; gerekirse hesabı mevcut bir
+// kullanıcıya bağlar veya yeni kullanıcı oluşturur.
+func (h *Handler) oidcUser(c *fiber.Ctx, claims *oidc.Claims) (*models.User, error) {
+	ctx := c.UserContext()
+	provider := h.OIDC.Name()
+
+	identity, err := h.Identities.Get(ctx, provider, claims.Subject)
+	if err == nil {
+		user, err := h.Users.GetByID(ctx, identity.UserID)
+		if err != nil {
+			log.Println("OIDC bağlı kullanıcı sorgu hatası:", err)
+			return nil, apierr.New(apierr.CodeInternal, "oidc.login_failed")
+		}
+		return user, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		log.Println("OIDC bağlantı sorgu hatası:", err)
+		return nil, apierr.New(apierr.CodeInternal, "oidc.login_failed")
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, apierr.New(apierr.CodeOIDCEmailUnverified, "oidc.email_unverified")
+	}
+	identity = &models.Identity{Provider: provider, Subject: claims.Subject, Email: claims.Email}
+
+	user, err := h.Users.GetByEmail(ctx, claims.Email)
+	switch {
+	case err == nil:
+		if !user.EmailVerified() {
+			return nil, apierr.New(apierr.CodeOIDCLinkConflict, "oidc.link_conflict")
+		}
+		identity.UserID = user.ID
+		if err := h.Identities.Create(ctx, identity); err != nil {
+			if errors.Is(err, repository.ErrConflict) {
+				// Aynı hesap eşzamanlı bir girişte bağlanmış olabilir.
+				return nil, apierr.New(apierr.CodeOIDCLinkConflict, "oidc.link_conflict")
+			}
+			log.Println("OIDC hesap bağlama hatası:", err)
+			return nil, apierr.New(apierr.CodeInternal, "oidc.login_failed")
+		}
+		log.Printf("OIDC hesabı mevcut kullanıcıya bağlandı: %s (%s)\n", user.ID, provider)
+		return user, nil
+	case errors.Is(err, repository.ErrNotFound):
+		return h.createOIDCUser(c, claims, identity)
+	default:
+		log.Println("OIDC kullanıcı sorgu hatası:", err)
+		return nil, apierr.New(apierr.CodeInternal, "oidc.login_failed")
+	}
+}
+
+// createOIDCUser, sağlayıcı hesabı için yeni bir kullanıcı oluşturur. Kullanıcının şifresi
+// olmadığından şifreyle giriş yapamaz; isterse şifre sıfırlama ile bir şifre belirleyebilir.
+func (h *Handler) createOIDCUser(c *fiber.Ctx, claims *oidc.Claims, identity *models.Identity) (*models.User, error) {
+	now := time.Now()
+	base := oidcUsername(claims)
+
+	for attempt := 0; ; attempt++ {
+		username := base
+		if attempt > 0 {
+			// Ek için yer açılır; kullanıcı adı 50 karakteri geçemez.
+			runes := []rune(base)
+			username = string(runes[:min(len(runes), 45)]) + "_" + randomSuffix()
+		}
+		user := models.User{
+			ID:              uuid.New(),
+			Username:        username,
+			Email:           claims.Email,
+			HesapTuru:       "Free",
+			Cash:            100.00,
+			EmailVerifiedAt: &now,
+		}
+
+		err := h.Identities.CreateUser(c.UserContext(), &user, "user", identity)
+		if err == nil {
+			log.Printf("OIDC ile yeni kullanıcı oluşturuldu: %s (%s)\n", user.ID, identity.Provider)
+			return &user, nil
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Println("Veritabanında 'user' rolü bulunamadı.")
+			return nil, apierr.New(apierr.CodeInternal, "auth.user_role_missing")
+		}
+		if !errors.Is(err, repository.ErrConflict) {
+			log.Println("OIDC kullanıcı oluşturma hatası:", err)
+			return nil, apierr.New(apierr.CodeInternal, "oidc.login_failed")
+		}
+		// E-posta önceden kontrol edildiğinden çakışma büyük olasılıkla kullanıcı adındadır.
+		if attempt == oidcUsernameAttempts {
+			return nil, apierr.New(apierr.CodeOIDCLinkConflict, "oidc.link_conflict")
+		}
+	}
+}
+
+// oidcUsername, sağlayıcının bildirdiği adlardan geçerli bir kullanıcı adı türetir.
+func oidcUsername(claims *oidc.Claims) string {
+	local, _, _ := strings.Cut(claims.Email, "@")
+	for _, candidate := range []string{claims.PreferredUsername, claims.Name, local} {
+		var b strings.Builder
+		for _, r := range candidate {
+			switch {
+			case unicode.IsLetter(r), unicode.IsDigit(r), r == '.', r == '-', r == '_':
+				b.WriteRune(r)
+			case unicode.IsSpace(r):
+				b.WriteRune('_')
+			}
+		}
+		name := []rune(b.String())
+		if len(name) > 50 {
+			name = name[:50]
+		}
+		if len(name) >= 3 {
+			return string(name)
+		}
+	}
+	return "user_" + randomSuffix()
+}
+
+// randomSuffix, kullanıcı adlarına eklenen 4 karakterlik rastgele bir ektir.
+func randomSuffix() string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	buf := make([]byte, 4)
+	rand.Read(buf)
+	for i, b := range buf {
+		buf[i] = alphabet[int(b)%len(alphabet)]
+	}
+	return string(buf)
+}
@@ -0,0 +1,344 @@
+package handlers_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/config"
+	"spoti/dto"
+	"spoti/middleware"
+	"spoti/oidc"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// oidcClientID, testlerde sağlayıcıya kayıtlı istemcinin kimliğidir.
+const oidcClientID = "spoti"
+
+var (
+	issuerKeyOnce sync.Once
+	issuerKey     *rsa.PrivateKey
+)
+
+// signingKey, sahte sağlayıcının ID token'ları imzaladığı anahtardır; her testte yeniden
+// üretmemek için bir kez oluşturulur.
+func signingKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	issuerKeyOnce.Do(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		issuerKey = key
+	})
+	return issuerKey
+}
+
+// fakeIssuer, discovery belgesi, token uç noktası ve JWKS sunan sahte bir OIDC sağlayıcısıdır.
+// Yetkilendirme adımı tarayıcı olmadan authorize ile yapılır.
+type fakeIssuer struct {
+	t   *testing.T
+	srv *httptest.Server
+	key *rsa.PrivateKey
+
+	mu sync.Mutex
+	// claims, verilecek ID token'ın kullanıcı alanlarıdır (sub, email, email_verified...).
+	claims map[string]any
+	// audience ve nonce boş değilse ID token'a doğru değerlerin yerine yazılır.
+	audience string
+	nonce    string
+	grants   map[string]oidcGrant
+}
+
+// oidcGrant, verilmiş bir yetkilendirme kodunun PKCE challenge'ı ve nonce değeridir.
+type oidcGrant struct {
+	challenge string
+	nonce     string
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	f := &fakeIssuer{t: t, key: signingKey(t), grants: make(map[string]oidcGrant)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 f.srv.URL,
+			"authorization_endpoint": f.srv.URL + "/authorize",
+			"token_endpoint":         f.srv.URL + "/token",
+			"jwks_uri":               f.srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		pub := f.key.PublicKey
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kid": "test",
+			"kty": "RSA",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("POST /token", f.token)
+	f.srv = httptest.NewServer(mux)
+	t.Cleanup(f.srv.Close)
+	return f
+}
+
+// provider, sahte sağlayıcıya bağlanan bir oidc.Provider döndürür.
+func (f *fakeIssuer) provider() *oidc.Provider {
+	return oidc.New(config.OIDCConfig{
+		Provider: "test",
+		Issuer:   f.srv.URL,
+		ClientID: oidcClientID,
+		Scopes:   []string{"openid", "email", "profile"},
+	}, "http://spoti.test/callback")
+}
+
+// authorize, kullanıcının sağlayıcıda girişi onayladığını varsayar: yönlendirme adresindeki
+// PKCE challenge'ı ve nonce için bir kod üretir ve kodla state'i döndürür.
+func (f *fakeIssuer) authorize(location string) (code, state string) {
+	f.t.Helper()
+	u, err := url.Parse(location)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("client_id") != oidcClientID {
+		f.t.Fatalf("beklenmeyen yetkilendirme isteği: %s", location)
+	}
+	code = rand.Text()
+	f.mu.Lock()
+	f.grants[code] = oidcGrant{challenge: q.Get("code_challenge"), nonce: q.Get("nonce")}
+	f.mu.Unlock()
+	return code, q.Get("state")
+}
+
+// token, kodu PKCE doğrulamasından sonra imzalı bir ID token'a çevirir. Kodlar tek kullanımlıktır.
+func (f *fakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	grant, ok := f.grants[r.PostFormValue("code")]
+	delete(f.grants, r.PostFormValue("code"))
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   f.srv.URL,
+		"aud":   oidcClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": grant.nonce,
+	}
+	for k, v := range f.claims {
+		claims[k] = v
+	}
+	if f.audience != "" {
+		claims["aud"] = f.audience
+	}
+	if f.nonce != "" {
+		claims["nonce"] = f.nonce
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test"
+	signed, err := token.SignedString(f.key)
+	if err != nil {
+		f.t.Error(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+// mountOIDC, sahte sağlayıcıyı Handler'a bağlar ve OIDC rotalarını ekler.
+func mountOIDC(env *testEnv, f *fakeIssuer) {
+	env.h.OIDC = f.provider()
+	env.app.Get("/oidc/login", env.h.OIDCLogin)
+	env.app.Get("/oidc/callback", env.h.OIDCCallback)
+	env.app.Get("/me", middleware.AuthRequired(env.h.AuthConfig()), env.h.GetUser)
+	env.app.Post("/2fa", middleware.ValidateBody[dto.TwoFactorLoginRequest](), env.h.VerifyTwoFactorLogin)
+}
+
+// oidcLogin, girişi başlatır ve sağlayıcının ürettiği kodu, state'i ve state çerezini döndürür.
+func oidcLogin(t *testing.T, env *testEnv, f *fakeIssuer) (code, state, cookie string) {
+	t.Helper()
+	resp := env.do(http.MethodGet, "/oidc/login", nil, "")
+	resp.expect(t, http.StatusFound, "")
+	if !strings.HasPrefix(resp.cookie, "oidc_state=") {
+		t.Fatalf("state çerezi verilmedi: %q", resp.cookie)
+	}
+	code, state = f.authorize(resp.header.Get("Location"))
+	return code, state, resp.cookie
+}
+
+func oidcCallback(env *testEnv, code, state, cookie string) response {
+	q := url.Values{"code": {code}, "state": {state}}
+	return env.do(http.MethodGet, "/oidc/callback?"+q.Encode(), nil, cookie)
+}
+
+func TestOIDCLoginLinksVerifiedAccount(t *testing.T) {
+	env := newTestEnv(t)
+	f := newFakeIssuer(t)
+	mountOIDC(env, f)
+	user := env.createUser("alice", auth.RoleUser)
+	f.claims = map[string]any{"sub": "alice-sub", "email": user.Email, "email_verified": true}
+
+	code, state, cookie := oidcLogin(t, env, f)
+	resp := oidcCallback(env, code, state, cookie)
+	resp.expect(t, http.StatusOK, "")
+	env.do(http.MethodGet, "/me", nil, resp.cookie).expect(t, http.StatusOK, "")
+
+	identity, err := env.mem.Identities().Get(context.Background(), "test", "alice-sub")
+	if err != nil || identity.UserID != user.ID {
+		t.Fatalf("sağlayıcı hesabı kullanıcıya bağlanmadı: %+v, %v", identity, err)
+	}
+}
+
+func TestOIDCCallbackRejectsStateMismatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie func(cookie string) string
+	}{
+		{"başka tarayıcının çerezi", func(string) string { return "oidc_state=baska" }},
+		{"çerez yok", func(string) string { return "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			f := newFakeIssuer(t)
+			mountOIDC(env, f)
+			f.claims = map[string]any{"sub": "alice-sub", "email": "alice@spoti.test", "email_verified": true}
+
+			code, state, cookie := oidcLogin(t, env, f)
+			oidcCallback(env, code, state, tt.cookie(cookie)).expect(t, http.StatusBadRequest, apierr.CodeOIDCStateInvalid)
+		})
+	}
+}
+
+func TestOIDCCallbackRejectsReplayedState(t *testing.T) {
+	env := newTestEnv(t)
+	f := newFakeIssuer(t)
+	mountOIDC(env, f)
+	f.claims = map[string]any{"sub": "alice-sub", "email": "alice@spoti.test", "email_verified": true}
+
+	code, state, cookie := oidcLogin(t, env, f)
+	oidcCallback(env, code, state, cookie).expect(t, http.StatusOK, "")
+
+	// Aynı dönüş adresi (ör. tarayıcı geçmişinden) ikinci kez açılamaz.
+	oidcCallback(env, code, state, cookie).expect(t, http.StatusBadRequest, apierr.CodeOIDCStateInvalid)
+}
+
+func TestOIDCCallbackRejectsPKCEMismatch(t *testing.T) {
+	env := newTestEnv(t)
+	f := newFakeIssuer(t)
+	mountOIDC(env, f)
+	f.claims = map[string]any{"sub": "alice-sub", "email": "alice@spoti.test", "email_verified": true}
+
+	// Saldırganın kendi girişinden aldığı kod kurbanın state'iyle gönderilirse code_verifier
+	// eşleşmez ve sağlayıcı kodu reddeder.
+	stolen, _, _ := oidcLogin(t, env, f)
+	_, state, cookie := oidcLogin(t, env, f)
+	oidcCallback(env, stolen, state, cookie).expect(t, http.StatusBadGateway, apierr.CodeOIDCProviderError)
+
+	if _, err := env.mem.Users().GetByEmail(context.Background(), "alice@spoti.test"); err == nil {
+		t.Fatal("PKCE doğrulanmadan kullanıcı oluşturuldu")
+	}
+}
+
+func TestOIDCCallbackRejectsInvalidIDToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(f *fakeIssuer)
+	}{
+		{"farklı nonce", func(f *fakeIssuer) { f.nonce = "baska-nonce" }},
+		{"farklı hedef kitle", func(f *fakeIssuer) { f.audience = "baska-istemci" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			f := newFakeIssuer(t)
+			mountOIDC(env, f)
+			f.claims = map[string]any{"sub": "alice-sub", "email": "alice@spoti.test", "email_verified": true}
+			tt.tamper(f)
+
+			code, state, cookie := oidcLogin(t, env, f)
+			oidcCallback(env, code, state, cookie).expect(t, http.StatusBadGateway, apierr.CodeOIDCProviderError)
+		})
+	}
+}
+
+func TestOIDCLinkRequiresVerifiedEmails(t *testing.T) {
+	tests := []struct {
+		name string
+		// localVerified, aynı adrese sahip yerel hesabın adresini doğrulayıp doğrulamadığıdır.
+		localVerified    bool
+		providerVerified any
+		status           int
+		code             apierr.Code
+	}{
+		{"sağlayıcıda doğrulanmamış", true, false, http.StatusForbidden, apierr.CodeOIDCEmailUnverified},
+		{"sağlayıcıda doğrulanmamış (metin)", true, "false", http.StatusForbidden, apierr.CodeOIDCEmailUnverified},
+		{"yerelde doğrulanmamış", false, true, http.StatusConflict, apierr.CodeOIDCLinkConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			f := newFakeIssuer(t)
+			mountOIDC(env, f)
+			user := env.createUnverifiedUser("alice")
+			if tt.localVerified {
+				user = env.createUser("bob", auth.RoleUser)
+			}
+			f.claims = map[string]any{"sub": "sub-1", "email": user.Email, "email_verified": tt.providerVerified}
+
+			code, state, cookie := oidcLogin(t, env, f)
+			oidcCallback(env, code, state, cookie).expect(t, tt.status, tt.code)
+
+			if _, err := env.mem.Identities().Get(context.Background(), "test", "sub-1"); err == nil {
+				t.Fatal("doğrulanmamış adresle hesap bağlandı")
+			}
+		})
+	}
+}
+
+func TestOIDCLoginStillRequiresTwoFactor(t *testing.T) {
+	env := newTestEnv(t)
+	f := newFakeIssuer(t)
+	mountOIDC(env, f)
+	user := env.createUser("alice", auth.RoleUser)
+	_, codes := enableTwoFactor(t, env, user)
+	f.claims = map[string]any{"sub": "alice-sub", "email": user.Email, "email_verified": true}
+
+	code, state, cookie := oidcLogin(t, env, f)
+	resp := oidcCallback(env, code, state, cookie)
+	resp.expect(t, http.StatusOK, "")
+	var challenge dto.TwoFactorChallenge
+	resp.decode(t, &challenge)
+	if !challenge.TwoFactorRequired || challenge.ChallengeToken == "" {
+		t.Fatalf("OIDC girişi iki adımlı doğrulamayı atladı: %s", resp.body)
+	}
+	// İkinci adım tamamlanmadan oturum açılmaz.
+	env.do(http.MethodGet, "/me", nil, resp.cookie).expect(t, http.StatusUnauthorized, "")
+
+	done := env.do(http.MethodPost, "/2fa", dto.TwoFactorLoginRequest{ChallengeToken: challenge.ChallengeToken, Code: codes[0]}, "")
+	done.expect(t, http.StatusOK, "")
+	env.do(http.MethodGet, "/me", nil, done.cookie).expect(t, http.StatusOK, "")
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"spoti/apierr"
+	"spoti/cursor"
+	"spoti/dto"
+	"spoti/repository"
+)
+
+// pageRequest, liste sorgusundaki cursor belirtecini çözerek sayfalama isteğini hazırlar.
+// Belirteç boşsa ilk sayfa istenir; çözülemiyorsa CodeInvalidCursor döner.
+func pageRequest[K any](token string, size int, withTotal bool) (repository.Page[K], error) {
+	page := repository.Page[K]{Limit: size, WithTotal: withTotal}
+	if token == "" {
+		return page, nil
+	}
+	key, before, err := cursor.Decode[K](token)
+	if err != nil {
+		return page, apierr.New(apierr.CodeInvalidCursor, "request.invalid_cursor")
+	}
+	if before {
+		page.Before = &key
+	} else {
+		page.After = &key
+	}
+	return page, nil
+}
+
+// newPageInfo, dönen sayfanın ilk ve son satırının anahtarından komşu sayfaların
+// belirteçlerini üretir. Boş bir sayfanın komşusu gösterilmez.
+func newPageInfo[T, K any](items []T, info repository.PageInfo, size int, key func(*T) K) dto.PageInfo {
+	out := dto.PageInfo{PageSize: size, Total: info.Total}
+	if len(items) == 0 {
+		return out
+	}
+	if info.HasNext {
+		out.NextCursor = cursor.Encode(key(&items[len(items)-1]), false)
+	}
+	if info.HasPrev {
+		out.PrevCursor = cursor.Encode(key(&items[0]), true)
+	}
+	return out
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/mail"
+	"spoti/middleware"
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ForgotPassword, kayıtlı e-posta adresine tek kullanımlık bir şifre sıfırlama bağlantısı gönderir.
+// Yanıt, adresin kayıtlı olup olmadığını sızdırmamak için her durumda aynıdır.
+func (h *Handler) ForgotPassword(c *fiber.Ctx) error {
+	req := middleware.Body[dto.ForgotPasswordRequest](c)
+	ctx := c.UserContext()
+
+	user, err := h.Users.GetByEmail(ctx, req.Email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return h.resetRequested(c)
+	}
+	if err != nil {
+		log.Println("Şifre sıfırlama için kullanıcı sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "password.reset_request_failed")
+	}
+
+	plain, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		log.Println("Şifre sıfırlama token'ı üretme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "password.reset_request_failed")
+	}
+	token := models.UserToken{
+		UserID:    user.ID,
+		Purpose:   models.TokenPurposePasswordReset,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(h.PasswordResetTTL),
+	}
+	if err := h.UserTokens.Create(ctx, &token); err != nil {
+		log.Println("Şifre sıfırlama token'ı kaydetme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "password.reset_request_failed")
+	}
+
+	lang := mailLanguage(c, user)
+	link := h.link("/reset-password", plain)
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: i18n.Translate(lang, "mail.password_reset_subject"),
+		Body:    i18n.Translate(lang, "mail.password_reset_body", user.Username, link, int(h.PasswordResetTTL.Minutes())),
+	}
+	// Gönderim hatası da yanıtı değiştirmez; aksi hâlde adresin kayıtlı olduğu anlaşılır.
+	if err := h.Mailer.Send(ctx, msg); err != nil {
+		log.Printf("Şifre sıfırlama e-postası gönderilemedi (%s): %v\n", user.Email, err)
+	}
+
+	return h.resetRequested(c)
+}
+
+// ResetPassword, e-postayla gönderilen token'la kullanıcının şifresini değiştirir.
+// Başarılı bir sıfırlamadan sonra kullanıcının açık tüm session'ları, erişim ve
+// yenileme token'ları geçersiz olur.
+func (h *Handler) ResetPassword(c *fiber.Ctx) error {
+	req := middleware.Body[dto.ResetPasswordRequest](c)
+	ctx := c.UserContext()
+
+	// Token tek kullanımlıktır; yalnızca gerçekleşebilecek bir sıfırlamada harcanması için
+	// şifre önce doğrulanır ve hashlenir.
+	if err := h.checkPasswordPolicy("password", req.Password); err != nil {
+		return err
+	}
+	hashedPassword, err := auth.HashPassword(req.Password)
+	if err != nil {
+		log.Printf("Şifre hashleme hatası: %v\n", err)
+		return apierr.New(apierr.CodeInternal, "auth.hash_failed")
+	}
+
+	token, err := h.UserTokens.Consume(ctx, auth.HashToken(req.Token), models.TokenPurposePasswordReset)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierr.New(apierr.CodeResetTokenInvalid, "password.reset_token_invalid")
+	}
+	if err != nil {
+		log.Println("Şifre sıfırlama token'ı doğrulama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "password.reset_failed")
+	}
+
+	if err := h.Users.SetPassword(ctx, token.UserID, hashedPassword); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeResetTokenInvalid, "password.reset_token_invalid")
+		}
+		log.Println("Şifre güncelleme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "password.reset_failed")
+	}
+
+	if err := h.revokeAllSessions(c, token.UserID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "password.reset_done")})
+}
+
+// revokeAllSessions, kullanıcının tüm session'larını, erişim ve yenileme token'larını geçersiz kılar.
+func (h *Handler) revokeAllSessions(c *fiber.Ctx, userID uuid.UUID) error {
+	if err := h.RefreshTokens.RevokeAllForUser(c.UserContext(), userID); err != nil {
+		log.Println("Yenileme token'ları iptal edilemedi:", err)
+		return apierr.New(apierr.CodeInternal, "session.revoke_failed")
+	}
+	if err := middleware.RevokeSessions(h.AuthConfig(), userID); err != nil {
+		log.Println("Session'lar iptal edilemedi:", err)
+		return apierr.New(apierr.CodeInternal, "session.revoke_failed")
+	}
+	// Session'lar yukarıdaki işaretle zaten geçersizdir; dizinden silinmeleri storage'ı temizler.
+	if err := h.Sessions.RevokeAll(userID); err != nil {
+		log.Println("Session dizini temizlenemedi:", err)
+	}
+	return nil
+}
+
+func (h *Handler) resetRequested(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "password.reset_requested")})
+}
+
+// link, e-postalarda kullanılan ve token'ı sorgu parametresi olarak taşıyan bağlantıyı üretir.
+func (h *Handler) link(path, token string) string {
+	return strings.TrimRight(h.PublicURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+// mailLanguage, e-postanın yazılacağı dili seçer: kullanıcının tercihi, yoksa isteğin dili.
+func mailLanguage(c *fiber.Ctx, user *models.User) i18n.Lang {
+	if lang, ok := i18n.Parse(user.Language); ok {
+		return lang
+	}
+	return i18n.FromCtx(c)
+}
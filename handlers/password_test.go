@@ -0,0 +1,134 @@
+package handlers_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/middleware"
+)
+
+// mountPasswordReset, şifre sıfırlama, giriş ve kimlik doğrulaması isteyen rotaları ekler.
+func mountPasswordReset(env *testEnv) {
+	env.app.Post("/forgot", middleware.ValidateBody[dto.ForgotPasswordRequest](), env.h.ForgotPassword)
+	env.app.Post("/reset", middleware.ValidateBody[dto.ResetPasswordRequest](), env.h.ResetPassword)
+	env.app.Post("/login", middleware.ValidateBody[dto.LoginRequest](), env.h.LoginUser)
+	env.app.Post("/refresh", middleware.ValidateBody[dto.RefreshRequest](), env.h.RefreshToken)
+	env.app.Get("/me", middleware.AuthRequired(env.h.AuthConfig()), env.h.GetUser)
+}
+
+// requestReset, şifre sıfırlama bağlantısı ister ve e-postayla gönderilen token'ı döndürür.
+func requestReset(t *testing.T, env *testEnv, email string) string {
+	t.Helper()
+	env.do(http.MethodPost, "/forgot", dto.ForgotPasswordRequest{Email: email}, "").expect(t, http.StatusOK, "")
+	return env.mailToken("/reset-password")
+}
+
+func login(env *testEnv, email, password string) response {
+	return env.do(http.MethodPost, "/login", dto.LoginRequest{Email: email, Password: password}, "")
+}
+
+// nextSecond, bir sonraki saniyenin başına kadar bekler. Toplu iptal işareti JWT "iat"
+// alanı gibi saniye hassasiyetinde karşılaştırıldığından, iptalden önce açılan oturumların
+// işaretten önceki bir saniyeye düşmesi gerekir.
+func nextSecond() {
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+}
+
+func TestResetPasswordTokenIsSingleUse(t *testing.T) {
+	env := newTestEnv(t)
+	mountPasswordReset(env)
+	user := env.createUser("alice", auth.RoleUser)
+	token := requestReset(t, env, user.Email)
+
+	env.do(http.MethodPost, "/reset", dto.ResetPasswordRequest{Token: token, Password: "Yeni-sifre-1"}, "").
+		expect(t, http.StatusOK, "")
+	login(env, user.Email, testPassword).expect(t, http.StatusUnauthorized, apierr.CodeInvalidCredentials)
+	login(env, user.Email, "Yeni-sifre-1").expect(t, http.StatusOK, "")
+
+	// Kullanılmış token şifreyi bir daha değiştiremez.
+	env.do(http.MethodPost, "/reset", dto.ResetPasswordRequest{Token: token, Password: "Baska-sifre-2"}, "").
+		expect(t, http.StatusBadRequest, apierr.CodeResetTokenInvalid)
+	login(env, user.Email, "Baska-sifre-2").expect(t, http.StatusUnauthorized, apierr.CodeInvalidCredentials)
+}
+
+func TestResetPasswordTokenExpires(t *testing.T) {
+	env := newTestEnv(t)
+	mountPasswordReset(env)
+	user := env.createUser("alice", auth.RoleUser)
+	env.h.PasswordResetTTL = -time.Minute
+	token := requestReset(t, env, user.Email)
+
+	env.do(http.MethodPost, "/reset", dto.ResetPasswordRequest{Token: token, Password: "Yeni-sifre-1"}, "").
+		expect(t, http.StatusBadRequest, apierr.CodeResetTokenInvalid)
+	login(env, user.Email, testPassword).expect(t, http.StatusOK, "")
+}
+
+func TestResetPasswordPolicyRejectionKeepsToken(t *testing.T) {
+	env := newTestEnv(t)
+	mountPasswordReset(env)
+	user := env.createUser("alice", auth.RoleUser)
+	env.h.PasswordPolicy = auth.PasswordPolicy{MinLength: 8, RequireSymbol: true}
+	token := requestReset(t, env, user.Email)
+
+	env.do(http.MethodPost, "/reset", dto.ResetPasswordRequest{Token: token, Password: "Sembolsuz1"}, "").
+		expect(t, http.StatusBadRequest, apierr.CodeValidation)
+	login(env, user.Email, testPassword).expect(t, http.StatusOK, "")
+
+	// Politikaya takılan istek token'ı harcamaz; kullanıcı aynı bağlantıyla yeniden dener.
+	env.do(http.MethodPost, "/reset", dto.ResetPasswordRequest{Token: token, Password: "Sembollu-1"}, "").
+		expect(t, http.StatusOK, "")
+	login(env, user.Email, "Sembollu-1").expect(t, http.StatusOK, "")
+}
+
+func TestResetPasswordRevokesSessionsAndTokens(t *testing.T) {
+	env := newTestEnv(t)
+	mountPasswordReset(env)
+	user := env.createUser("alice", auth.RoleUser)
+
+	session := login(env, user.Email, testPassword)
+	session.expect(t, http.StatusOK, "")
+	tokens := tokenLogin(t, env, user.Email)
+	env.do(http.MethodGet, "/me", nil, session.cookie).expect(t, http.StatusOK, "")
+	env.doBearer(http.MethodGet, "/me", nil, tokens.AccessToken).expect(t, http.StatusOK, "")
+
+	nextSecond()
+	token := requestReset(t, env, user.Email)
+	env.do(http.MethodPost, "/reset", dto.ResetPasswordRequest{Token: token, Password: "Yeni-sifre-1"}, "").
+		expect(t, http.StatusOK, "")
+
+	env.do(http.MethodGet, "/me", nil, session.cookie).expect(t, http.StatusUnauthorized, apierr.CodeSessionExpired)
+	env.doBearer(http.MethodGet, "/me", nil, tokens.AccessToken).expect(t, http.StatusUnauthorized, apierr.CodeTokenExpired)
+	resp, _ := refresh(t, env, tokens.RefreshToken)
+	resp.expect(t, http.StatusUnauthorized, apierr.CodeRefreshInvalid)
+	if list, err := env.h.Sessions.List(user.ID); err != nil || len(list) != 0 {
+		t.Fatalf("sıfırlamadan sonra açık session'lar = %+v, %v", list, err)
+	}
+
+	// Yeni şifreyle açılan oturum geçerlidir.
+	fresh := login(env, user.Email, "Yeni-sifre-1")
+	fresh.expect(t, http.StatusOK, "")
+	env.do(http.MethodGet, "/me", nil, fresh.cookie).expect(t, http.StatusOK, "")
+}
+
+func TestForgotPasswordDoesNotRevealAccounts(t *testing.T) {
+	env := newTestEnv(t)
+	mountPasswordReset(env)
+	user := env.createUser("alice", auth.RoleUser)
+
+	known := env.do(http.MethodPost, "/forgot", dto.ForgotPasswordRequest{Email: user.Email}, "")
+	known.expect(t, http.StatusOK, "")
+	unknown := env.do(http.MethodPost, "/forgot", dto.ForgotPasswordRequest{Email: "bob@spoti.test"}, "")
+	unknown.expect(t, http.StatusOK, "")
+
+	if !bytes.Equal(known.body, unknown.body) {
+		t.Fatalf("yanıtlar farklı: %s / %s", known.body, unknown.body)
+	}
+	if n := env.sent(); n != 1 {
+		t.Fatalf("gönderilen e-posta sayısı = %d, beklenen 1", n)
+	}
+}
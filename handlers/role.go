@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/middleware"
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ListRoles, tüm rolleri izinleriyle birlikte listeler.
+func (h *Handler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.Roles.List(c.UserContext())
+	if err != nil {
+		log.Println("Rolleri listeleme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "role.list_failed")
+	}
+	return c.JSON(dto.NewRoles(roles))
+}
+
+// ListPermissions, rollere verilebilecek tüm izinleri listeler.
+func (h *Handler) ListPermissions(c *fiber.Ctx) error {
+	return c.JSON(auth.Permissions)
+}
+
+// CreateRole, verilen izinlerle yeni bir rol oluşturur.
+func (h *Handler) CreateRole(c *fiber.Ctx) error {
+	req := middleware.Body[dto.CreateRoleRequest](c)
+	if err := checkPermissions(req.Permissions); err != nil {
+		return err
+	}
+
+	role := models.Role{Name: req.Name, Permissions: normalizePermissions(req.Permissions)}
+	if err := h.Roles.Create(c.UserContext(), &role); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return apierr.New(apierr.CodeRoleExists, "role.exists", req.Name)
+		}
+		log.Println("Rol oluşturma hatası:", err)
+		return apierr.New(apierr.CodeInternal, "role.create_failed")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.NewRole(&role))
+}
+
+// UpdateRole, rolün izinlerini verilenlerle değiştirir. Yerleşik rollerin izinleri değiştirilemez.
+func (h *Handler) UpdateRole(c *fiber.Ctx) error {
+	name := c.Params("name")
+	req := middleware.Body[dto.UpdateRoleRequest](c)
+	if err := checkPermissions(req.Permissions); err != nil {
+		return err
+	}
+
+	if err := h.Roles.SetPermissions(c.UserContext(), name, normalizePermissions(req.Permissions)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeRoleNotFound, "role.not_found", name)
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			return apierr.New(apierr.CodeRoleBuiltIn, "role.built_in", name)
+		}
+		log.Println("Rol güncelleme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "role.update_failed")
+	}
+	// Rolün hangi kullanıcılarda önbelleğe alındığı bilinmediğinden önbellek tamamen temizlenir.
+	h.Permissions.Reset()
+
+	return c.JSON(fiber.Map{"message": i18n.Msg(c, "role.updated", name)})
+}
+
+// AssignRole, kullanıcıya verilen rolü atar. Adminler kendi rollerini değiştiremez; böylece
+// son yetkili hesap yanlışlıkla yetkisiz kalmaz.
+func (h *Handler) AssignRole(c *fiber.Ctx) error {
+	adminID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+	userID, err := uuid.Parse(c.Params("userID"))
+	if err != nil {
+		return apierr.New(apierr.CodeInvalidID, "user.invalid_id")
+	}
+	if userID == adminID {
+		return apierr.New(apierr.CodeForbidden, "role.self_assign_forbidden")
+	}
+
+	req := middleware.Body[dto.AssignRoleRequest](c)
+
+	// Rolün var olduğu önce kontrol edilir; SetRole'ün ErrNotFound hatası rolü ve
+	// kullanıcıyı ayırt etmez.
+	if _, err := h.Roles.GetByName(c.UserContext(), req.Role); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeRoleNotFound, "role.not_found", req.Role)
+		}
+		log.Println("Rol sorgulama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "role.assign_failed")
+	}
+	if err := h.Users.SetRole(c.UserContext(), userID, req.Role); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.not_found")
+		}
+		log.Println("Rol atama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "role.assign_failed")
+	}
+	h.Permissions.Forget(userID)
+
+	return c.JSON(fiber.Map{"message": i18n.Msg(c, "role.assigned", req.Role)})
+}
+
+// checkTargetRole, admin uçlarından yönetilen hedef kullanıcının çağıranın yetkisini aşmadığını
+// doğrular. Hedefin rolü çağıranın taşımadığı bir izin içeriyorsa işlem reddedilir; böylece
+// kullanıcı yönetimi izni olan destek hesapları adminleri düzenleyemez, silemez veya oturumlarını
+// kapatamaz. Rol yönetimi izni olan kullanıcılar bu uçlardan hiç yönetilemez; önce rolleri
+// değiştirilmelidir. Hedef bulunamazsa notFoundKey ile CodeUserNotFound döner.
+func (h *Handler) checkTargetRole(c *fiber.Ctx, targetID uuid.UUID, notFoundKey string) error {
+	callerID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+
+	target, err := h.Permissions.Role(c.UserContext(), targetID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, notFoundKey)
+		}
+		log.Println("Hedef kullanıcının rolü sorgulanamadı:", err)
+		return apierr.New(apierr.CodeInternal, "auth.permission_check_failed")
+	}
+	if slices.Contains(target.Permissions, auth.PermRolesManage) {
+		return apierr.New(apierr.CodeForbidden, "user.target_role_manager")
+	}
+
+	caller, err := h.Permissions.Role(c.UserContext(), callerID)
+	if err != nil {
+		log.Println("Rol sorgulama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.permission_check_failed")
+	}
+	for _, permission := range target.Permissions {
+		if !slices.Contains(caller.Permissions, permission) {
+			return apierr.New(apierr.CodeForbidden, "user.target_privileged", permission)
+		}
+	}
+	return nil
+}
+
+// checkPermissions, tanımlı olmayan her izin adı için bir doğrulama hatası döndürür.
+// İzinlerin listesi yalnızca auth.Permissions'ta tutulur; DTO etiketlerinde tekrarlanmaz.
+func checkPermissions(permissions []string) error {
+	var details []apierr.FieldError
+	for i, permission := range permissions {
+		if !auth.IsPermission(permission) {
+			details = append(details, apierr.FieldError{
+				Field: fmt.Sprintf("permissions[%d]", i),
+				Code:  "oneof",
+				Key:   "validation.oneof",
+				Args:  []any{strings.Join(auth.Permissions, ", ")},
+			})
+		}
+	}
+	if len(details) > 0 {
+		return apierr.Validation(details...)
+	}
+	return nil
+}
+
+// normalizePermissions, izinleri sıralar ve tekrarlananları çıkarır.
+func normalizePermissions(permissions []string) []string {
+	return slices.Compact(slices.Sorted(slices.Values(permissions)))
+}
@@ -0,0 +1,45 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/middleware"
+	"spoti/models"
+)
+
+func TestRolePermissionsMustBeKnown(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		valid       bool
+	}{
+		{"tanımlı izinler", []string{auth.PermSongsWrite, auth.PermUsersRead}, true},
+		{"tekrarlanan izin", []string{auth.PermSongsWrite, auth.PermSongsWrite}, true},
+		{"izinsiz rol", nil, true},
+		{"bilinmeyen izin", []string{auth.PermSongsWrite, "songs:delete"}, false},
+		{"boş izin adı", []string{""}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.app.Post("/roles", middleware.ValidateBody[dto.CreateRoleRequest](), env.h.CreateRole)
+			env.app.Put("/roles/:name", middleware.ValidateBody[dto.UpdateRoleRequest](), env.h.UpdateRole)
+			if err := env.mem.Roles().Create(context.Background(), &models.Role{Name: "reviewer"}); err != nil {
+				t.Fatal(err)
+			}
+
+			created, updated := http.StatusCreated, http.StatusOK
+			var code apierr.Code
+			if !tt.valid {
+				created, updated, code = http.StatusBadRequest, http.StatusBadRequest, apierr.CodeValidation
+			}
+			env.do(http.MethodPost, "/roles", dto.CreateRoleRequest{Name: "editor", Permissions: tt.permissions}, "").expect(t, created, code)
+			env.do(http.MethodPut, "/roles/reviewer", dto.UpdateRoleRequest{Permissions: tt.permissions}, "").expect(t, updated, code)
+		})
+	}
+}
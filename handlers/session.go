@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"spoti/apierr"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/middleware"
+	"spoti/sessions"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ListSessions, oturumdaki kullanıcının açık session'larını cihaz, IP ve son görülme
+// bilgileriyle listeler. İstek bir session'la yapıldıysa o session current olarak işaretlenir.
+func (h *Handler) ListSessions(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+
+	current, err := h.currentSessionKey(c)
+	if err != nil {
+		return err
+	}
+	list, err := h.Sessions.List(userID)
+	if err != nil {
+		log.Println("Session listeleme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "session.list_failed")
+	}
+
+	return c.JSON(dto.NewSessions(list, current))
+}
+
+// RevokeSession, oturumdaki kullanıcının bir session'ını sonlandırır.
+func (h *Handler) RevokeSession(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+
+	if err := h.Sessions.Revoke(userID, c.Params("sessionID")); err != nil {
+		if errors.Is(err, sessions.ErrNotFound) {
+			return apierr.New(apierr.CodeSessionNotFound, "session.not_found")
+		}
+		log.Println("Session sonlandırma hatası:", err)
+		return apierr.New(apierr.CodeInternal, "session.destroy_failed")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "session.revoked")})
+}
+
+// RevokeOtherSessions, oturumdaki kullanıcının istek yapılan session dışındaki bütün
+// session'larını sonlandırır. Bearer token veya API anahtarı etkilenmez.
+func (h *Handler) RevokeOtherSessions(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+
+	current, err := h.currentSessionKey(c)
+	if err != nil {
+		return err
+	}
+	revoked, err := h.Sessions.RevokeOthers(userID, current)
+	if err != nil {
+		log.Println("Session'lar sonlandırılamadı:", err)
+		return apierr.New(apierr.CodeInternal, "session.revoke_failed")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "session.others_revoked", revoked)})
+}
+
+// ForceLogoutUser, kullanıcının bütün cihazlardaki oturumlarını ve token'larını sonlandırır.
+// Çağırandan yetkili kullanıcıların oturumları kapatılamaz (bkz. checkTargetRole).
+func (h *Handler) ForceLogoutUser(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("userID"))
+	if err != nil {
+		return apierr.New(apierr.CodeInvalidID, "user.invalid_id")
+	}
+
+	if err := h.checkTargetRole(c, userID, "user.not_found"); err != nil {
+		return err
+	}
+
+	if err := h.revokeAllSessions(c, userID); err != nil {
+		return err
+	}
+
+	log.Printf("Kullanıcının tüm oturumları admin tarafından sonlandırıldı (%s).\n", userID)
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "session.force_logged_out")})
+}
+
+// currentSessionKey, istek session çereziyle doğrulandıysa session'ın storage anahtarını,
+// değilse boş dize döndürür.
+func (h *Handler) currentSessionKey(c *fiber.Ctx) (string, error) {
+	if middleware.AuthMethod(c) != middleware.AuthSession {
+		return "", nil
+	}
+	sess, err := h.Store.Get(c)
+	if err != nil {
+		log.Printf("Session alma hatası: %v\n", err)
+		return "", apierr.New(apierr.CodeInternal, "session.get_failed")
+	}
+	return sess.ID(), nil
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/middleware"
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RefreshToken, geçerli bir yenileme token'ı karşılığında yeni bir erişim token'ı verir.
+// Yenileme token'ı her kullanımda döndürülür: eskisi iptal edilir, aynı aileden yenisi
+// verilir. İptal edilmiş bir token tekrar gelirse token çalınmış sayılır ve tüm aile iptal edilir.
+func (h *Handler) RefreshToken(c *fiber.Ctx) error {
+	req := middleware.Body[dto.RefreshRequest](c)
+	ctx := c.UserContext()
+
+	current, err := h.RefreshTokens.GetByHash(ctx, auth.HashToken(req.RefreshToken))
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierr.New(apierr.CodeRefreshInvalid, "auth.refresh_invalid")
+	}
+	if err != nil {
+		log.Println("Yenileme token'ı sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.refresh_failed")
+	}
+
+	if current.RevokedAt != nil {
+		return h.refreshReused(c, current)
+	}
+	if !current.Active(time.Now()) {
+		return apierr.New(apierr.CodeRefreshInvalid, "auth.refresh_invalid")
+	}
+
+	user, err := h.Users.GetByID(ctx, current.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierr.New(apierr.CodeRefreshInvalid, "auth.refresh_invalid")
+	}
+	if err != nil {
+		log.Println("Kullanıcı sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.refresh_failed")
+	}
+
+	plain, next, err := h.newRefreshToken(user.ID, current.FamilyID)
+	if err != nil {
+		log.Println("Yenileme token'ı üretme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.token_issue_failed")
+	}
+	if err := h.RefreshTokens.Rotate(ctx, current.ID, next); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			// Aynı token'la eşzamanlı başka bir yenileme kazandı.
+			return h.refreshReused(c, current)
+		}
+		log.Println("Yenileme token'ı döndürme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.refresh_failed")
+	}
+
+	return h.writeTokens(c, user, plain)
+}
+
+// RevokeToken, yenileme token'ını ve aynı girişten türeyen tüm token'ları iptal eder.
+// Bilinmeyen token'lar için de başarılı yanıt döner; böylece token varlığı sızdırılmaz.
+func (h *Handler) RevokeToken(c *fiber.Ctx) error {
+	req := middleware.Body[dto.RefreshRequest](c)
+	ctx := c.UserContext()
+
+	current, err := h.RefreshTokens.GetByHash(ctx, auth.HashToken(req.RefreshToken))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Println("Yenileme token'ı sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.revoke_failed")
+	}
+	if current != nil {
+		if err := h.RefreshTokens.RevokeFamily(ctx, current.FamilyID); err != nil {
+			log.Println("Yenileme token'ı iptal hatası:", err)
+			return apierr.New(apierr.CodeInternal, "auth.revoke_failed")
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "auth.token_revoked")})
+}
+
+// respondWithTokens, kullanıcı için verilen ailede yeni bir token çifti üretir ve yanıtlar.
+func (h *Handler) respondWithTokens(c *fiber.Ctx, user *models.User, familyID uuid.UUID) error {
+	plain, token, err := h.newRefreshToken(user.ID, familyID)
+	if err != nil {
+		log.Println("Yenileme token'ı üretme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.token_issue_failed")
+	}
+	if err := h.RefreshTokens.Create(c.UserContext(), token); err != nil {
+		log.Println("Yenileme token'ı kaydetme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.token_issue_failed")
+	}
+	return h.writeTokens(c, user, plain)
+}
+
+// newRefreshToken, kaydedilmeye hazır bir yenileme token'ı ve istemciye verilecek düz hâlini üretir.
+func (h *Handler) newRefreshToken(userID, familyID uuid.UUID) (string, *models.RefreshToken, error) {
+	plain, hash, err := h.Tokens.NewRefreshToken()
+	if err != nil {
+		return "", nil, err
+	}
+	return plain, &models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(h.Tokens.RefreshTTL()),
+	}, nil
+}
+
+// writeTokens, kullanıcı için bir erişim token'ı imzalar ve yenileme token'ıyla birlikte döndürür.
+func (h *Handler) writeTokens(c *fiber.Ctx, user *models.User, refreshToken string) error {
+	accessToken, err := h.Tokens.IssueAccess(user.ID, user.Language)
+	if err != nil {
+		log.Println("Erişim token'ı imzalama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.token_issue_failed")
+	}
+
+	// Token yanıtları önbelleğe alınmamalıdır (RFC 6749, 5.1).
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).JSON(dto.TokenResponse{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(h.Tokens.AccessTTL().Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int(h.Tokens.RefreshTTL().Seconds()),
+	})
+}
+
+// refreshReused, iptal edilmiş bir yenileme token'ı kullanıldığında tüm aileyi iptal eder.
+func (h *Handler) refreshReused(c *fiber.Ctx, token *models.RefreshToken) error {
+	log.Printf("İptal edilmiş yenileme token'ı tekrar kullanıldı, aile iptal ediliyor (kullanıcı %s).\n", token.UserID)
+	if err := h.RefreshTokens.RevokeFamily(c.UserContext(), token.FamilyID); err != nil {
+		log.Println("Yenileme token'ı ailesi iptal hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.refresh_failed")
+	}
+	return apierr.New(apierr.CodeRefreshInvalid, "auth.refresh_invalid")
+}
@@ -0,0 +1,109 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/middleware"
+)
+
+// mountTokens, bearer token girişi ve yenileme rotalarını ekler.
+func mountTokens(env *testEnv) {
+	env.app.Post("/login", middleware.ValidateBody[dto.LoginRequest](), env.h.LoginUser)
+	env.app.Post("/refresh", middleware.ValidateBody[dto.RefreshRequest](), env.h.RefreshToken)
+	env.app.Post("/revoke", middleware.ValidateBody[dto.RefreshRequest](), env.h.RevokeToken)
+}
+
+func tokenLogin(t *testing.T, env *testEnv, email string) dto.TokenResponse {
+	t.Helper()
+	resp := env.do(http.MethodPost, "/login", dto.LoginRequest{Email: email, Password: testPassword, Tokens: true}, "")
+	resp.expect(t, http.StatusOK, "")
+	var tokens dto.TokenResponse
+	resp.decode(t, &tokens)
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Fatalf("token yanıtı eksik: %s", resp.body)
+	}
+	return tokens
+}
+
+func refresh(t *testing.T, env *testEnv, token string) (response, dto.TokenResponse) {
+	t.Helper()
+	resp := env.do(http.MethodPost, "/refresh", dto.RefreshRequest{RefreshToken: token}, "")
+	var tokens dto.TokenResponse
+	if resp.status == http.StatusOK {
+		resp.decode(t, &tokens)
+	}
+	return resp, tokens
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser("alice", auth.RoleUser)
+	mountTokens(env)
+
+	first := tokenLogin(t, env, user.Email)
+
+	resp, second := refresh(t, env, first.RefreshToken)
+	resp.expect(t, http.StatusOK, "")
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("yenileme token'ı döndürülmedi")
+	}
+
+	resp, third := refresh(t, env, second.RefreshToken)
+	resp.expect(t, http.StatusOK, "")
+
+	// Döndürülmüş token'ın tekrar kullanılması çalınma sayılır ve tüm aileyi iptal eder.
+	resp, _ = refresh(t, env, first.RefreshToken)
+	resp.expect(t, http.StatusUnauthorized, apierr.CodeRefreshInvalid)
+	resp, _ = refresh(t, env, third.RefreshToken)
+	resp.expect(t, http.StatusUnauthorized, apierr.CodeRefreshInvalid)
+}
+
+func TestRefreshTokenFamiliesAreIndependent(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser("alice", auth.RoleUser)
+	mountTokens(env)
+
+	phone := tokenLogin(t, env, user.Email)
+	laptop := tokenLogin(t, env, user.Email)
+
+	resp, rotated := refresh(t, env, phone.RefreshToken)
+	resp.expect(t, http.StatusOK, "")
+	resp, _ = refresh(t, env, phone.RefreshToken)
+	resp.expect(t, http.StatusUnauthorized, apierr.CodeRefreshInvalid)
+	resp, _ = refresh(t, env, rotated.RefreshToken)
+	resp.expect(t, http.StatusUnauthorized, apierr.CodeRefreshInvalid)
+
+	// Başka bir girişten türeyen aile etkilenmez.
+	resp, _ = refresh(t, env, laptop.RefreshToken)
+	resp.expect(t, http.StatusOK, "")
+}
+
+func TestRevokeToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token func(dto.TokenResponse) string
+	}{
+		{"geçerli token", func(tokens dto.TokenResponse) string { return tokens.RefreshToken }},
+		{"bilinmeyen token", func(dto.TokenResponse) string { return "bilinmeyen" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			user := env.createUser("alice", auth.RoleUser)
+			mountTokens(env)
+			tokens := tokenLogin(t, env, user.Email)
+
+			// Bilinmeyen token'lar da başarılı yanıt alır; token varlığı sızdırılmaz.
+			env.do(http.MethodPost, "/revoke", dto.RefreshRequest{RefreshToken: tt.token(tokens)}, "").expect(t, http.StatusOK, "")
+
+			resp, _ := refresh(t, env, tokens.RefreshToken)
+			if revoked := resp.status == http.StatusUnauthorized; revoked != (tt.token(tokens) == tokens.RefreshToken) {
+				t.Fatalf("iptal sonrası yenileme durumu = %d", resp.status)
+			}
+		})
+	}
+}
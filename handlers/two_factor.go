@@ -0,0 +1,362 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/middleware"
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// twoFactorChallengeTTL, şifresi doğrulanan kullanıcının ikinci adımı tamamlaması için tanınan süredir.
+const twoFactorChallengeTTL = 5 * time.Minute
+
+// twoFactorMaxAttempts, bir giriş denemesinde kabul edilen en fazla hatalı kod sayısıdır.
+// Aşılırsa kullanıcının şifresiyle yeniden giriş yapması gerekir.
+const twoFactorMaxAttempts = 5
+
+// twoFactorChallenge, şifre adımı tamamlanmış ama kodu beklenen bir girişin session
+// storage'ında tutulan durumudur. Hatalı kod sayısı ayrı bir sayaç anahtarında tutulur.
+type twoFactorChallenge struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Tokens    bool      `json:"tokens"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// twoFactorChallengeKey, giriş denemesinin storage anahtarıdır; token'ın kendisi değil hash'i kullanılır.
+func twoFactorChallengeKey(hash string) string {
+	return "2fa_challenge:" + hash
+}
+
+// twoFactorAttemptsKey, giriş denemesinde kontrol edilen kodların atomik sayacının anahtarıdır.
+func twoFactorAttemptsKey(hash string) string {
+	return "2fa_challenge_attempts:" + hash
+}
+
+// GetTwoFactorStatus, oturumdaki kullanıcının iki adımlı doğrulama durumunu döndürür.
+func (h *Handler) GetTwoFactorStatus(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+	ctx := c.UserContext()
+
+	status := dto.TwoFactorStatus{}
+	twoFactor, err := h.TwoFactor.Get(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Println("İki adımlı doğrulama sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "two_factor.fetch_failed")
+	}
+	if err == nil && twoFactor.Enabled() {
+		status.Enabled = true
+		status.EnabledAt = twoFactor.EnabledAt
+		if status.RecoveryCodesRemaining, err = h.TwoFactor.RemainingRecoveryCodes(ctx, userID); err != nil {
+			log.Println("Kurtarma kodu sayma hatası:", err)
+			return apierr.New(apierr.CodeInternal, "two_factor.fetch_failed")
+		}
+	}
+
+	return c.JSON(status)
+}
+
+// SetupTwoFactor, yeni bir TOTP gizli anahtarı üretir ve doğrulayıcı uygulamaya eklenecek
+// otpauth adresini döndürür. Kurulum, EnableTwoFactor ilk kodu doğrulayana kadar etkin değildir.
+func (h *Handler) SetupTwoFactor(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+	ctx := c.UserContext()
+
+	user, err := h.Users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.not_found")
+		}
+		log.Println("Veritabanı sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "user.fetch_failed")
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		log.Println("TOTP anahtarı üretme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "two_factor.setup_failed")
+	}
+	if err := h.TwoFactor.Begin(ctx, userID, secret); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return apierr.New(apierr.CodeTwoFactorEnabled, "two_factor.already_enabled")
+		}
+		log.Println("TOTP kurulumu kaydetme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "two_factor.setup_failed")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).JSON(dto.TwoFactorSetup{
+		Secret:     secret,
+		OTPAuthURI: auth.TOTPURI(user.Email, secret),
+	})
+}
+
+// EnableTwoFactor, doğrulayıcı uygulamadaki ilk kodu kontrol ederek iki adımlı doğrulamayı
+// etkinleştirir ve kurtarma kodlarını döndürür. Kodlar yalnızca bu yanıtta gösterilir.
+func (h *Handler) EnableTwoFactor(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+	req := middleware.Body[dto.TwoFactorCodeRequest](c)
+	ctx := c.UserContext()
+
+	twoFactor, err := h.TwoFactor.Get(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierr.New(apierr.CodeTwoFactorNotEnabled, "two_factor.setup_missing")
+	}
+	if err != nil {
+		log.Println("İki adımlı doğrulama sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "two_factor.enable_failed")
+	}
+	if twoFactor.Enabled() {
+		return apierr.New(apierr.CodeTwoFactorEnabled, "two_factor.already_enabled")
+	}
+
+	step, ok := auth.ValidateTOTP(twoFactor.Secret, req.Code, time.Now())
+	if !ok {
+		return apierr.New(apierr.CodeTwoFactorInvalid, "two_factor.code_invalid")
+	}
+
+	codes, hashes, err := auth.NewRecoveryCodes()
+	if err != nil {
+		log.Println("Kurtarma kodu üretme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "two_factor.enable_failed")
+	}
+	if err := h.TwoFactor.Enable(ctx, userID, step, hashes); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// Aynı anda başka bir istek kurulumu etkinleştirdi veya sildi.
+			return apierr.New(apierr.CodeTwoFactorNotEnabled, "two_factor.setup_missing")
+		}
+		log.Println("İki adımlı doğrulama etkinleştirme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "two_factor.enable_failed")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).JSON(dto.RecoveryCodes{
+		Message:       i18n.Msg(c, "two_factor.enabled"),
+		RecoveryCodes: codes,
+	})
+}
+
+// DisableTwoFactor, geçerli bir TOTP veya kurtarma koduyla iki adımlı doğrulamayı kapatır.
+// Admin hesapları kapattıktan sonra yeniden etkinleştirene kadar admin uçlarını kullanamaz.
+func (h *Handler) DisableTwoFactor(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+	req := middleware.Body[dto.TwoFactorCodeRequest](c)
+	ctx := c.UserContext()
+
+	user, err := h.Users.GetByID(c.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.not_found")
+		}
+		log.Println("Veritabanı sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "two_factor.disable_failed")
+	}
+	if err := h.checkSecondFactor(c, user, req.Code, "two_factor.disable_failed"); err != nil {
+		return err
+	}
+	if err := h.TwoFactor.Disable(ctx, userID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Println("İki adımlı doğrulama kapatma hatası:", err)
+		return apierr.New(apierr.CodeInternal, "two_factor.disable_failed")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "two_factor.disabled")})
+}
+
+// RegenerateRecoveryCodes, geçerli bir kodla kullanıcının kurtarma kodlarını yeniler;
+// eski kodların tümü geçersiz olur.
+func (h *Handler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+	req := middleware.Body[dto.TwoFactorCodeRequest](c)
+
+	user, err := h.Users.GetByID(c.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.not_found")
+		}
+		log.Println("Veritabanı sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "two_factor.recovery_failed")
+	}
+	if err := h.checkSecondFactor(c, user, req.Code, "two_factor.recovery_failed"); err != nil {
+		return err
+	}
+
+	codes, hashes, err := auth.NewRecoveryCodes()
+	if err != nil {
+		log.Println("Kurtarma kodu üretme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "two_factor.recovery_failed")
+	}
+	if err := h.TwoFactor.ReplaceRecoveryCodes(c.UserContext(), userID, hashes); err != nil {
+		log.Println("Kurtarma kodu kaydetme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "two_factor.recovery_failed")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).JSON(dto.RecoveryCodes{
+		Message:       i18n.Msg(c, "two_factor.recovery_regenerated"),
+		RecoveryCodes: codes,
+	})
+}
+
+// VerifyTwoFactorLogin, şifre adımından dönen challenge token'ı ve TOTP veya kurtarma
+// koduyla girişi tamamlar. Yanıt, ilk istekte seçilen yönteme göre session veya token çiftidir.
+func (h *Handler) VerifyTwoFactorLogin(c *fiber.Ctx) error {
+	req := middleware.Body[dto.TwoFactorLoginRequest](c)
+	hash := auth.HashToken(req.ChallengeToken)
+	key := twoFactorChallengeKey(hash)
+
+	raw, err := h.Store.Storage.Get(key)
+	if err != nil {
+		log.Println("Giriş doğrulaması okuma hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.login_failed")
+	}
+	var challenge twoFactorChallenge
+	if len(raw) == 0 || json.Unmarshal(raw, &challenge) != nil || !time.Now().Before(challenge.ExpiresAt) {
+		return apierr.New(apierr.CodeTwoFactorChallenge, "two_factor.challenge_invalid")
+	}
+
+	user, err := h.Users.GetByID(c.UserContext(), challenge.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierr.New(apierr.CodeTwoFactorChallenge, "two_factor.challenge_invalid")
+	}
+	if err != nil {
+		log.Println("Kullanıcı sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.login_failed")
+	}
+
+	// Deneme hakkı kod kontrol edilmeden önce atomik olarak ayrılır; eşzamanlı istekler
+	// twoFactorMaxAttempts'tan fazla kod deneyemez.
+	attempts, err := h.KV.Incr(twoFactorAttemptsKey(hash), time.Until(challenge.ExpiresAt))
+	if err != nil {
+		log.Println("Giriş doğrulaması sayacı artırma hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.login_failed")
+	}
+	if attempts > twoFactorMaxAttempts {
+		h.endTwoFactorChallenge(hash)
+		return apierr.New(apierr.CodeTwoFactorChallenge, "two_factor.challenge_invalid")
+	}
+
+	if err := h.checkSecondFactor(c, user, req.Code, "auth.login_failed"); err != nil {
+		if attempts == twoFactorMaxAttempts {
+			h.endTwoFactorChallenge(hash)
+		}
+		return err
+	}
+	h.endTwoFactorChallenge(hash)
+
+	h.loginSucceeded(user.Email)
+	return h.completeLogin(c, user, challenge.Tokens)
+}
+
+// startTwoFactorChallenge, şifresi doğrulanan kullanıcı için kısa ömürlü bir giriş denemesi
+// oluşturur. Session'a userID bu adımda yazılmaz.
+func (h *Handler) startTwoFactorChallenge(c *fiber.Ctx, userID uuid.UUID, tokens bool) error {
+	plain, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		log.Println("Giriş doğrulaması token'ı üretme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.login_failed")
+	}
+	challenge := twoFactorChallenge{UserID: userID, Tokens: tokens, ExpiresAt: time.Now().Add(twoFactorChallengeTTL)}
+	raw, err := json.Marshal(challenge)
+	if err != nil {
+		log.Println("Giriş doğrulaması kodlama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.login_failed")
+	}
+	if err := h.Store.Storage.Set(twoFactorChallengeKey(hash), raw, twoFactorChallengeTTL); err != nil {
+		log.Println("Giriş doğrulaması kaydetme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.login_failed")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).JSON(dto.TwoFactorChallenge{
+		Message:           i18n.Msg(c, "two_factor.code_required"),
+		TwoFactorRequired: true,
+		ChallengeToken:    plain,
+		ExpiresIn:         int(twoFactorChallengeTTL.Seconds()),
+	})
+}
+
+// endTwoFactorChallenge, giriş denemesini ve sayacını siler.
+func (h *Handler) endTwoFactorChallenge(hash string) {
+	for _, key := range []string{twoFactorChallengeKey(hash), twoFactorAttemptsKey(hash)} {
+		if err := h.Store.Storage.Delete(key); err != nil {
+			log.Println("Giriş doğrulaması silme hatası:", err)
+		}
+	}
+}
+
+// checkSecondFactor, kodu kullanıcının TOTP anahtarıyla veya kurtarma kodlarıyla doğrular.
+// Kabul edilen TOTP adımı ve kurtarma kodu bir daha kullanılamaz. Hatalı kodlar giriş
+// denemeleriyle aynı sayaçlara yazılır; hesap kilitliyken kod hiç kontrol edilmez. failKey,
+// beklenmeyen hatalarda dönecek mesajın anahtarıdır.
+func (h *Handler) checkSecondFactor(c *fiber.Ctx, user *models.User, code, failKey string) error {
+	ctx := c.UserContext()
+	userID := user.ID
+
+	block, err := h.Lockout.Check(user.Email, c.IP(), time.Now())
+	if err != nil {
+		log.Println("Giriş denemesi sayacı okuma hatası:", err)
+		return apierr.New(apierr.CodeInternal, failKey)
+	}
+	if block != nil {
+		return loginBlocked(block)
+	}
+
+	twoFactor, err := h.TwoFactor.Get(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !twoFactor.Enabled()) {
+		return apierr.New(apierr.CodeTwoFactorNotEnabled, "two_factor.not_enabled")
+	}
+	if err != nil {
+		log.Println("İki adımlı doğrulama sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, failKey)
+	}
+
+	if auth.IsTOTPCode(code) {
+		step, ok := auth.ValidateTOTP(twoFactor.Secret, code, time.Now())
+		if !ok {
+			return h.secondFactorFailed(c, user.Email)
+		}
+		err = h.TwoFactor.UseStep(ctx, userID, step)
+		if errors.Is(err, repository.ErrConflict) {
+			// Kod daha önce kullanılmış; ele geçirilmiş bir kodun tekrar oynatılması olabilir.
+			return h.secondFactorFailed(c, user.Email)
+		}
+	} else {
+		err = h.TwoFactor.UseRecoveryCode(ctx, userID, auth.HashRecoveryCode(code))
+		if errors.Is(err, repository.ErrNotFound) {
+			return h.secondFactorFailed(c, user.Email)
+		}
+		if err == nil {
+			log.Printf("Kullanıcı %s bir kurtarma kodu kullandı.\n", userID)
+		}
+	}
+	if err != nil {
+		log.Println("İki adımlı doğrulama kodu kaydetme hatası:", err)
+		return apierr.New(apierr.CodeInternal, failKey)
+	}
+	return nil
+}
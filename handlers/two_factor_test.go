@@ -0,0 +1,186 @@
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/kv"
+	"spoti/lockout"
+	"spoti/middleware"
+	"spoti/models"
+)
+
+// enableTwoFactor, kullanıcı için iki adımlı doğrulamayı etkinleştirir ve TOTP anahtarıyla
+// kurtarma kodlarını döndürür.
+func enableTwoFactor(t *testing.T, env *testEnv, user *models.User) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, hashes, err := auth.NewRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.mem.TwoFactor().Begin(ctx, user.ID, secret); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.mem.TwoFactor().Enable(ctx, user.ID, 0, hashes); err != nil {
+		t.Fatal(err)
+	}
+	return secret, codes
+}
+
+// wrongTOTP, anahtar için şu an geçerli olmayan bir TOTP kodu döndürür.
+func wrongTOTP(secret string) string {
+	for i := 0; ; i++ {
+		code := fmt.Sprintf("%06d", i)
+		if _, ok := auth.ValidateTOTP(secret, code, time.Now()); !ok {
+			return code
+		}
+	}
+}
+
+// mountLogin, giriş ve iki adımlı giriş doğrulaması rotalarını ekler.
+func mountLogin(env *testEnv) {
+	env.app.Post("/login", middleware.ValidateBody[dto.LoginRequest](), env.h.LoginUser)
+	env.app.Post("/2fa", middleware.ValidateBody[dto.TwoFactorLoginRequest](), env.h.VerifyTwoFactorLogin)
+}
+
+// startChallenge, doğru şifreyle giriş yapar ve dönen challenge token'ını döndürür.
+func startChallenge(t *testing.T, env *testEnv, user *models.User) string {
+	t.Helper()
+	resp := env.do(http.MethodPost, "/login", dto.LoginRequest{Email: user.Email, Password: testPassword}, "")
+	resp.expect(t, http.StatusOK, "")
+	var challenge dto.TwoFactorChallenge
+	resp.decode(t, &challenge)
+	if !challenge.TwoFactorRequired || challenge.ChallengeToken == "" {
+		t.Fatalf("giriş iki adımlı doğrulama istemedi: %s", resp.body)
+	}
+	return challenge.ChallengeToken
+}
+
+func accountFailures(t *testing.T, env *testEnv, email string) int {
+	t.Helper()
+	state, err := env.h.Lockout.Status(lockout.KindAccount, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state.Failures
+}
+
+func TestTwoFactorLoginFailuresLockAccount(t *testing.T) {
+	env := newTestEnv(t)
+	mountLogin(env)
+	user := env.createUser("alice", auth.RoleUser)
+	secret, codes := enableTwoFactor(t, env, user)
+
+	// Her denemede yeni bir challenge açılsa da hatalı kodlar aynı hesap sayacına yazılır.
+	for i := 1; i < 5; i++ {
+		token := startChallenge(t, env, user)
+		env.do(http.MethodPost, "/2fa", dto.TwoFactorLoginRequest{ChallengeToken: token, Code: wrongTOTP(secret)}, "").
+			expect(t, http.StatusUnauthorized, apierr.CodeTwoFactorInvalid)
+	}
+	token := startChallenge(t, env, user)
+	env.do(http.MethodPost, "/2fa", dto.TwoFactorLoginRequest{ChallengeToken: token, Code: wrongTOTP(secret)}, "").
+		expect(t, http.StatusTooManyRequests, apierr.CodeAccountLocked)
+
+	// Kilitliyken ne şifre ne de geçerli bir kod kabul edilir.
+	env.do(http.MethodPost, "/login", dto.LoginRequest{Email: user.Email, Password: testPassword}, "").
+		expect(t, http.StatusTooManyRequests, apierr.CodeAccountLocked)
+	env.do(http.MethodPost, "/2fa", dto.TwoFactorLoginRequest{ChallengeToken: token, Code: codes[0]}, "").
+		expect(t, http.StatusTooManyRequests, apierr.CodeAccountLocked)
+}
+
+func TestTwoFactorLoginResetsLockoutAfterSecondFactor(t *testing.T) {
+	env := newTestEnv(t)
+	mountLogin(env)
+	user := env.createUser("alice", auth.RoleUser)
+	_, codes := enableTwoFactor(t, env, user)
+
+	env.do(http.MethodPost, "/login", dto.LoginRequest{Email: user.Email, Password: "yanlis-sifre"}, "").
+		expect(t, http.StatusUnauthorized, apierr.CodeInvalidCredentials)
+	token := startChallenge(t, env, user)
+	if got := accountFailures(t, env, user.Email); got != 1 {
+		t.Fatalf("şifre adımından sonra hatalı deneme sayısı = %d, beklenen 1", got)
+	}
+
+	env.do(http.MethodPost, "/2fa", dto.TwoFactorLoginRequest{ChallengeToken: token, Code: codes[0]}, "").
+		expect(t, http.StatusOK, "")
+	if got := accountFailures(t, env, user.Email); got != 0 {
+		t.Fatalf("giriş tamamlandıktan sonra hatalı deneme sayısı = %d, beklenen 0", got)
+	}
+}
+
+func TestTwoFactorChallengeLimitsConcurrentAttempts(t *testing.T) {
+	env := newTestEnv(t)
+	mountLogin(env)
+	// Hesap kilidi devreye girmesin; yalnızca giriş denemesinin kendi sınırı sınanır.
+	env.h.Lockout = lockout.New(kv.NewMemory(), lockout.Policy{FreeAttempts: 100, MaxAttempts: 100, IPMaxAttempts: 100, Window: time.Hour, LockDuration: time.Hour})
+	user := env.createUser("alice", auth.RoleUser)
+	secret, codes := enableTwoFactor(t, env, user)
+	token := startChallenge(t, env, user)
+
+	const requests = 20
+	responses := make([]response, requests)
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i] = env.do(http.MethodPost, "/2fa", dto.TwoFactorLoginRequest{ChallengeToken: token, Code: wrongTOTP(secret)}, "")
+		}()
+	}
+	wg.Wait()
+
+	// Eşzamanlı istekler de en fazla beş kod deneyebilir; gerisi giriş denemesi geçersiz sayılarak reddedilir.
+	checked := 0
+	for _, resp := range responses {
+		switch apierr.Code(resp.errorCode(t)) {
+		case apierr.CodeTwoFactorInvalid:
+			checked++
+		case apierr.CodeTwoFactorChallenge:
+		default:
+			t.Fatalf("beklenmeyen yanıt: %d %s", resp.status, resp.body)
+		}
+	}
+	if checked != 5 {
+		t.Fatalf("kontrol edilen kod sayısı = %d, beklenen 5", checked)
+	}
+	env.do(http.MethodPost, "/2fa", dto.TwoFactorLoginRequest{ChallengeToken: token, Code: codes[0]}, "").
+		expect(t, http.StatusUnauthorized, apierr.CodeTwoFactorChallenge)
+}
+
+func TestCheckSecondFactorCountsFailures(t *testing.T) {
+	for _, path := range []string{"/2fa/disable", "/2fa/recovery-codes"} {
+		t.Run(path, func(t *testing.T) {
+			env := newTestEnv(t)
+			user := env.createUser("alice", auth.RoleUser)
+			secret, codes := enableTwoFactor(t, env, user)
+			env.app.Post("/2fa/disable", as(user.ID), middleware.ValidateBody[dto.TwoFactorCodeRequest](), env.h.DisableTwoFactor)
+			env.app.Post("/2fa/recovery-codes", as(user.ID), middleware.ValidateBody[dto.TwoFactorCodeRequest](), env.h.RegenerateRecoveryCodes)
+
+			for i := 1; i < 5; i++ {
+				env.do(http.MethodPost, path, dto.TwoFactorCodeRequest{Code: wrongTOTP(secret)}, "").
+					expect(t, http.StatusUnauthorized, apierr.CodeTwoFactorInvalid)
+			}
+			env.do(http.MethodPost, path, dto.TwoFactorCodeRequest{Code: wrongTOTP(secret)}, "").
+				expect(t, http.StatusTooManyRequests, apierr.CodeAccountLocked)
+			env.do(http.MethodPost, path, dto.TwoFactorCodeRequest{Code: codes[0]}, "").
+				expect(t, http.StatusTooManyRequests, apierr.CodeAccountLocked)
+
+			twoFactor, err := env.mem.TwoFactor().Get(context.Background(), user.ID)
+			if err != nil || !twoFactor.Enabled() {
+				t.Fatalf("iki adımlı doğrulama kilitliyken değişti: %v", err)
+			}
+		})
+	}
+}
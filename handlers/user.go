@@ -1,110 +1,180 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"log"
-	"spoti/models"
+
+	"spoti/apierr"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/middleware"
+	"spoti/repository"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/session"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v4"
 )
 
-var DB *pgx.Conn
-var Store *session.Store
-
 // GetUser, oturumdaki kullanıcının bilgilerini getirir.
-func GetUser(c *fiber.Ctx) error {
+func (h *Handler) GetUser(c *fiber.Ctx) error {
 	userIDLocal := c.Locals("userID")
 	if userIDLocal == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Oturum açık değil."})
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
 	}
 
 	userID, ok := userIDLocal.(uuid.UUID)
 	if !ok {
 		log.Printf("GetUser: userID yerel değişkeni UUID tipinde değil: %v\n", userIDLocal)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Sunucu hatası, userID geçersiz."})
+		return apierr.New(apierr.CodeInternal, "session.invalid_user_id")
 	}
 
-	var user models.User
-	// Sorguya username ve email sütunları eklendi
-	query := `SELECT id, username, email, role_id, hesap_turu, cash FROM t_users WHERE id = $1`
-	err := DB.QueryRow(context.Background(), query, userID).Scan(&user.ID, &user.Username, &user.Email, &user.RoleID, &user.HesapTuru, &user.Cash)
+	user, err := h.Users.GetByID(c.UserContext(), userID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Kullanıcı bulunamadı."})
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.not_found")
 		}
 		log.Println("Veritabanı sorgu hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kullanıcı bilgileri alınamadı."})
+		return apierr.New(apierr.CodeInternal, "user.fetch_failed")
+	}
+
+	return c.JSON(dto.NewSelfUser(user))
+}
+
+// GetPublicProfile, bir kullanıcının herkese açık profilini getirir.
+func (h *Handler) GetPublicProfile(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("userID"))
+	if err != nil {
+		return apierr.New(apierr.CodeInvalidID, "user.invalid_id")
+	}
+
+	user, err := h.Users.GetByID(c.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.not_found")
+		}
+		log.Println("Profil sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "user.fetch_failed")
 	}
 
-	return c.JSON(user)
+	return c.JSON(dto.NewPublicUser(user))
 }
 
-// UpdateUser, oturumdaki kullanıcının bilgilerini günceller.
-func UpdateUser(c *fiber.Ctx) error {
+// UpdateUser, oturumdaki kullanıcının profil bilgilerini günceller.
+func (h *Handler) UpdateUser(c *fiber.Ctx) error {
 	userIDLocal := c.Locals("userID")
 	if userIDLocal == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Oturum açık değil."})
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
 	}
 
 	userID, ok := userIDLocal.(uuid.UUID)
 	if !ok {
 		log.Printf("UpdateUser: userID yerel değişkeni UUID tipinde değil: %v\n", userIDLocal)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Sunucu hatası, userID geçersiz."})
+		return apierr.New(apierr.CodeInternal, "session.invalid_user_id")
 	}
 
-	var updatedUser models.User
-	if err := c.BodyParser(&updatedUser); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi."})
-	}
+	req := middleware.Body[dto.UpdateProfileRequest](c)
 
-	query := `UPDATE t_users SET hesap_turu = $1, cash = $2 WHERE id = $3`
-	commandTag, err := DB.Exec(context.Background(), query, updatedUser.HesapTuru, updatedUser.Cash, userID)
-	if err != nil {
+	if err := h.Users.UpdateProfile(c.UserContext(), userID, req.Username); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.update_not_found")
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			return apierr.New(apierr.CodeUsernameTaken, "user.username_taken")
+		}
 		log.Println("Veritabanı güncelleme hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kullanıcı bilgileri güncellenemedi."})
+		return apierr.New(apierr.CodeInternal, "user.update_failed")
 	}
 
-	if commandTag.RowsAffected() == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Güncellenecek kullanıcı bulunamadı."})
-	}
-
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Kullanıcı başarıyla güncellendi."})
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "user.updated")})
 }
 
 // DeleteUser, oturumdaki kullanıcının hesabını siler.
-func DeleteUser(c *fiber.Ctx) error {
+func (h *Handler) DeleteUser(c *fiber.Ctx) error {
 	userIDLocal := c.Locals("userID")
 	if userIDLocal == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Oturum açık değil."})
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
 	}
 
 	userID, ok := userIDLocal.(uuid.UUID)
 	if !ok {
 		log.Printf("DeleteUser: userID yerel değişkeni UUID tipinde değil: %v\n", userIDLocal)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Sunucu hatası, userID geçersiz."})
+		return apierr.New(apierr.CodeInternal, "session.invalid_user_id")
 	}
 
-	query := `DELETE FROM t_users WHERE id = $1`
-	commandTag, err := DB.Exec(context.Background(), query, userID)
-	if err != nil {
+	if err := h.Users.Delete(c.UserContext(), userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.delete_not_found")
+		}
 		log.Println("Veritabanı silme hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kullanıcı silinemedi."})
+		return apierr.New(apierr.CodeInternal, "user.delete_failed")
 	}
 
-	if commandTag.RowsAffected() == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Silinecek kullanıcı bulunamadı."})
+	// Diğer cihazlardaki oturumlar ve bearer token'lı istemcilerin yenileme token'ları da iptal
+	// edilir. Hesap zaten silindiği için hata isteği başarısız kılmaz, yalnızca loglanır.
+	if err := h.revokeAllSessions(c, userID); err != nil {
+		log.Printf("Silinen hesabın oturumları kapatılamadı (%s): %v\n", userID, err)
 	}
 
-	sess, err := Store.Get(c)
-	if err != nil {
-		log.Println("Session alınamadı:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Oturum sonlandırılamadı."})
+	// İstek session çereziyle geldiyse mevcut session da sonlandırılır.
+	if middleware.AuthMethod(c) == middleware.AuthSession {
+		sess, err := h.Store.Get(c)
+		if err != nil {
+			log.Println("Session alınamadı:", err)
+			return apierr.New(apierr.CodeInternal, "session.end_failed")
+		}
+
+		if err := sess.Destroy(); err != nil {
+			log.Println("Session sonlandırma hatası:", err)
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "user.deleted")})
+}
+
+// UpdateLanguage, oturumdaki kullanıcının yanıt dili tercihini kaydeder.
+// Boş dil gönderilirse tercih kaldırılır ve Accept-Language başlığı kullanılır.
+func (h *Handler) UpdateLanguage(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
 	}
 
-	sess.Destroy()
+	req := middleware.Body[dto.LanguageRequest](c)
+
+	// Boş değilse doğrulama dilin desteklendiğini garanti eder.
+	lang, _ := i18n.Parse(req.Language)
+
+	if err := h.Users.SetLanguage(c.UserContext(), userID, string(lang)); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.update_not_found")
+		}
+		log.Println("Dil tercihi güncelleme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "user.update_failed")
+	}
+
+	if lang == "" {
+		i18n.ResetLang(c)
+	} else {
+		i18n.SetLang(c, lang)
+	}
+
+	// Sonraki isteklerin veritabanına gitmeden tercihi kullanabilmesi için oturumda da sakla.
+	// Bearer token'lar tercihi bir sonraki yenilemede taşımaya başlar.
+	if middleware.AuthMethod(c) == middleware.AuthSession {
+		sess, err := h.Store.Get(c)
+		if err != nil {
+			log.Println("Session alınamadı:", err)
+			return apierr.New(apierr.CodeInternal, "session.get_failed")
+		}
+		if lang == "" {
+			sess.Delete("lang")
+		} else {
+			sess.Set("lang", string(lang))
+		}
+		if err := sess.Save(); err != nil {
+			log.Println("Session kaydetme hatası:", err)
+			return apierr.New(apierr.CodeInternal, "session.save_failed")
+		}
+	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Kullanıcı başarıyla silindi."})
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "user.language_updated"), "language": lang})
 }
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"spoti/apierr"
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GetAlbum, bir albümün bilgilerini getirir.
+func (h *Handler) GetAlbum(c *fiber.Ctx) error {
+	album, err := h.albumFromParam(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(album)
+}
+
+// GetAlbumTracks, bir albümün şarkılarını albümdeki sırasıyla listeler; sırası bilinmeyen
+// şarkılar sona konur.
+func (h *Handler) GetAlbumTracks(c *fiber.Ctx) error {
+	album, err := h.albumFromParam(c)
+	if err != nil {
+		return err
+	}
+
+	tracks, err := h.Albums.Tracks(c.UserContext(), album.ID)
+	if err != nil {
+		log.Println("Albüm şarkıları sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "album.tracks_failed")
+	}
+	if tracks == nil {
+		tracks = []models.Song{}
+	}
+
+	return c.JSON(fiber.Map{"album": album, "tracks": tracks})
+}
+
+// albumFromParam, albumID yol parametresindeki albümü getirir.
+func (h *Handler) albumFromParam(c *fiber.Ctx) (*models.Album, error) {
+	albumID, err := uuid.Parse(c.Params("albumID"))
+	if err != nil {
+		return nil, apierr.New(apierr.CodeInvalidID, "album.invalid_id")
+	}
+
+	album, err := h.Albums.GetByID(c.UserContext(), albumID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, apierr.New(apierr.CodeAlbumNotFound, "album.not_found")
+		}
+		log.Println("Albüm detay sorgu hatası:", err)
+		return nil, apierr.New(apierr.CodeInternal, "album.fetch_failed")
+	}
+	return album, nil
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"spoti/apierr"
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GetArtist, bir sanatçının bilgilerini getirir.
+func (h *Handler) GetArtist(c *fiber.Ctx) error {
+	artist, err := h.artistFromParam(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(artist)
+}
+
+// GetArtistAlbums, bir sanatçının albümlerini yeniden eskiye doğru listeler; yayın
+// tarihi bilinmeyen albümler sona konur.
+func (h *Handler) GetArtistAlbums(c *fiber.Ctx) error {
+	artist, err := h.artistFromParam(c)
+	if err != nil {
+		return err
+	}
+
+	albums, err := h.Artists.Albums(c.UserContext(), artist.ID)
+	if err != nil {
+		log.Println("Sanatçı albümleri sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "artist.albums_failed")
+	}
+	if albums == nil {
+		albums = []models.Album{}
+	}
+
+	return c.JSON(fiber.Map{"artist": artist, "albums": albums})
+}
+
+// artistFromParam, artistID yol parametresindeki sanatçıyı getirir.
+func (h *Handler) artistFromParam(c *fiber.Ctx) (*models.Artist, error) {
+	artistID, err := uuid.Parse(c.Params("artistID"))
+	if err != nil {
+		return nil, apierr.New(apierr.CodeInvalidID, "artist.invalid_id")
+	}
+
+	artist, err := h.Artists.GetByID(c.UserContext(), artistID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, apierr.New(apierr.CodeArtistNotFound, "artist.not_found")
+		}
+		log.Println("Sanatçı detay sorgu hatası:", err)
+		return nil, apierr.New(apierr.CodeInternal, "artist.fetch_failed")
+	}
+	return artist, nil
+}
@@ -1,289 +1,240 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"log"
 
+	"spoti/apierr"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/middleware"
 	"spoti/models"
+	"spoti/repository"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v4"
 )
 
+// freePlaylistLimit, Free hesapların bir çalma listesine ekleyebileceği en fazla şarkı sayısıdır.
+const freePlaylistLimit = 5
+
 // CreatePlaylist, kullanıcının yeni bir çalma listesi oluşturmasını sağlar.
-func CreatePlaylist(c *fiber.Ctx) error {
+func (h *Handler) CreatePlaylist(c *fiber.Ctx) error {
 	// Middleware'dan userID'yi al.
 	userIDLocal := c.Locals("userID")
 	if userIDLocal == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Oturum açık değil."})
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
 	}
 	userID, ok := userIDLocal.(uuid.UUID)
 	if !ok {
 		log.Printf("CreatePlaylist: userID yerel değişkeni UUID tipinde değil: %v\n", userIDLocal)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Sunucu hatası, userID geçersiz."})
+		return apierr.New(apierr.CodeInternal, "session.invalid_user_id")
 	}
 
-	var playlist models.Playlist
-	if err := c.BodyParser(&playlist); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz istek gövdesi."})
-	}
-	playlist.UserID = userID
+	req := middleware.Body[dto.CreatePlaylistRequest](c)
+	playlist := models.Playlist{Name: req.Name, UserID: userID}
 
-	query := `INSERT INTO t_playlist (name, user_id) VALUES ($1, $2) RETURNING id`
-	err := DB.QueryRow(context.Background(), query, playlist.Name, playlist.UserID).Scan(&playlist.ID)
-	if err != nil {
+	if err := h.Playlists.Create(c.UserContext(), &playlist); err != nil {
 		log.Println("Playlist oluşturma hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Çalma listesi oluşturulamadı."})
+		return apierr.New(apierr.CodeInternal, "playlist.create_failed")
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(playlist)
 }
 
 // GetUserPlaylists, oturumdaki kullanıcının çalma listelerini getirir.
-func GetUserPlaylists(c *fiber.Ctx) error {
+func (h *Handler) GetUserPlaylists(c *fiber.Ctx) error {
 	// Middleware'dan userID'yi al.
 	userIDLocal := c.Locals("userID")
 	if userIDLocal == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Oturum açık değil."})
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
 	}
 	userID, ok := userIDLocal.(uuid.UUID)
 	if !ok {
 		log.Printf("GetUserPlaylists: userID yerel değişkeni UUID tipinde değil: %v\n", userIDLocal)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Sunucu hatası, userID geçersiz."})
-	}
-
-	var playlists []models.Playlist
-	rows, err := DB.Query(context.Background(), `SELECT id, name, user_id FROM t_playlist WHERE user_id = $1`, userID)
-	if err != nil {
-		log.Println("Kullanıcı çalma listeleri sorgulama hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Çalma listeleri alınamadı."})
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var playlist models.Playlist
-		if err := rows.Scan(&playlist.ID, &playlist.Name, &playlist.UserID); err != nil {
-			log.Println("Playlist satır tarama hatası:", err)
-			continue
-		}
-		playlists = append(playlists, playlist)
-	}
-
-	// Rows döngüsünden sonra olası hataları kontrol et.
-	if err := rows.Err(); err != nil {
-		log.Printf("Döngü sonrası hata: %v\n", err)
+		return apierr.New(apierr.CodeInternal, "session.invalid_user_id")
 	}
 
-	return c.JSON(playlists)
+	return h.listPlaylists(c, userID)
 }
 
 // GetPlaylistByID, belirli bir çalma listesini ve içindeki şarkıları getirir.
-func GetPlaylistByID(c *fiber.Ctx) error {
+func (h *Handler) GetPlaylistByID(c *fiber.Ctx) error {
 	playlistID := c.Params("playlistID")
 	parsedPlaylistID, err := uuid.Parse(playlistID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz çalma listesi ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "playlist.invalid_id")
 	}
 
-	var playlist models.Playlist
-	query := `SELECT id, name, user_id FROM t_playlist WHERE id = $1`
-	err = DB.QueryRow(context.Background(), query, parsedPlaylistID).Scan(&playlist.ID, &playlist.Name, &playlist.UserID)
+	playlist, err := h.Playlists.GetByID(c.UserContext(), parsedPlaylistID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Çalma listesi bulunamadı."})
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodePlaylistNotFound, "playlist.not_found")
 		}
 		log.Println("Playlist sorgu hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Çalma listesi alınamadı."})
+		return apierr.New(apierr.CodeInternal, "playlist.fetch_failed")
 	}
 
-	var songs []models.Song
-	songsQuery := `
-        SELECT s.id, s.title, s.artist, s.album, s.duration, s.click_count 
-        FROM t_playlist_songs ps
-        JOIN t_songs s ON ps.song_id = s.id
-        WHERE ps.playlist_id = $1
-    `
-	rows, err := DB.Query(context.Background(), songsQuery, parsedPlaylistID)
+	songs, err := h.Playlists.Songs(c.UserContext(), parsedPlaylistID)
 	if err != nil {
 		log.Println("Playlist şarkıları sorgulama hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Çalma listesi şarkıları alınamadı."})
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var song models.Song
-		if err := rows.Scan(&song.ID, &song.Title, &song.Artist, &song.Album, &song.Duration, &song.ClickCount); err != nil {
-			log.Println("Şarkı satır tarama hatası:", err)
-			continue
-		}
-		songs = append(songs, song)
-	}
-	// rows.Next() döngüsünden sonra olası hataları kontrol et.
-	if err := rows.Err(); err != nil {
-		log.Printf("Döngü sonrası hata: %v\n", err)
+		return apierr.New(apierr.CodeInternal, "playlist.songs_failed")
 	}
 
 	return c.JSON(fiber.Map{"playlist": playlist, "songs": songs})
 }
 
 // DeletePlaylist, belirli bir çalma listesini siler.
-func DeletePlaylist(c *fiber.Ctx) error {
+func (h *Handler) DeletePlaylist(c *fiber.Ctx) error {
 	playlistID := c.Params("playlistID")
 	parsedPlaylistID, err := uuid.Parse(playlistID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz çalma listesi ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "playlist.invalid_id")
 	}
 
 	// Middleware'dan userID'yi al.
 	userIDLocal := c.Locals("userID")
 	if userIDLocal == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Oturum açık değil."})
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
 	}
 	userID, ok := userIDLocal.(uuid.UUID)
 	if !ok {
 		log.Printf("DeletePlaylist: userID yerel değişkeni UUID tipinde değil: %v\n", userIDLocal)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Sunucu hatası, userID geçersiz."})
+		return apierr.New(apierr.CodeInternal, "session.invalid_user_id")
 	}
 
 	// Kullanıcının kendi playlist'ini silmeye yetkisi var mı kontrol et
-	var ownerID uuid.UUID
-	err = DB.QueryRow(context.Background(), `SELECT user_id FROM t_playlist WHERE id = $1`, parsedPlaylistID).Scan(&ownerID)
+	playlist, err := h.Playlists.GetByID(c.UserContext(), parsedPlaylistID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Çalma listesi bulunamadı."})
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodePlaylistNotFound, "playlist.not_found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Veritabanı hatası."})
+		return apierr.New(apierr.CodeInternal, "common.database_error")
 	}
 
-	if ownerID != userID {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Bu çalma listesini silmeye yetkiniz yok."})
+	if playlist.UserID != userID {
+		return apierr.New(apierr.CodeForbidden, "playlist.delete_forbidden")
 	}
 
-	commandTag, err := DB.Exec(context.Background(), `DELETE FROM t_playlist WHERE id = $1`, parsedPlaylistID)
-	if err != nil {
+	if err := h.Playlists.Delete(c.UserContext(), parsedPlaylistID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodePlaylistNotFound, "playlist.delete_not_found")
+		}
 		log.Println("Playlist silme hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Çalma listesi silinemedi."})
-	}
-
-	if commandTag.RowsAffected() == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Silinecek çalma listesi bulunamadı."})
+		return apierr.New(apierr.CodeInternal, "playlist.delete_failed")
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Çalma listesi başarıyla silindi."})
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "playlist.deleted")})
 }
 
-// AddSongToPlaylist, bir şarkıyı çalma listesine ekler. Free kullanıcılar için 5 şarkı sınırı vardır.
-func AddSongToPlaylist(c *fiber.Ctx) error {
+// AddSongToPlaylist, bir şarkıyı çalma listesine ekler. Free kullanıcılar için freePlaylistLimit kadar şarkı sınırı vardır.
+func (h *Handler) AddSongToPlaylist(c *fiber.Ctx) error {
 	playlistID := c.Params("playlistID")
 	songID := c.Params("songID")
 	parsedPlaylistID, err := uuid.Parse(playlistID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz çalma listesi ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "playlist.invalid_id")
 	}
 	parsedSongID, err := uuid.Parse(songID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz şarkı ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "song.invalid_id")
 	}
 
 	// Middleware'dan userID'yi al.
 	userIDLocal := c.Locals("userID")
 	if userIDLocal == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Oturum açık değil."})
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
 	}
 
-	// Kullanıcının hesap türünü kontrol et
-	var accountType string
-	err = DB.QueryRow(context.Background(), `SELECT tu.hesap_turu FROM t_users tu JOIN t_playlist tp ON tu.id = tp.user_id WHERE tp.id = $1`, parsedPlaylistID).Scan(&accountType)
+	// Çalma listesi sahibinin hesap türünü kontrol et
+	playlist, err := h.Playlists.GetByID(c.UserContext(), parsedPlaylistID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Çalma listesi bulunamadı."})
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodePlaylistNotFound, "playlist.not_found")
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Kullanıcı bilgisi alınamadı."})
+		return apierr.New(apierr.CodeInternal, "user.fetch_failed")
+	}
+	owner, err := h.Users.GetByID(c.UserContext(), playlist.UserID)
+	if err != nil {
+		log.Println("Playlist sahibi sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "user.fetch_failed")
 	}
 
-	if accountType == "Free" {
+	if owner.HesapTuru == "Free" {
 		// Free kullanıcı için şarkı sayısını kontrol et
-		var songCount int
-		err = DB.QueryRow(context.Background(), `SELECT COUNT(*) FROM t_playlist_songs WHERE playlist_id = $1`, parsedPlaylistID).Scan(&songCount)
+		songCount, err := h.Playlists.CountSongs(c.UserContext(), parsedPlaylistID)
 		if err != nil {
 			log.Println("Şarkı sayısı sorgu hatası:", err)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Şarkı sayısı kontrol edilemedi."})
+			return apierr.New(apierr.CodeInternal, "playlist.count_failed")
 		}
-		if songCount >= 5 {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Ücretsiz kullanıcılar bir çalma listesine en fazla 5 şarkı ekleyebilir."})
+		if songCount >= freePlaylistLimit {
+			return apierr.New(apierr.CodePlaylistLimitReached, "playlist.limit_reached", freePlaylistLimit)
 		}
 	}
 
 	// Çalma listesine şarkıyı ekle
-	query := `INSERT INTO t_playlist_songs (playlist_id, song_id) VALUES ($1, $2)`
-	_, err = DB.Exec(context.Background(), query, parsedPlaylistID, parsedSongID)
-	if err != nil {
+	if err := h.Playlists.AddSong(c.UserContext(), parsedPlaylistID, parsedSongID); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return apierr.New(apierr.CodeSongAlreadyInPlaylist, "playlist.song_exists")
+		}
 		log.Println("Şarkı ekleme hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Şarkı çalma listesine eklenemedi."})
+		return apierr.New(apierr.CodeInternal, "playlist.add_song_failed")
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Şarkı başarıyla çalma listesine eklendi."})
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "playlist.song_added")})
 }
 
 // GetUserPlaylistsByUserID, belirli bir kullanıcının tüm çalma listelerini getirir.
-func GetUserPlaylistsByUserID(c *fiber.Ctx) error {
+func (h *Handler) GetUserPlaylistsByUserID(c *fiber.Ctx) error {
 	parsedUserID, err := uuid.Parse(c.Params("userID"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz kullanıcı ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "user.invalid_id")
 	}
 
-	var playlists []models.Playlist
-	rows, err := DB.Query(context.Background(), `SELECT id, name, user_id FROM t_playlist WHERE user_id = $1`, parsedUserID)
+	return h.listPlaylists(c, parsedUserID)
+}
+
+// listPlaylists, kullanıcının çalma listelerini ada göre sıralayarak sayfa sayfa döndürür.
+func (h *Handler) listPlaylists(c *fiber.Ctx, userID uuid.UUID) error {
+	q := middleware.Query[dto.ListQuery](c)
+	page, err := pageRequest[repository.PlaylistKey](q.Cursor, q.PageSize, q.IncludeTotal)
 	if err != nil {
-		log.Println("Kullanıcı çalma listeleri sorgulama hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Çalma listeleri alınamadı."})
+		return err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var playlist models.Playlist
-		if err := rows.Scan(&playlist.ID, &playlist.Name, &playlist.UserID); err != nil {
-			log.Println("Playlist satır tarama hatası:", err)
-			continue
-		}
-		playlists = append(playlists, playlist)
+	playlists, info, err := h.Playlists.ListByUser(c.UserContext(), userID, page)
+	if err != nil {
+		log.Println("Kullanıcı çalma listeleri sorgulama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "playlist.list_failed")
 	}
-
-	// rows.Next() döngüsünden sonra olası hataları kontrol et.
-	if err := rows.Err(); err != nil {
-		log.Printf("Döngü sonrası hata: %v\n", err)
+	if playlists == nil {
+		playlists = []models.Playlist{}
 	}
 
-	return c.JSON(playlists)
+	return c.JSON(dto.PlaylistPage{
+		Playlists: playlists,
+		PageInfo:  newPageInfo(playlists, info, q.PageSize, repository.NewPlaylistKey),
+	})
 }
 
 // GetUserPlaylistSongByUserID, belirli bir kullanıcının çalma listesindeki belirli bir şarkıyı getirir.
-func GetUserPlaylistSongByUserID(c *fiber.Ctx) error {
+func (h *Handler) GetUserPlaylistSongByUserID(c *fiber.Ctx) error {
 	parsedUserID, err := uuid.Parse(c.Params("userID"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz kullanıcı ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "user.invalid_id")
 	}
 	parsedSongID, err := uuid.Parse(c.Params("songID"))
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz şarkı ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "song.invalid_id")
 	}
 
-	var song models.Song
-	query := `
-        SELECT s.id, s.title, s.artist, s.album, s.duration, s.click_count 
-        FROM t_playlist_songs ps
-        JOIN t_playlist p ON ps.playlist_id = p.id
-        JOIN t_songs s ON ps.song_id = s.id
-        WHERE p.user_id = $1 AND s.id = $2
-    `
-	err = DB.QueryRow(context.Background(), query, parsedUserID, parsedSongID).Scan(&song.ID, &song.Title, &song.Artist, &song.Album, &song.Duration, &song.ClickCount)
+	song, err := h.Playlists.FindUserSong(c.UserContext(), parsedUserID, parsedSongID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Şarkı bu kullanıcının çalma listesinde bulunamadı."})
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeSongNotFound, "playlist.user_song_not_found")
 		}
 		log.Println("Şarkı sorgu hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Şarkı bilgileri alınamadı."})
+		return apierr.New(apierr.CodeInternal, "song.fetch_failed")
 	}
 
 	return c.JSON(song)
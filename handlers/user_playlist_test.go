@@ -0,0 +1,118 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/middleware"
+	"spoti/models"
+
+	"github.com/google/uuid"
+)
+
+// mountPlaylists, çalma listesi rotalarını userID adına ekler.
+func mountPlaylists(env *testEnv, userID uuid.UUID) {
+	env.app.Post("/playlist", as(userID), middleware.ValidateBody[dto.CreatePlaylistRequest](), env.h.CreatePlaylist)
+	env.app.Get("/playlist", as(userID), middleware.ValidateListQuery, env.h.GetUserPlaylists)
+	env.app.Get("/playlist/:playlistID", as(userID), env.h.GetPlaylistByID)
+	env.app.Delete("/playlist/:playlistID", as(userID), env.h.DeletePlaylist)
+	env.app.Post("/playlist/:playlistID/:songID", as(userID), env.h.AddSongToPlaylist)
+}
+
+func createPlaylist(t *testing.T, env *testEnv, name string) models.Playlist {
+	t.Helper()
+	resp := env.do(http.MethodPost, "/playlist", dto.CreatePlaylistRequest{Name: name}, "")
+	resp.expect(t, http.StatusCreated, "")
+	var playlist models.Playlist
+	resp.decode(t, &playlist)
+	return playlist
+}
+
+func TestPlaylistCRUD(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser("alice", auth.RoleUser)
+	mountPlaylists(env, user.ID)
+	song := env.createSong("Şımarık", "Tarkan")
+
+	playlist := createPlaylist(t, env, "Yolculuk")
+	if playlist.UserID != user.ID || playlist.Name != "Yolculuk" {
+		t.Fatalf("oluşturulan çalma listesi = %+v", playlist)
+	}
+	env.do(http.MethodPost, "/playlist", dto.CreatePlaylistRequest{}, "").expect(t, http.StatusBadRequest, apierr.CodeValidation)
+
+	path := "/playlist/" + playlist.ID.String()
+	env.do(http.MethodPost, path+"/"+song.ID.String(), nil, "").expect(t, http.StatusOK, "")
+
+	var detail struct {
+		Playlist models.Playlist `json:"playlist"`
+		Songs    []models.Song   `json:"songs"`
+	}
+	resp := env.do(http.MethodGet, path, nil, "")
+	resp.expect(t, http.StatusOK, "")
+	resp.decode(t, &detail)
+	if detail.Playlist.ID != playlist.ID || len(detail.Songs) != 1 || detail.Songs[0].ID != song.ID {
+		t.Fatalf("çalma listesi detayı = %s", resp.body)
+	}
+
+	var page dto.PlaylistPage
+	resp = env.do(http.MethodGet, "/playlist", nil, "")
+	resp.expect(t, http.StatusOK, "")
+	resp.decode(t, &page)
+	if len(page.Playlists) != 1 || page.Playlists[0].ID != playlist.ID {
+		t.Fatalf("çalma listeleri = %s", resp.body)
+	}
+
+	env.do(http.MethodDelete, path, nil, "").expect(t, http.StatusOK, "")
+	env.do(http.MethodGet, path, nil, "").expect(t, http.StatusNotFound, apierr.CodePlaylistNotFound)
+}
+
+func TestPlaylistErrorMapping(t *testing.T) {
+	env := newTestEnv(t)
+	user := env.createUser("alice", auth.RoleUser)
+	other := env.createUser("bob", auth.RoleUser)
+	if err := env.mem.Users().UpdateAccount(context.Background(), user.ID, "Free", 0); err != nil {
+		t.Fatal(err)
+	}
+	mountPlaylists(env, user.ID)
+	env.app.Delete("/other/playlist/:playlistID", as(other.ID), env.h.DeletePlaylist)
+
+	playlist := createPlaylist(t, env, "Yolculuk")
+	path := "/playlist/" + playlist.ID.String()
+	songs := make([]*models.Song, 6)
+	for i := range songs {
+		songs[i] = env.createSong("Şarkı "+string(rune('A'+i)), "Tarkan")
+	}
+	for _, song := range songs[:5] {
+		env.do(http.MethodPost, path+"/"+song.ID.String(), nil, "").expect(t, http.StatusOK, "")
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		code   apierr.Code
+	}{
+		{"geçersiz ID", http.MethodGet, "/playlist/abc", http.StatusBadRequest, apierr.CodeInvalidID},
+		{"olmayan çalma listesi (ErrNotFound)", http.MethodGet, "/playlist/" + uuid.NewString(), http.StatusNotFound, apierr.CodePlaylistNotFound},
+		{"olmayan çalma listesini silme", http.MethodDelete, "/playlist/" + uuid.NewString(), http.StatusNotFound, apierr.CodePlaylistNotFound},
+		{"Free sınırı", http.MethodPost, path + "/" + songs[5].ID.String(), http.StatusForbidden, apierr.CodePlaylistLimitReached},
+		{"başkasının çalma listesini silme", http.MethodDelete, "/other" + path, http.StatusForbidden, apierr.CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env.do(tt.method, tt.path, nil, "").expect(t, tt.status, tt.code)
+		})
+	}
+
+	// Sınır yalnızca Free hesaplara uygulanır; tekrar eklenen şarkı (ErrConflict) ise her hesapta reddedilir.
+	if err := env.mem.Users().UpdateAccount(context.Background(), user.ID, "Premium", 0); err != nil {
+		t.Fatal(err)
+	}
+	env.do(http.MethodPost, path+"/"+songs[5].ID.String(), nil, "").expect(t, http.StatusOK, "")
+	env.do(http.MethodPost, path+"/"+songs[0].ID.String(), nil, "").expect(t, http.StatusConflict, apierr.CodeSongAlreadyInPlaylist)
+}
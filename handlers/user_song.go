@@ -2,104 +2,153 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"log"
-	"strconv"
-	"strings"
 
+	"spoti/apierr"
+	"spoti/dto"
+	"spoti/middleware"
 	"spoti/models"
+	"spoti/repository"
+	"spoti/search"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v4"
 )
 
-// GetSongs, tüm şarkıları sayfalama ve arama filtreleriyle listeler.
-func GetSongs(c *fiber.Ctx) error {
-	// Sayfalama parametrelerini al
-	page, _ := strconv.Atoi(c.Query("page", "1"))
-	limit := 10
-	offset := (page - 1) * limit
-
-	// Arama parametresini al
-	searchQuery := c.Query("search", "")
+// GetSongs, şarkıları anahtar tabanlı sayfalama, filtre ve sıralama parametreleriyle
+// listeler. search parametresi başlık, sanatçı ve albümde aranır; eşleşen kelimeler her
+// şarkının highlight alanında işaretlenir. Sayfa belirteci başka bir sıralamayla
+// kullanılırsa CodeInvalidCursor döner.
+func (h *Handler) GetSongs(c *fiber.Ctx) error {
+	q := middleware.Query[dto.SongListQuery](c)
+	searchQuery := q.Search
+
+	sortBy := q.Sort
+	if sortBy == "" {
+		sortBy = repository.SongSortPopularity
+		if searchQuery != "" {
+			sortBy = repository.SongSortRelevance
+		}
+	}
+	// Aranacak kelime yoksa ilgi puanı hesaplanmaz; sayfa anahtarları popülerliğe göre üretilir.
+	if sortBy == repository.SongSortRelevance && len(search.Terms(searchQuery)) == 0 {
+		sortBy = repository.SongSortPopularity
+	}
+	desc := q.Order == "desc"
+	if q.Order == "" {
+		desc = sortBy == repository.SongSortPopularity || sortBy == repository.SongSortNewest || sortBy == repository.SongSortRelevance
+	}
 
-	var songs []models.Song
-	var rows pgx.Rows
-	var err error
-	var count int
+	page, err := pageRequest[repository.SongKey](q.Cursor, q.PageSize, q.IncludeTotal)
+	if err != nil {
+		return err
+	}
+	if key, _ := page.Boundary(); key != nil && (key.Sort != sortBy || key.Desc != desc) {
+		return apierr.New(apierr.CodeInvalidCursor, "request.invalid_cursor")
+	}
 
-	// Sorgu ve arama için dinamik WHERE ve COUNT
-	baseQuery := `SELECT id, title, artist, album, duration, click_count FROM t_songs`
-	countQuery := `SELECT COUNT(*) FROM t_songs`
-	whereClause := ""
-	args := []interface{}{}
+	songs, info, err := h.Songs.List(c.UserContext(), repository.SongFilter{
+		Search:      searchQuery,
+		Artist:      q.Artist,
+		Album:       q.Album,
+		Genre:       q.Genre,
+		MinDuration: q.MinDuration,
+		MaxDuration: q.MaxDuration,
+		YearFrom:    q.YearFrom,
+		YearTo:      q.YearTo,
+		Sort:        sortBy,
+		Desc:        desc,
+	}, page)
+	if err != nil {
+		log.Println("Şarkı sorgulama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "song.list_failed")
+	}
+	if songs == nil {
+		songs = []models.Song{}
+	}
 
-	if searchQuery != "" {
-		// Arama sorgusunu küçük harfe çevir ve LIKE ile eşleştir
-		whereClause = ` WHERE LOWER(title) LIKE $1`
-		args = append(args, "%"+strings.ToLower(searchQuery)+"%")
+	response := dto.SongPage{
+		Songs: songs,
+		PageInfo: newPageInfo(songs, info, q.PageSize, func(song *models.Song) repository.SongKey {
+			return repository.NewSongKey(song, sortBy, desc)
+		}),
+	}
+	// Sonuç bulunamayan aramalarda yazım hatası olabileceğinden en yakın ad önerilir.
+	if len(songs) == 0 && q.Cursor == "" && searchQuery != "" {
+		response.DidYouMean = h.didYouMean(c, searchQuery)
 	}
+	return c.JSON(response)
+}
+
+// SuggestSongs, yazılmakta olan arama için başlık, sanatçı ve albüm adlarından yazım
+// hatalarına dayanıklı öneriler döndürür. Sorgu SuggestTimeout içinde bitmezse istemci
+// bekletilmez; boş liste döner.
+func (h *Handler) SuggestSongs(c *fiber.Ctx) error {
+	q := middleware.Query[dto.SuggestQuery](c)
+
+	ctx, cancel := h.suggestContext(c)
+	defer cancel()
 
-	// Toplam şarkı sayısını al
-	err = DB.QueryRow(context.Background(), countQuery+whereClause, args...).Scan(&count)
+	suggestions, err := h.Songs.Suggest(ctx, q.Q, q.Limit)
 	if err != nil {
-		log.Println("Şarkı sayısı sorgu hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Şarkılar listelenemedi."})
+		if ctx.Err() == nil {
+			log.Println("Şarkı önerisi sorgulama hatası:", err)
+			return apierr.New(apierr.CodeInternal, "song.suggest_failed")
+		}
+		log.Printf("Şarkı önerisi süre sınırını aştı (%q): %v\n", q.Q, err)
+		suggestions = nil
+	}
+	if suggestions == nil {
+		suggestions = []models.SongSuggestion{}
 	}
 
-	// Veritabanından şarkıları sırala, sayfala ve çek
-	query := baseQuery + whereClause + ` ORDER BY click_count DESC LIMIT $` + strconv.Itoa(len(args)+1) + ` OFFSET $` + strconv.Itoa(len(args)+2)
-	args = append(args, limit, offset)
+	return c.JSON(fiber.Map{"suggestions": suggestions})
+}
+
+// didYouMean, sonuç bulunamayan arama için önerilecek adı döndürür. Öneri isteğe bağlı
+// olduğundan hata durumunda yalnızca loglanır ve boş metin döner.
+func (h *Handler) didYouMean(c *fiber.Ctx, searchQuery string) string {
+	ctx, cancel := h.suggestContext(c)
+	defer cancel()
 
-	rows, err = DB.Query(context.Background(), query, args...)
+	suggestion, err := h.Songs.DidYouMean(ctx, searchQuery)
 	if err != nil {
-		log.Println("Şarkı sorgulama hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Şarkılar listelenemedi."})
+		log.Println("Arama düzeltmesi sorgulama hatası:", err)
+		return ""
 	}
-	defer rows.Close()
+	return suggestion
+}
 
-	for rows.Next() {
-		var song models.Song
-		err := rows.Scan(&song.ID, &song.Title, &song.Artist, &song.Album, &song.Duration, &song.ClickCount)
-		if err != nil {
-			log.Println("Şarkı tarama hatası:", err)
-			continue
-		}
-		songs = append(songs, song)
+// suggestContext, öneri sorguları için SuggestTimeout ile sınırlanmış bir context döndürür.
+func (h *Handler) suggestContext(c *fiber.Ctx) (context.Context, context.CancelFunc) {
+	if h.SuggestTimeout <= 0 {
+		return context.WithCancel(c.UserContext())
 	}
-
-	return c.JSON(fiber.Map{
-		"songs":     songs,
-		"total":     count,
-		"page":      page,
-		"last_page": (count + limit - 1) / limit,
-	})
+	return context.WithTimeout(c.UserContext(), h.SuggestTimeout)
 }
 
 // GetSongByID, bir şarkının detaylarını getirir ve tıklanma sayısını artırır.
-func GetSongByID(c *fiber.Ctx) error {
+func (h *Handler) GetSongByID(c *fiber.Ctx) error {
 	songID := c.Params("songID")
 	parsedSongID, err := uuid.Parse(songID)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Geçersiz şarkı ID'si."})
+		return apierr.New(apierr.CodeInvalidID, "song.invalid_id")
 	}
 
 	// Şarkının click_count değerini 1 artır
-	_, err = DB.Exec(context.Background(), `UPDATE t_songs SET click_count = click_count + 1 WHERE id = $1`, parsedSongID)
-	if err != nil {
+	if err := h.Songs.IncrementClickCount(c.UserContext(), parsedSongID); err != nil && !errors.Is(err, repository.ErrNotFound) {
 		log.Println("Click count artırma hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Şarkı güncellenemedi."})
+		return apierr.New(apierr.CodeInternal, "song.update_failed")
 	}
 
-	var song models.Song
-	query := `SELECT id, title, artist, album, duration, click_count FROM t_songs WHERE id = $1`
-	err = DB.QueryRow(context.Background(), query, parsedSongID).Scan(&song.ID, &song.Title, &song.Artist, &song.Album, &song.Duration, &song.ClickCount)
+	song, err := h.Songs.GetByID(c.UserContext(), parsedSongID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Şarkı bulunamadı."})
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeSongNotFound, "song.not_found")
 		}
 		log.Println("Şarkı detay sorgu hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Şarkı bilgileri alınamadı."})
+		return apierr.New(apierr.CodeInternal, "song.fetch_failed")
 	}
 
 	return c.JSON(song)
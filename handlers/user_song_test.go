@@ -0,0 +1,172 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"spoti/apierr"
+	"spoti/dto"
+	"spoti/middleware"
+	"spoti/models"
+)
+
+// newSongEnv, şarkı listeleme ve öneri rotalarını küçük bir katalogla kurar.
+func newSongEnv(t *testing.T) *testEnv {
+	env := newTestEnv(t)
+	env.app.Get("/song", middleware.ValidateSongListQuery, env.h.GetSongs)
+	env.app.Get("/song/suggest", middleware.ValidateSuggestQuery, env.h.SuggestSongs)
+	for _, song := range []models.Song{
+		{Title: "Şımarık", Artist: "Tarkan", Album: "Ölürüm Sana", Duration: 240},
+		{Title: "Kuzu Kuzu", Artist: "Tarkan", Album: "Karma", Duration: 230},
+		{Title: "Gülpembe", Artist: "Barış Manço", Album: "Değmesin Yağlı Boya", Duration: 280},
+	} {
+		if err := env.mem.Songs().Create(context.Background(), &song); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return env
+}
+
+func TestSearchSongs(t *testing.T) {
+	tests := []struct {
+		name   string
+		search string
+		titles []string
+		// highlight, ilk sonucun başlığının vurgulanmış hâlidir.
+		highlight  string
+		didYouMean string
+	}{
+		{"Türkçe karakter sadeleştirme", "simarik", []string{"Şımarık"}, "<mark>Şımarık</mark>", ""},
+		{"kelime başı ve birden çok kelime", "tark kuz", []string{"Kuzu Kuzu"}, "<mark>Kuzu</mark> <mark>Kuzu</mark>", ""},
+		{"sanatçı adı", "MANÇO", []string{"Gülpembe"}, "Gülpembe", ""},
+		{"yazım hatası önerisi", "tarkn", nil, "", "Tarkan"},
+		{"öneri yok", "xyzzy", nil, "", ""},
+	}
+	env := newSongEnv(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := env.do(http.MethodGet, "/song?search="+url.QueryEscape(tt.search), nil, "")
+			resp.expect(t, http.StatusOK, "")
+			var page dto.SongPage
+			resp.decode(t, &page)
+
+			if len(page.Songs) != len(tt.titles) {
+				t.Fatalf("sonuçlar = %s", resp.body)
+			}
+			for i, song := range page.Songs {
+				if song.Title != tt.titles[i] {
+					t.Fatalf("%d. sonuç = %q, beklenen %q", i, song.Title, tt.titles[i])
+				}
+			}
+			if len(page.Songs) > 0 && (page.Songs[0].Highlight == nil || page.Songs[0].Highlight.Title != tt.highlight) {
+				t.Fatalf("vurgu = %+v, beklenen %q", page.Songs[0].Highlight, tt.highlight)
+			}
+			if page.DidYouMean != tt.didYouMean {
+				t.Fatalf("did_you_mean = %q, beklenen %q", page.DidYouMean, tt.didYouMean)
+			}
+		})
+	}
+}
+
+func TestSuggestSongs(t *testing.T) {
+	tests := []struct {
+		name  string
+		q     string
+		first models.SongSuggestion
+	}{
+		{"sanatçı tek kez önerilir", "tark", models.SongSuggestion{Kind: "artist", Value: "Tarkan"}},
+		{"yazım hatasına dayanıklı", "tarkn", models.SongSuggestion{Kind: "artist", Value: "Tarkan"}},
+		{"Türkçe karakter sadeleştirme", "gulpem", models.SongSuggestion{Kind: "title", Value: "Gülpembe"}},
+		{"albüm", "degmesin", models.SongSuggestion{Kind: "album", Value: "Değmesin Yağlı Boya"}},
+	}
+	env := newSongEnv(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := env.do(http.MethodGet, "/song/suggest?q="+url.QueryEscape(tt.q), nil, "")
+			resp.expect(t, http.StatusOK, "")
+			var body struct {
+				Suggestions []models.SongSuggestion `json:"suggestions"`
+			}
+			resp.decode(t, &body)
+			if len(body.Suggestions) == 0 || body.Suggestions[0].Kind != tt.first.Kind || body.Suggestions[0].Value != tt.first.Value {
+				t.Fatalf("öneriler = %s", resp.body)
+			}
+			for _, s := range body.Suggestions[1:] {
+				if s.Kind == tt.first.Kind && s.Value == tt.first.Value {
+					t.Fatalf("öneri tekrarlandı: %s", resp.body)
+				}
+			}
+		})
+	}
+
+	env.do(http.MethodGet, "/song/suggest?q=t", nil, "").expect(t, http.StatusBadRequest, apierr.CodeValidation)
+	// Eşleşme yoksa null yerine boş liste döner.
+	resp := env.do(http.MethodGet, "/song/suggest?q=xyzzy", nil, "")
+	resp.expect(t, http.StatusOK, "")
+	if got := strings.TrimSpace(string(resp.body)); got != `{"suggestions":[]}` {
+		t.Fatalf("eşleşmeyen öneri yanıtı = %s", got)
+	}
+}
+
+func TestSongPagination(t *testing.T) {
+	env := newTestEnv(t)
+	env.app.Get("/song", middleware.ValidateSongListQuery, env.h.GetSongs)
+	for _, title := range []string{"E", "C", "A", "D", "B"} {
+		env.createSong(title, "Tarkan")
+	}
+
+	list := func(t *testing.T, query string) dto.SongPage {
+		t.Helper()
+		resp := env.do(http.MethodGet, "/song?sort=title&page_size=2&"+query, nil, "")
+		resp.expect(t, http.StatusOK, "")
+		var page dto.SongPage
+		resp.decode(t, &page)
+		return page
+	}
+	titles := func(page dto.SongPage) string {
+		var s string
+		for _, song := range page.Songs {
+			s += song.Title
+		}
+		return s
+	}
+
+	// İleri yönde bütün sayfalar gezilir, sonra son sayfadan geri dönülür.
+	first := list(t, "include_total=true")
+	if titles(first) != "AB" || first.PrevCursor != "" || first.NextCursor == "" || first.Total == nil || *first.Total != 5 {
+		t.Fatalf("ilk sayfa = %+v", first)
+	}
+	second := list(t, "cursor="+first.NextCursor)
+	if titles(second) != "CD" || second.PrevCursor == "" || second.NextCursor == "" {
+		t.Fatalf("ikinci sayfa = %+v", second)
+	}
+	last := list(t, "cursor="+second.NextCursor)
+	if titles(last) != "E" || last.NextCursor != "" || last.PrevCursor == "" {
+		t.Fatalf("son sayfa = %+v", last)
+	}
+	back := list(t, "cursor="+last.PrevCursor)
+	if titles(back) != "CD" || back.PrevCursor == "" {
+		t.Fatalf("son sayfadan önceki sayfa = %+v", back)
+	}
+	start := list(t, "cursor="+back.PrevCursor)
+	if titles(start) != "AB" || start.PrevCursor != "" {
+		t.Fatalf("başa dönülen sayfa = %+v", start)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"bozuk belirteç", "/song?cursor=bozuk"},
+		{"başka sıralamanın belirteci", "/song?sort=duration&cursor=" + first.NextCursor},
+		{"başka yönün belirteci", "/song?sort=title&order=desc&cursor=" + first.NextCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env.do(http.MethodGet, tt.query, nil, "").expect(t, http.StatusBadRequest, apierr.CodeInvalidCursor)
+		})
+	}
+}
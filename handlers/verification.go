@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/i18n"
+	"spoti/mail"
+	"spoti/middleware"
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// verificationResendKey, kullanıcının bir sonraki doğrulama e-postasını isteyebileceği
+// zamanın session storage'ındaki anahtarıdır.
+func verificationResendKey(userID uuid.UUID) string {
+	return "verification_resend:" + userID.String()
+}
+
+// VerifyEmail, e-postayla gönderilen token'la kullanıcının e-posta adresini doğrular.
+// Oturum gerektirmez; bağlantı başka bir cihazda da açılabilir.
+func (h *Handler) VerifyEmail(c *fiber.Ctx) error {
+	req := middleware.Body[dto.VerifyEmailRequest](c)
+	ctx := c.UserContext()
+
+	token, err := h.UserTokens.Consume(ctx, auth.HashToken(req.Token), models.TokenPurposeEmailVerification)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apierr.New(apierr.CodeVerifyTokenInvalid, "verification.token_invalid")
+	}
+	if err != nil {
+		log.Println("E-posta doğrulama token'ı doğrulama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "verification.failed")
+	}
+
+	if err := h.Users.MarkEmailVerified(ctx, token.UserID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeVerifyTokenInvalid, "verification.token_invalid")
+		}
+		log.Println("E-posta doğrulama güncelleme hatası:", err)
+		return apierr.New(apierr.CodeInternal, "verification.failed")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "verification.verified")})
+}
+
+// ResendVerification, oturumdaki kullanıcıya yeni bir doğrulama bağlantısı gönderir.
+// İstekler VerificationResendInterval aralığıyla sınırlanır; yeni bağlantı öncekileri geçersiz kılar.
+func (h *Handler) ResendVerification(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(uuid.UUID)
+	if !ok {
+		return apierr.New(apierr.CodeUnauthenticated, "auth.not_logged_in")
+	}
+
+	user, err := h.Users.GetByID(c.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUserNotFound, "user.not_found")
+		}
+		log.Println("Veritabanı sorgu hatası:", err)
+		return apierr.New(apierr.CodeInternal, "user.fetch_failed")
+	}
+	if user.EmailVerified() {
+		return apierr.New(apierr.CodeAlreadyVerified, "verification.already_verified")
+	}
+
+	rawNext, err := h.Store.Storage.Get(verificationResendKey(userID))
+	if err != nil {
+		log.Println("Doğrulama e-postası bekleme süresi okuma hatası:", err)
+		return apierr.New(apierr.CodeInternal, "verification.send_failed")
+	}
+	if next, err := time.Parse(time.RFC3339Nano, string(rawNext)); err == nil {
+		if wait := time.Until(next); wait > 0 {
+			seconds := int(math.Ceil(wait.Seconds()))
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+			return apierr.New(apierr.CodeTooManyRequests, "verification.resend_throttled", seconds)
+		}
+	}
+
+	if err := h.sendVerification(c, user); err != nil {
+		log.Printf("Doğrulama e-postası gönderilemedi (%s): %v\n", user.Email, err)
+		return apierr.New(apierr.CodeInternal, "verification.send_failed")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": i18n.Msg(c, "verification.sent")})
+}
+
+// sendVerification, kullanıcı için yeni bir doğrulama token'ı üretir, bağlantıyı e-postayla
+// gönderir ve yeniden gönderim için bekleme süresini başlatır.
+func (h *Handler) sendVerification(c *fiber.Ctx, user *models.User) error {
+	ctx := c.UserContext()
+
+	plain, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	token := models.UserToken{
+		UserID:    user.ID,
+		Purpose:   models.TokenPurposeEmailVerification,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(h.EmailVerificationTTL),
+	}
+	if err := h.UserTokens.Create(ctx, &token); err != nil {
+		return err
+	}
+
+	lang := mailLanguage(c, user)
+	msg := mail.Message{
+		To:      user.Email,
+		Subject: i18n.Translate(lang, "mail.verify_email_subject"),
+		Body:    i18n.Translate(lang, "mail.verify_email_body", user.Username, h.link("/verify-email", plain), int(math.Ceil(h.EmailVerificationTTL.Hours()))),
+	}
+	if err := h.Mailer.Send(ctx, msg); err != nil {
+		return err
+	}
+
+	if h.VerificationResendInterval > 0 {
+		next := time.Now().Add(h.VerificationResendInterval).Format(time.RFC3339Nano)
+		if err := h.Store.Storage.Set(verificationResendKey(user.ID), []byte(next), h.VerificationResendInterval); err != nil {
+			log.Println("Doğrulama e-postası bekleme süresi kaydedilemedi:", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/middleware"
+)
+
+// mountVerification, kayıt, giriş ve e-posta doğrulama rotalarını, doğrulanmamış hesaplara
+// kapatılabilen işlemlerle birlikte uygulamadaki gibi ekler. RequireVerified kısıtlamaları
+// rota eklenirken okuduğundan UnverifiedRestrictions bundan önce atanmalıdır.
+func mountVerification(env *testEnv) {
+	authRequired := middleware.AuthRequired(env.h.AuthConfig())
+	env.app.Post("/register", middleware.ValidateBody[dto.RegisterRequest](), env.h.RegisterUser)
+	env.app.Post("/login", middleware.ValidateBody[dto.LoginRequest](), env.h.LoginUser)
+	env.app.Post("/verify-email", middleware.ValidateBody[dto.VerifyEmailRequest](), env.h.VerifyEmail)
+	env.app.Post("/verify-email/resend", authRequired, env.h.ResendVerification)
+	env.app.Post("/playlist", authRequired, env.h.RequireVerified(auth.RestrictPlaylistCreate), middleware.ValidateBody[dto.CreatePlaylistRequest](), env.h.CreatePlaylist)
+	env.app.Get("/playlist", authRequired, middleware.ValidateListQuery, env.h.GetUserPlaylists)
+	env.app.Post("/api-keys", authRequired, env.h.RequireVerified(auth.RestrictAPIKeys), middleware.ValidateBody[dto.CreateAPIKeyRequest](), env.h.CreateAPIKey)
+}
+
+// register, yeni bir hesap açar ve oturum açıp session çerezini döndürür.
+func register(t *testing.T, env *testEnv, username string) string {
+	t.Helper()
+	email := username + "@spoti.test"
+	env.do(http.MethodPost, "/register", dto.RegisterRequest{Username: username, Email: email, Password: testPassword}, "").
+		expect(t, http.StatusCreated, "")
+	session := login(env, email, testPassword)
+	session.expect(t, http.StatusOK, "")
+	return session.cookie
+}
+
+func TestVerifyEmailTokenIsSingleUse(t *testing.T) {
+	env := newTestEnv(t)
+	mountVerification(env)
+	cookie := register(t, env, "alice")
+	token := env.mailToken("/verify-email")
+
+	env.do(http.MethodPost, "/verify-email", dto.VerifyEmailRequest{Token: token}, "").expect(t, http.StatusOK, "")
+	user, err := env.mem.Users().GetByEmail(context.Background(), "alice@spoti.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.EmailVerified() {
+		t.Fatal("bağlantı açıldıktan sonra adres doğrulanmadı")
+	}
+
+	env.do(http.MethodPost, "/verify-email", dto.VerifyEmailRequest{Token: token}, "").
+		expect(t, http.StatusBadRequest, apierr.CodeVerifyTokenInvalid)
+	env.do(http.MethodPost, "/verify-email/resend", nil, cookie).expect(t, http.StatusConflict, apierr.CodeAlreadyVerified)
+}
+
+func TestVerifyEmailTokenExpires(t *testing.T) {
+	env := newTestEnv(t)
+	mountVerification(env)
+	env.h.EmailVerificationTTL = -time.Minute
+	register(t, env, "alice")
+
+	env.do(http.MethodPost, "/verify-email", dto.VerifyEmailRequest{Token: env.mailToken("/verify-email")}, "").
+		expect(t, http.StatusBadRequest, apierr.CodeVerifyTokenInvalid)
+}
+
+func TestResendVerificationIsThrottled(t *testing.T) {
+	env := newTestEnv(t)
+	mountVerification(env)
+	env.h.VerificationResendInterval = time.Minute
+	cookie := register(t, env, "alice")
+	user, err := env.mem.Users().GetByEmail(context.Background(), "alice@spoti.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := env.mailToken("/verify-email")
+
+	// Kayıtla gönderilen e-posta da bekleme süresini başlatır.
+	resp := env.do(http.MethodPost, "/verify-email/resend", nil, cookie)
+	resp.expect(t, http.StatusTooManyRequests, apierr.CodeTooManyRequests)
+	if wait, err := strconv.Atoi(resp.header.Get("Retry-After")); err != nil || wait < 1 || wait > 60 {
+		t.Fatalf("Retry-After = %q", resp.header.Get("Retry-After"))
+	}
+	if n := env.sent(); n != 1 {
+		t.Fatalf("gönderilen e-posta sayısı = %d, beklenen 1", n)
+	}
+
+	// Bekleme süresinin dolması, sürenin saklandığı anahtar silinerek taklit edilir.
+	if err := env.h.Store.Storage.Delete("verification_resend:" + user.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	env.do(http.MethodPost, "/verify-email/resend", nil, cookie).expect(t, http.StatusOK, "")
+	env.do(http.MethodPost, "/verify-email/resend", nil, cookie).expect(t, http.StatusTooManyRequests, apierr.CodeTooManyRequests)
+	if n := env.sent(); n != 2 {
+		t.Fatalf("gönderilen e-posta sayısı = %d, beklenen 2", n)
+	}
+
+	// Yeni bağlantı öncekini geçersiz kılar.
+	second := env.mailToken("/verify-email")
+	env.do(http.MethodPost, "/verify-email", dto.VerifyEmailRequest{Token: first}, "").
+		expect(t, http.StatusBadRequest, apierr.CodeVerifyTokenInvalid)
+	env.do(http.MethodPost, "/verify-email", dto.VerifyEmailRequest{Token: second}, "").expect(t, http.StatusOK, "")
+}
+
+func TestRequireVerifiedBlocksRestrictedActions(t *testing.T) {
+	env := newTestEnv(t)
+	env.h.UnverifiedRestrictions = []string{auth.RestrictPlaylistCreate, auth.RestrictAPIKeys}
+	mountVerification(env)
+	cookie := register(t, env, "alice")
+
+	env.do(http.MethodPost, "/playlist", dto.CreatePlaylistRequest{Name: "Yolculuk"}, cookie).
+		expect(t, http.StatusForbidden, apierr.CodeEmailNotVerified)
+	env.do(http.MethodPost, "/api-keys", dto.CreateAPIKeyRequest{Name: "betik", Scopes: []string{auth.ScopeSongsRead}}, cookie).
+		expect(t, http.StatusForbidden, apierr.CodeEmailNotVerified)
+	// Kısıtlanmamış işlemler açık kalır.
+	env.do(http.MethodGet, "/playlist", nil, cookie).expect(t, http.StatusOK, "")
+
+	// Doğrulama aynı session'da hemen geçerli olur; yeniden giriş gerekmez.
+	env.do(http.MethodPost, "/verify-email", dto.VerifyEmailRequest{Token: env.mailToken("/verify-email")}, "").expect(t, http.StatusOK, "")
+	env.do(http.MethodPost, "/playlist", dto.CreatePlaylistRequest{Name: "Yolculuk"}, cookie).expect(t, http.StatusCreated, "")
+	env.do(http.MethodPost, "/api-keys", dto.CreateAPIKeyRequest{Name: "betik", Scopes: []string{auth.ScopeSongsRead}}, cookie).
+		expect(t, http.StatusCreated, "")
+}
+
+func TestRequireVerifiedAllowsUnrestrictedActions(t *testing.T) {
+	env := newTestEnv(t)
+	env.h.UnverifiedRestrictions = []string{auth.RestrictAPIKeys}
+	mountVerification(env)
+	cookie := register(t, env, "alice")
+
+	env.do(http.MethodPost, "/playlist", dto.CreatePlaylistRequest{Name: "Yolculuk"}, cookie).expect(t, http.StatusCreated, "")
+	env.do(http.MethodPost, "/api-keys", dto.CreateAPIKeyRequest{Name: "betik", Scopes: []string{auth.ScopeSongsRead}}, cookie).
+		expect(t, http.StatusForbidden, apierr.CodeEmailNotVerified)
+}
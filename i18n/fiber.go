@@ -0,0 +1,60 @@
+package i18n
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// c.Locals anahtarları: localsKey isteğin geçerli dilini, negotiatedKey ise
+// Accept-Language başlığından seçilen dili tutar.
+const (
+	localsKey     = "lang"
+	negotiatedKey = "lang.negotiated"
+)
+
+// Middleware, isteğin dilini Accept-Language başlığından seçer. Başlık yoksa veya
+// desteklenen bir dil içermiyorsa fallback kullanılır. Oturum açmış kullanıcının
+// kayıtlı bir dil tercihi varsa kimlik doğrulama middleware'ı bunu SetLang ile ezer.
+func Middleware(fallback Lang) fiber.Handler {
+	offers := []string{string(fallback)}
+	for _, lang := range Supported() {
+		if lang != fallback {
+			offers = append(offers, string(lang))
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		lang := fallback
+		if accepted, ok := Parse(c.AcceptsLanguages(offers...)); ok {
+			lang = accepted
+		}
+		c.Locals(localsKey, lang)
+		c.Locals(negotiatedKey, lang)
+		c.Vary(fiber.HeaderAcceptLanguage)
+		return c.Next()
+	}
+}
+
+// SetLang, isteğin dilini değiştirir.
+func SetLang(c *fiber.Ctx, lang Lang) {
+	c.Locals(localsKey, lang)
+}
+
+// ResetLang, isteğin dilini Accept-Language başlığından seçilen dile geri döndürür.
+func ResetLang(c *fiber.Ctx) {
+	if lang, ok := c.Locals(negotiatedKey).(Lang); ok {
+		c.Locals(localsKey, lang)
+	}
+}
+
+// FromCtx, isteğin dilini döndürür. Middleware çalışmamışsa kaynak dil döner.
+func FromCtx(c *fiber.Ctx) Lang {
+	if lang, ok := c.Locals(localsKey).(Lang); ok {
+		return lang
+	}
+	return Source
+}
+
+// Msg, anahtarın isteğin dilindeki metnini döndürür.
+func Msg(c *fiber.Ctx, key string, args ...any) string {
+	return Translate(FromCtx(c), key, args...)
+}
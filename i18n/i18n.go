@@ -0,0 +1,101 @@
+// Package i18n, kullanıcıya gösterilen mesajların dil kataloğunu tutar.
+//
+// Mesajlar locales/<dil>.yaml dosyalarında anahtar bazında tanımlanır ve ikili
+// dosyaya gömülür. Handler'lar Türkçe veya İngilizce metin yerine anahtar kullanır;
+// metin, isteğin diline göre yanıt yazılırken seçilir.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lang, desteklenen bir dilin ISO 639-1 kodudur.
+type Lang string
+
+const (
+	TR Lang = "tr"
+	EN Lang = "en"
+)
+
+// Source, kataloğun eksiksiz olduğu kaynak dildir. Bir anahtarın istenen dilde
+// karşılığı yoksa bu dildeki metin kullanılır.
+const Source = TR
+
+// Supported, desteklenen dilleri döndürür.
+func Supported() []Lang {
+	return []Lang{TR, EN}
+}
+
+//go:embed locales/*.yaml
+var localeFS embed.FS
+
+// catalog, dil -> mesaj anahtarı -> metin eşlemesidir. init'te yüklenir ve
+// sonrasında yalnızca okunur.
+var catalog = map[Lang]map[string]string{}
+
+func init() {
+	for _, lang := range Supported() {
+		data, err := localeFS.ReadFile(path.Join("locales", string(lang)+".yaml"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: %s kataloğu okunamadı: %v", lang, err))
+		}
+		var tree map[string]any
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			panic(fmt.Sprintf("i18n: %s kataloğu çözümlenemedi: %v", lang, err))
+		}
+		messages := map[string]string{}
+		flatten("", tree, messages)
+		catalog[lang] = messages
+	}
+}
+
+// flatten, iç içe YAML anahtarlarını "grup.anahtar" biçiminde düzleştirir.
+func flatten(prefix string, tree map[string]any, out map[string]string) {
+	for k, v := range tree {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case map[string]any:
+			flatten(key, v, out)
+		case string:
+			out[key] = v
+		default:
+			panic(fmt.Sprintf("i18n: %q anahtarının değeri metin değil", key))
+		}
+	}
+}
+
+// Parse, "en", "EN-us" gibi bir dil etiketini desteklenen bir dile çevirir.
+func Parse(tag string) (Lang, bool) {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	for _, lang := range Supported() {
+		if base == string(lang) {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// Translate, anahtarın verilen dildeki metnini döndürür. Anahtar bu dilde yoksa
+// kaynak dile, orada da yoksa anahtarın kendisine düşer. args verilmişse metin
+// fmt.Sprintf kalıbı olarak kullanılır.
+func Translate(lang Lang, key string, args ...any) string {
+	msg, ok := catalog[lang][key]
+	if !ok {
+		msg, ok = catalog[Source][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
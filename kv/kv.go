@@ -0,0 +1,32 @@
+// Package kv, fiber.Storage'ın sunmadığı atomik sayaç ve hash işlemlerini ekler.
+//
+// fiber.Storage yalnızca anahtar bazında Get/Set/Delete sunar; bu yüzden bir değeri okuyup
+// değiştirip geri yazan kod, birden fazla sunucu örneği aynı anahtara yazdığında güncellemeleri
+// kaybeder. Store bu işlemleri Redis'in INCR/HINCRBY/HSET/HDEL komutlarıyla tek adımda yapar.
+package kv
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Store, atomik sayaç ve hash işlemleriyle genişletilmiş bir fiber.Storage'dır.
+//
+// ttl alan işlemler anahtarın ömrünü ttl'ye ayarlar; sıfır ttl ömrü değiştirmez.
+type Store interface {
+	fiber.Storage
+
+	// Incr, anahtardaki sayacı bir artırır ve yeni değeri döndürür. Anahtar yoksa sıfırdan başlar.
+	Incr(key string, ttl time.Duration) (int64, error)
+	// HIncr, hash'teki bir alanı bir artırır ve yeni değeri döndürür.
+	HIncr(key, field string, ttl time.Duration) (int64, error)
+	// HSet, hash'teki bir alanı yazar.
+	HSet(key, field string, value []byte, ttl time.Duration) error
+	// HGet, hash'teki bir alanı okur; alan yoksa nil döner.
+	HGet(key, field string) ([]byte, error)
+	// HGetAll, hash'in tüm alanlarını döndürür.
+	HGetAll(key string) (map[string][]byte, error)
+	// HDel, hash'ten verilen alanları siler.
+	HDel(key string, fields ...string) error
+}
@@ -0,0 +1,168 @@
+package kv
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Memory, testler ve tek örnekli kurulumlar için bellek içi Store'dur.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+type entry struct {
+	value   []byte
+	fields  map[string][]byte
+	expires time.Time
+}
+
+// NewMemory, boş bir bellek içi Store oluşturur.
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]*entry)}
+}
+
+func (m *Memory) Get(key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e := m.lookup(key); e != nil && e.fields == nil {
+		return slices.Clone(e.value), nil
+	}
+	return nil, nil
+}
+
+func (m *Memory) Set(key string, val []byte, exp time.Duration) error {
+	if key == "" || len(val) == 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := &entry{value: slices.Clone(val)}
+	if exp > 0 {
+		e.expires = time.Now().Add(exp)
+	}
+	m.entries[key] = e
+	return nil
+}
+
+func (m *Memory) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *Memory) Reset() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]*entry)
+	return nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+func (m *Memory) Incr(key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.lookup(key)
+	if e == nil {
+		e = &entry{}
+		m.entries[key] = e
+	}
+	n, _ := strconv.ParseInt(string(e.value), 10, 64)
+	n++
+	e.value = strconv.AppendInt(nil, n, 10)
+	e.expire(ttl)
+	return n, nil
+}
+
+func (m *Memory) HIncr(key, field string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.hash(key)
+	n, _ := strconv.ParseInt(string(e.fields[field]), 10, 64)
+	n++
+	e.fields[field] = strconv.AppendInt(nil, n, 10)
+	e.expire(ttl)
+	return n, nil
+}
+
+func (m *Memory) HSet(key, field string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.hash(key)
+	e.fields[field] = slices.Clone(value)
+	e.expire(ttl)
+	return nil
+}
+
+func (m *Memory) HGet(key, field string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e := m.lookup(key); e != nil {
+		return slices.Clone(e.fields[field]), nil
+	}
+	return nil, nil
+}
+
+func (m *Memory) HGetAll(key string) (map[string][]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fields := make(map[string][]byte)
+	if e := m.lookup(key); e != nil {
+		for field, value := range e.fields {
+			fields[field] = slices.Clone(value)
+		}
+	}
+	return fields, nil
+}
+
+func (m *Memory) HDel(key string, fields ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.lookup(key)
+	if e == nil {
+		return nil
+	}
+	for _, field := range fields {
+		delete(e.fields, field)
+	}
+	// Redis'te olduğu gibi boşalan hash silinir.
+	if len(e.fields) == 0 {
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+// lookup, çağıranın kilidi tuttuğu varsayımıyla anahtarı döndürür; süresi dolmuş anahtarları siler.
+func (m *Memory) lookup(key string) *entry {
+	e, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+	if !e.expires.IsZero() && !time.Now().Before(e.expires) {
+		delete(m.entries, key)
+		return nil
+	}
+	return e
+}
+
+// hash, çağıranın kilidi tuttuğu varsayımıyla anahtardaki hash'i döndürür; yoksa oluşturur.
+func (m *Memory) hash(key string) *entry {
+	e := m.lookup(key)
+	if e == nil || e.fields == nil {
+		e = &entry{fields: make(map[string][]byte)}
+		m.entries[key] = e
+	}
+	return e
+}
+
+func (e *entry) expire(ttl time.Duration) {
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+}
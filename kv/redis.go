@@ -0,0 +1,87 @@
+package kv
+
+import (
+	"context"
+	"time"
+
+	redisstore "github.com/gofiber/storage/redis/v3"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis, session store'un kullandığı Redis bağlantısı üzerinde çalışan Store'dur.
+type Redis struct {
+	*redisstore.Storage
+}
+
+// NewRedis, verilen Redis storage'ını Store olarak sarar.
+func NewRedis(storage *redisstore.Storage) *Redis {
+	return &Redis{Storage: storage}
+}
+
+func (r *Redis) Incr(key string, ttl time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	err := r.pipeline(key, ttl, func(pipe redis.Pipeliner) {
+		incr = pipe.Incr(context.Background(), key)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (r *Redis) HIncr(key, field string, ttl time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	err := r.pipeline(key, ttl, func(pipe redis.Pipeliner) {
+		incr = pipe.HIncrBy(context.Background(), key, field, 1)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (r *Redis) HSet(key, field string, value []byte, ttl time.Duration) error {
+	return r.pipeline(key, ttl, func(pipe redis.Pipeliner) {
+		pipe.HSet(context.Background(), key, field, value)
+	})
+}
+
+func (r *Redis) HGet(key, field string) ([]byte, error) {
+	value, err := r.Conn().HGet(context.Background(), key, field).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (r *Redis) HGetAll(key string) (map[string][]byte, error) {
+	values, err := r.Conn().HGetAll(context.Background(), key).Result()
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string][]byte, len(values))
+	for field, value := range values {
+		fields[field] = []byte(value)
+	}
+	return fields, nil
+}
+
+func (r *Redis) HDel(key string, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return r.Conn().HDel(context.Background(), key, fields...).Err()
+}
+
+// pipeline, yazma komutunu ve gerekiyorsa ömür ayarını tek bir MULTI/EXEC içinde çalıştırır;
+// böylece anahtar ömürsüz kalmaz.
+func (r *Redis) pipeline(key string, ttl time.Duration, write func(redis.Pipeliner)) error {
+	_, err := r.Conn().TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		write(pipe)
+		if ttl > 0 {
+			pipe.PExpire(context.Background(), key, ttl)
+		}
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,290 @@
+// Package lockout, başarısız giriş denemelerini hesap ve IP bazında sayarak kaba kuvvet
+// saldırılarını yavaşlatır ve gerekirse geçici olarak kilitler.
+//
+// Sayaçlar session store'un kullandığı storage'da (üretimde Redis) tutulur; böylece birden
+// fazla sunucu örneği aynı sayaçları görür. Sayaçlar HINCRBY ile atomik olarak artırılır;
+// eşzamanlı denemeler birbirinin artışını ezemez.
+//
+// Her hesap ve IP için iki kayıt vardır: Window süresince yaşayan sayaç hash'i
+// (login_failures:<tür>:<özne>) ve sınır aşıldığında yazılan, LockDuration süresince yaşayan
+// kilit kaydı (login_lock:<tür>:<özne>). Kilit yazılırken sayaç silinir; kilit kalkınca
+// sayım sıfırdan başlar.
+package lockout
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"spoti/kv"
+)
+
+// Sayaç türleri.
+const (
+	KindAccount = "account"
+	KindIP      = "ip"
+)
+
+// indexKey, etkin kilitlerin admin uçlarında listelenebilmesi için tutulan hash'in anahtarıdır.
+// Her kilit "<tür>:<özne>" alanına yazılır; böylece kilitler birbirinin kaydını ezmez.
+const indexKey = "login_lockouts"
+
+// Policy, sayaçların ve kilitlerin sınırlarını belirler.
+type Policy struct {
+	// FreeAttempts, hesap için gecikme uygulanmadan kabul edilen başarısız deneme sayısıdır.
+	FreeAttempts int
+	// MaxAttempts, hesabın kilitlendiği başarısız deneme sayısıdır.
+	MaxAttempts int
+	// IPMaxAttempts, bir IP adresinin tüm hesaplardaki denemeleri için kilit sınırıdır.
+	IPMaxAttempts int
+	// BaseDelay, FreeAttempts aşıldıktan sonraki ilk gecikmedir; her denemede iki katına çıkar.
+	BaseDelay time.Duration
+	// MaxDelay, tek bir gecikmenin üst sınırıdır.
+	MaxDelay time.Duration
+	// Window, son başarısız denemeden sonra sayacın sıfırlanması için geçmesi gereken süredir.
+	Window time.Duration
+	// LockDuration, sınır aşıldığında uygulanan kilidin süresidir.
+	LockDuration time.Duration
+}
+
+// Sayaç hash'inin alanları.
+const (
+	fieldFailures    = "failures"
+	fieldLastFailure = "last_failure"
+	fieldRetryAt     = "retry_at"
+)
+
+// State, bir hesabın veya IP adresinin sayaç durumudur. Kilit kayıtları da bu biçimde saklanır.
+type State struct {
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"last_failure"`
+	// RetryAt, gecikme nedeniyle bir sonraki denemenin yapılabileceği andır.
+	RetryAt time.Time `json:"retry_at,omitempty"`
+	// LockedUntil, kilidin kalkacağı andır; sıfır değer kilit olmadığını gösterir.
+	LockedUntil time.Time `json:"locked_until,omitempty"`
+}
+
+// Block, bir denemenin neden reddedildiğini açıklar.
+type Block struct {
+	Kind string
+	// Locked true ise sınır aşılmış ve kilit uygulanmıştır; false ise deneme yalnızca gecikmiştir.
+	Locked bool
+	Until  time.Time
+}
+
+// Lockout, admin uçlarında listelenen etkin bir kilittir.
+type Lockout struct {
+	Kind        string    `json:"kind"`
+	Subject     string    `json:"subject"`
+	Failures    int       `json:"failures"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// Guard, başarısız giriş denemelerini izler.
+type Guard struct {
+	store  kv.Store
+	policy Policy
+}
+
+// New, verilen store ve politikayla bir Guard oluşturur.
+func New(store kv.Store, policy Policy) *Guard {
+	return &Guard{store: store, policy: policy}
+}
+
+// Check, hesap ve IP için yeni bir giriş denemesine izin verilip verilmediğini kontrol eder.
+// Deneme engelliyse nedenini döndürür; engelli denemeler sayaçları artırmaz.
+func (g *Guard) Check(account, ip string, now time.Time) (*Block, error) {
+	ipState, err := g.Status(KindIP, ip)
+	if err != nil {
+		return nil, err
+	}
+	if now.Before(ipState.LockedUntil) {
+		return &Block{Kind: KindIP, Locked: true, Until: ipState.LockedUntil}, nil
+	}
+
+	accountState, err := g.Status(KindAccount, account)
+	if err != nil {
+		return nil, err
+	}
+	if now.Before(accountState.LockedUntil) {
+		return &Block{Kind: KindAccount, Locked: true, Until: accountState.LockedUntil}, nil
+	}
+	if now.Before(accountState.RetryAt) {
+		return &Block{Kind: KindAccount, Until: accountState.RetryAt}, nil
+	}
+	return nil, nil
+}
+
+// Fail, başarısız bir denemeyi hesap ve IP sayaçlarına ekler. Bu deneme bir kilidi
+// başlattıysa kilidi döndürür.
+func (g *Guard) Fail(account, ip string, now time.Time) (*Block, error) {
+	ipBlock, err := g.increment(KindIP, ip, g.policy.IPMaxAttempts, now)
+	if err != nil {
+		return nil, err
+	}
+	accountBlock, err := g.increment(KindAccount, account, g.policy.MaxAttempts, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if accountBlock != nil {
+		return accountBlock, nil
+	}
+	return ipBlock, nil
+}
+
+// Succeed, başarılı girişten sonra hesabın sayacını sıfırlar. IP sayacı korunur; aksi
+// hâlde saldırgan kendi hesabıyla giriş yaparak IP sayacını temizleyebilirdi.
+func (g *Guard) Succeed(account string) error {
+	return g.store.Delete(counterKey(KindAccount, account))
+}
+
+// Status, hesabın veya IP adresinin güncel sayaç durumunu döndürür. Etkin bir kilit varsa
+// kilidin kaydı döner.
+func (g *Guard) Status(kind, subject string) (State, error) {
+	raw, err := g.store.Get(lockKey(kind, subject))
+	if err != nil {
+		return State{}, err
+	}
+	if len(raw) > 0 {
+		var lock State
+		// Bozuk bir kilit kaydı girişleri engellememeli; sayaca bakılır.
+		if err := json.Unmarshal(raw, &lock); err == nil {
+			return lock, nil
+		}
+	}
+
+	fields, err := g.store.HGetAll(counterKey(kind, subject))
+	if err != nil {
+		return State{}, err
+	}
+	var state State
+	state.Failures, _ = strconv.Atoi(string(fields[fieldFailures]))
+	_ = state.LastFailure.UnmarshalText(fields[fieldLastFailure])
+	_ = state.RetryAt.UnmarshalText(fields[fieldRetryAt])
+	return state, nil
+}
+
+// Clear, hesabın veya IP adresinin sayacını ve kilidini kaldırır.
+func (g *Guard) Clear(kind, subject string) error {
+	if err := g.store.Delete(lockKey(kind, subject)); err != nil {
+		return err
+	}
+	if err := g.store.Delete(counterKey(kind, subject)); err != nil {
+		return err
+	}
+	return g.store.HDel(indexKey, indexField(kind, subject))
+}
+
+// Active, süresi dolmamış kilitleri döndürür. Süresi dolmuş kayıtlar dizinden temizlenir.
+func (g *Guard) Active(now time.Time) ([]Lockout, error) {
+	fields, err := g.store.HGetAll(indexKey)
+	if err != nil {
+		return nil, err
+	}
+	var expired []string
+	var active []Lockout
+	for field, raw := range fields {
+		var lock Lockout
+		if err := json.Unmarshal(raw, &lock); err != nil || !lock.LockedUntil.After(time.Now()) {
+			expired = append(expired, field)
+			continue
+		}
+		if now.Before(lock.LockedUntil) {
+			active = append(active, lock)
+		}
+	}
+	if err := g.store.HDel(indexKey, expired...); err != nil {
+		return nil, err
+	}
+	return active, nil
+}
+
+// NormalizeSubject, e-posta adreslerini büyük/küçük harf ve boşluk farklarından arındırır;
+// böylece aynı hesap için farklı yazımlar ayrı sayaçlar oluşturmaz.
+func NormalizeSubject(kind, subject string) string {
+	if kind == KindAccount {
+		return strings.ToLower(strings.TrimSpace(subject))
+	}
+	return subject
+}
+
+// increment, sayacı atomik olarak artırır ve gerekiyorsa gecikme veya kilit uygular.
+// Bu deneme kilidi başlattıysa kilidi döndürür.
+func (g *Guard) increment(kind, subject string, limit int, now time.Time) (*Block, error) {
+	key := counterKey(kind, subject)
+	n, err := g.store.HIncr(key, fieldFailures, g.policy.Window)
+	if err != nil {
+		return nil, err
+	}
+	failures := int(n)
+	if err := g.store.HSet(key, fieldLastFailure, timeText(now), g.policy.Window); err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && failures >= limit {
+		return g.lock(kind, subject, failures, now)
+	}
+	if kind == KindAccount && failures > g.policy.FreeAttempts {
+		// Eşzamanlı denemelerden daha küçük sayıyı gören geç yazabilir; bu yalnızca gecikmeyi
+		// bir adım kısaltır, sayacı ve kilidi etkilemez.
+		retryAt := now.Add(g.delay(failures - g.policy.FreeAttempts))
+		if err := g.store.HSet(key, fieldRetryAt, timeText(retryAt), g.policy.Window); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// lock, kilit kaydını yazar, kilidi dizine ekler ve sayacı sıfırlar.
+func (g *Guard) lock(kind, subject string, failures int, now time.Time) (*Block, error) {
+	until := now.Add(g.policy.LockDuration)
+	raw, err := json.Marshal(State{Failures: failures, LastFailure: now, LockedUntil: until})
+	if err != nil {
+		return nil, err
+	}
+	if err := g.store.Set(lockKey(kind, subject), raw, g.policy.LockDuration); err != nil {
+		return nil, err
+	}
+
+	entry, err := json.Marshal(Lockout{Kind: kind, Subject: NormalizeSubject(kind, subject), Failures: failures, LockedUntil: until})
+	if err != nil {
+		return nil, err
+	}
+	if err := g.store.HSet(indexKey, indexField(kind, subject), entry, g.policy.LockDuration); err != nil {
+		return nil, err
+	}
+
+	// Kilit kalkınca sayım sıfırdan başlar.
+	if err := g.store.Delete(counterKey(kind, subject)); err != nil {
+		return nil, err
+	}
+	return &Block{Kind: kind, Locked: true, Until: until}, nil
+}
+
+// delay, FreeAttempts aşıldıktan sonraki n. başarısız deneme için beklenecek süreyi hesaplar.
+func (g *Guard) delay(n int) time.Duration {
+	d := g.policy.BaseDelay
+	for i := 1; i < n && d < g.policy.MaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, g.policy.MaxDelay)
+}
+
+func timeText(t time.Time) []byte {
+	text, _ := t.MarshalText()
+	return text
+}
+
+func counterKey(kind, subject string) string {
+	return "login_failures:" + kind + ":" + NormalizeSubject(kind, subject)
+}
+
+func lockKey(kind, subject string) string {
+	return "login_lock:" + kind + ":" + NormalizeSubject(kind, subject)
+}
+
+func indexField(kind, subject string) string {
+	return kind + ":" + NormalizeSubject(kind, subject)
+}
@@ -0,0 +1,211 @@
+package lockout_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"spoti/kv"
+	"spoti/lockout"
+)
+
+var policy = lockout.Policy{
+	FreeAttempts:  2,
+	MaxAttempts:   6,
+	IPMaxAttempts: 10,
+	BaseDelay:     time.Second,
+	MaxDelay:      4 * time.Second,
+	Window:        time.Hour,
+	LockDuration:  time.Hour,
+}
+
+const (
+	email = "alice@spoti.test"
+	ip    = "10.0.0.1"
+)
+
+// newGuard, bellek içi store'u kullanan bir Guard döndürür.
+func newGuard() *lockout.Guard {
+	return lockout.New(kv.NewMemory(), policy)
+}
+
+func TestFailDelaysAndLocksAccount(t *testing.T) {
+	tests := []struct {
+		failures int
+		// delay, son denemeden sonra beklenmesi gereken süredir; sıfır gecikme olmadığını gösterir.
+		delay  time.Duration
+		locked bool
+	}{
+		{1, 0, false},
+		{2, 0, false},
+		{3, time.Second, false},
+		{4, 2 * time.Second, false},
+		{5, 4 * time.Second, false},
+		{6, 0, true},
+	}
+
+	guard := newGuard()
+	now := time.Now()
+	for _, tt := range tests {
+		block, err := guard.Fail(email, ip, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if locked := block != nil && block.Kind == lockout.KindAccount && block.Locked; locked != tt.locked {
+			t.Fatalf("%d. denemede kilit = %+v", tt.failures, block)
+		}
+
+		block, err = guard.Check(email, ip, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case tt.locked:
+			if block == nil || !block.Locked || !block.Until.Equal(now.Add(policy.LockDuration)) {
+				t.Fatalf("%d. denemeden sonra hesap kilitli değil: %+v", tt.failures, block)
+			}
+		case tt.delay == 0:
+			if block != nil {
+				t.Fatalf("%d. denemeden sonra beklenmeyen engel: %+v", tt.failures, block)
+			}
+		default:
+			if block == nil || block.Locked || !block.Until.Equal(now.Add(tt.delay)) {
+				t.Fatalf("%d. denemeden sonra gecikme = %+v, beklenen %s", tt.failures, block, tt.delay)
+			}
+			// Gecikme dolunca yeni denemeye izin verilir.
+			now = now.Add(tt.delay)
+		}
+	}
+
+	if block, _ := guard.Check(email, ip, now.Add(policy.LockDuration)); block != nil {
+		t.Fatalf("kilit süresi dolduktan sonra engel = %+v", block)
+	}
+}
+
+func TestIPLockSpansAccounts(t *testing.T) {
+	guard := lockout.New(kv.NewMemory(), lockout.Policy{FreeAttempts: 100, MaxAttempts: 100, IPMaxAttempts: 3, Window: time.Hour, LockDuration: time.Hour})
+	now := time.Now()
+
+	var block *lockout.Block
+	for _, account := range []string{"a@spoti.test", "b@spoti.test", "c@spoti.test"} {
+		var err error
+		if block, err = guard.Fail(account, ip, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if block == nil || block.Kind != lockout.KindIP || !block.Locked {
+		t.Fatalf("IP kilitlenmedi: %+v", block)
+	}
+	if block, _ := guard.Check("d@spoti.test", ip, now); block == nil || block.Kind != lockout.KindIP {
+		t.Fatalf("kilitli IP'den yeni hesaba deneme engellenmedi: %+v", block)
+	}
+	if block, _ := guard.Check("d@spoti.test", "10.0.0.2", now); block != nil {
+		t.Fatalf("başka IP engellendi: %+v", block)
+	}
+}
+
+func TestConcurrentFailuresAreCounted(t *testing.T) {
+	const attempts = 50
+	guard := lockout.New(kv.NewMemory(), lockout.Policy{FreeAttempts: attempts, MaxAttempts: attempts * 2, IPMaxAttempts: attempts * 2, Window: time.Hour, LockDuration: time.Hour})
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := guard.Fail(email, ip, now); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Eşzamanlı denemeler birbirinin artışını ezmemeli.
+	for _, c := range []struct{ kind, subject string }{{lockout.KindAccount, email}, {lockout.KindIP, ip}} {
+		if state, _ := guard.Status(c.kind, c.subject); state.Failures != attempts {
+			t.Fatalf("%s sayacı = %d, beklenen %d", c.kind, state.Failures, attempts)
+		}
+	}
+}
+
+func TestSucceedResetsAccountOnly(t *testing.T) {
+	guard := newGuard()
+	now := time.Now()
+	for range 3 {
+		if _, err := guard.Fail(email, ip, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := guard.Succeed(email); err != nil {
+		t.Fatal(err)
+	}
+	account, _ := guard.Status(lockout.KindAccount, email)
+	if account.Failures != 0 {
+		t.Fatalf("başarılı girişten sonra hesap sayacı = %d", account.Failures)
+	}
+	if block, _ := guard.Check(email, ip, now); block != nil {
+		t.Fatalf("başarılı girişten sonra engel = %+v", block)
+	}
+	// IP sayacı korunur; saldırgan kendi hesabıyla giriş yaparak onu sıfırlayamaz.
+	if state, _ := guard.Status(lockout.KindIP, ip); state.Failures != 3 {
+		t.Fatalf("IP sayacı = %d, beklenen 3", state.Failures)
+	}
+}
+
+func TestNormalizeSubject(t *testing.T) {
+	tests := []struct {
+		kind, subject, want string
+	}{
+		{lockout.KindAccount, " Alice@Spoti.Test ", "alice@spoti.test"},
+		{lockout.KindAccount, "alice@spoti.test", "alice@spoti.test"},
+		{lockout.KindIP, "10.0.0.1", "10.0.0.1"},
+		{lockout.KindIP, "FE80::1", "FE80::1"},
+	}
+	for _, tt := range tests {
+		if got := lockout.NormalizeSubject(tt.kind, tt.subject); got != tt.want {
+			t.Errorf("NormalizeSubject(%s, %q) = %q, beklenen %q", tt.kind, tt.subject, got, tt.want)
+		}
+	}
+
+	// Farklı yazımlar aynı sayacı paylaşır.
+	guard := newGuard()
+	if _, err := guard.Fail("ALICE@spoti.test", ip, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ := guard.Status(lockout.KindAccount, email); state.Failures != 1 {
+		t.Fatalf("büyük harfli adresin denemesi sayılmadı: %+v", state)
+	}
+}
+
+func TestActiveAndClear(t *testing.T) {
+	guard := newGuard()
+	now := time.Now()
+	for range policy.MaxAttempts {
+		if _, err := guard.Fail(email, ip, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	active, err := guard.Active(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 1 || active[0].Kind != lockout.KindAccount || active[0].Subject != email || active[0].Failures != policy.MaxAttempts {
+		t.Fatalf("etkin kilitler = %+v", active)
+	}
+	if expired, _ := guard.Active(now.Add(policy.LockDuration)); len(expired) != 0 {
+		t.Fatalf("süresi dolmuş kilitler listelendi: %+v", expired)
+	}
+
+	if err := guard.Clear(lockout.KindAccount, "Alice@Spoti.Test"); err != nil {
+		t.Fatal(err)
+	}
+	if active, _ := guard.Active(now); len(active) != 0 {
+		t.Fatalf("temizlenen kilit listelendi: %+v", active)
+	}
+	if block, _ := guard.Check(email, ip, now); block != nil {
+		t.Fatalf("temizlenen hesap engellendi: %+v", block)
+	}
+}
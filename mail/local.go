@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Log, mesajları göndermek yerine standart loga yazar. Yerel geliştirme içindir.
+type Log struct {
+	from *netmail.Address
+}
+
+var _ Mailer = (*Log)(nil)
+
+// NewLog, mesajları loga yazan bir gönderici oluşturur.
+func NewLog(from *netmail.Address) *Log {
+	return &Log{from: from}
+}
+
+func (m *Log) Send(ctx context.Context, msg Message) error {
+	log.Printf("E-posta (gönderilmedi) -> %s\n%s\n", msg.To, render(m.from, msg))
+	return nil
+}
+
+// File, her mesajı verilen dizine ayrı bir .eml dosyası olarak yazar.
+// Dosyalar bir e-posta istemcisiyle açılabilir; yerel geliştirme içindir.
+type File struct {
+	dir  string
+	from *netmail.Address
+}
+
+var _ Mailer = (*File)(nil)
+
+// NewFile, mesajları dir dizinine yazan bir gönderici oluşturur; dizin yoksa oluşturulur.
+func NewFile(dir string, from *netmail.Address) (*File, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("mail dizini oluşturulamadı: %w", err)
+	}
+	return &File{dir: dir, from: from}, nil
+}
+
+func (m *File) Send(ctx context.Context, msg Message) error {
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405"), uuid.NewString()[:8])
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, render(m.from, msg), 0o644); err != nil {
+		return fmt.Errorf("e-posta dosyası yazılamadı: %w", err)
+	}
+	log.Printf("E-posta %s adresine gönderilmek yerine %s dosyasına yazıldı.\n", msg.To, path)
+	return nil
+}
@@ -0,0 +1,60 @@
+// Package mail, kullanıcılara e-posta göndermek için değiştirilebilir gönderici gerçeklemeleri sağlar.
+// Üretimde SMTP, yerel geliştirmede mesajları loga veya dosyaya yazan göndericiler kullanılır.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	netmail "net/mail"
+	"time"
+
+	"spoti/config"
+)
+
+// Message, gönderilecek düz metin e-postadır.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer, e-posta gönderen bileşenlerin ortak arayüzüdür.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New, yapılandırmadaki sürücüye göre bir Mailer oluşturur.
+func New(cfg config.MailConfig) (Mailer, error) {
+	from, err := netmail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("mail.from geçersiz: %w", err)
+	}
+
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTP(cfg, from), nil
+	case "file":
+		return NewFile(cfg.Dir, from)
+	case "log":
+		return NewLog(from), nil
+	default:
+		return nil, fmt.Errorf("bilinmeyen mail sürücüsü: %q", cfg.Driver)
+	}
+}
+
+// render, mesajı RFC 5322 biçiminde UTF-8 düz metin e-postaya çevirir.
+func render(from *netmail.Address, msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	return b.Bytes()
+}
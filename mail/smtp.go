@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+
+	"spoti/config"
+)
+
+// SMTP, mesajları bir SMTP sunucusu üzerinden gönderir.
+// Sunucu destekliyorsa bağlantı STARTTLS ile şifrelenir.
+type SMTP struct {
+	addr string
+	auth smtp.Auth
+	from *netmail.Address
+}
+
+var _ Mailer = (*SMTP)(nil)
+
+// NewSMTP, verilen sunucu ayarlarıyla bir SMTP göndericisi oluşturur.
+// Kullanıcı adı boşsa kimlik doğrulaması yapılmaz.
+func NewSMTP(cfg config.MailConfig, from *netmail.Address) *SMTP {
+	m := &SMTP{
+		addr: net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+		from: from,
+	}
+	if cfg.SMTPUsername != "" {
+		m.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return m
+}
+
+func (m *SMTP) Send(ctx context.Context, msg Message) error {
+	// net/smtp context desteklemediğinden yalnızca başlamadan önce iptal kontrol edilir.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := smtp.SendMail(m.addr, m.auth, m.from.Address, []string{msg.To}, render(m.from, msg)); err != nil {
+		return fmt.Errorf("e-posta gönderilemedi (%s): %w", m.addr, err)
+	}
+	return nil
+}
@@ -1,24 +1,18 @@
 package main
 
 import (
-	"context"
 	"encoding/gob"
+	"flag"
+	"fmt"
 	"log"
+	"os"
 	"time"
 
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/session"
-	"github.com/gofiber/storage/redis/v3"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v4"
 
-	"spoti/handlers"
-	"spoti/middleware"
+	"spoti/config"
 )
 
-var store *session.Store
-var db *pgx.Conn
-
 func init() {
 	// Gob paketine uuid.UUID tipini kaydet.
 	// Bu, Fiber session modülünün UUID tipindeki verileri doğru şekilde saklamasını sağlar.
@@ -26,87 +20,55 @@ func init() {
 	gob.Register(time.Time{})
 }
 
-func main() {
-	// Redis için yeni bir Store oluştur
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), `Kullanım: spoti [-config dosya] <komut> [argümanlar]
+
+Komutlar:
+  serve [-migrate]                 HTTP sunucusunu başlatır (varsayılan komut)
+  migrate up|down|status|redo|version
+                                   Gömülü veritabanı migration'larını yönetir
+  migrate create [-dir dizin] ad   Yeni bir SQL migration dosyası oluşturur
+  admin create-admin -username ad -email e-posta [-password şifre]
+                                   Admin rolünde yeni bir kullanıcı oluşturur
+  admin promote -email e-posta [-role rol]
+                                   Kullanıcının rolünü değiştirir (varsayılan: admin)
+  admin reset-password -email e-posta [-password şifre]
+                                   Kullanıcının şifresini sıfırlar
+  admin seed                       Yerel geliştirme için demo verileri ekler
+
+Genel bayraklar:
+`)
+	flag.PrintDefaults()
+}
 
-	redisStore := redis.New(redis.Config{
-		Host: "redis",
-		Port: 6379,
-	})
+func main() {
+	flag.Usage = usage
+	configPath := flag.String("config", "", "YAML veya TOML yapılandırma dosyası (varsayılan: $SPOTI_CONFIG)")
+	flag.Parse()
 
-	store = session.New(session.Config{
-		Storage: redisStore,
-	})
+	command, args := "serve", flag.Args()
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
 
-	// Veritabanı bağlantısı
-	var err error
-	connStr := "user=postgres password=postgres dbname=spoti host=db sslmode=disable"
-	db, err = pgx.Connect(context.Background(), connStr)
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatalf("Veritabanına bağlanılamadı: %v\n", err)
+		log.Fatalf("Yapılandırma yüklenemedi: %v\n", err)
 	}
-	defer db.Close(context.Background())
 
-	err = db.Ping(context.Background())
+	switch command {
+	case "serve":
+		err = runServe(cfg, args)
+	case "migrate":
+		err = runMigrate(cfg, args)
+	case "admin":
+		err = runAdmin(cfg, args)
+	default:
+		fmt.Fprintf(os.Stderr, "Bilinmeyen komut: %q\n\n", command)
+		usage()
+		os.Exit(2)
+	}
 	if err != nil {
-		log.Fatalf("Veritabanı bağlantısı başarısız: %v\n", err)
+		log.Fatal(err)
 	}
-
-	app := fiber.New()
-
-	// Handler'lara veritabanı ve session nesnelerini aktar
-	handlers.DB = db
-	handlers.Store = store
-	middleware.Store = store
-	middleware.DB = db
-
-	api := app.Group("/api")
-
-	// Kimlik Doğrulama (Authentication) rotaları
-	api.Post("/user/register", handlers.RegisterUser)
-	api.Post("/user/login", handlers.LoginUser)
-	api.Post("/user/logout", handlers.LogoutUser)
-
-	// --- API Route'ları ---
-	// User API'leri için rotalar
-	userAPI := api.Group("/user", middleware.AuthRequired)
-	userAPI.Get("/", handlers.GetUser)
-	userAPI.Put("/", handlers.UpdateUser)
-	userAPI.Delete("/", handlers.DeleteUser)
-
-	// Şarkı ve Çalma Listesi (Playlist) Rotaları
-	userAPI.Get("/song", middleware.ValidatePageQuery, handlers.GetSongs)
-	userAPI.Get("/song/:songID", handlers.GetSongByID)
-	userAPI.Post("/playlist", handlers.CreatePlaylist)
-	userAPI.Get("/playlist", handlers.GetUserPlaylists)
-	userAPI.Get("/playlist/:playlistID", handlers.GetPlaylistByID)
-	userAPI.Delete("/playlist/:playlistID", handlers.DeletePlaylist)
-	userAPI.Post("/playlist/:playlistID/:songID", handlers.AddSongToPlaylist)
-
-	// Rota çakışmasını önlemek için rotalar güncellendi
-	userAPI.Get("/playlist/by-user/:userID", handlers.GetUserPlaylistsByUserID)
-	userAPI.Get("/playlist/by-user/:userID/:songID", handlers.GetUserPlaylistSongByUserID)
-
-	// Kupon ve Premium Üyelik Rotaları
-	userAPI.Get("/coupon", handlers.GetUserCoupons)
-	userAPI.Post("/premium/start", handlers.StartPremiumPurchase)
-	userAPI.Post("/premium", handlers.PurchasePremium)
-
-	// Admin API'leri için rotalar
-	adminAPI := api.Group("/admin", middleware.AdminRequired)
-	adminAPI.Get("/user", handlers.GetAllUsers)
-	adminAPI.Get("/user/:userID", handlers.GetUserByID)
-	adminAPI.Put("/user/:userID", handlers.UpdateUserByID)
-	adminAPI.Delete("/user/:userID", handlers.DeleteUserByID)
-
-	// Yeni Admin Rotaları
-	adminAPI.Post("/song", handlers.AdminCreateSong)
-	adminAPI.Delete("/song/:songID", handlers.AdminDeleteSong)
-	adminAPI.Put("/song/:songID", handlers.AdminUpdateSong)
-
-	// Kupon Admin Rotaları
-	adminAPI.Post("/coupon", handlers.CreateCoupon)
-	adminAPI.Post("/coupon/assign", handlers.AssignCoupon)
-
-	log.Fatal(app.Listen(":3000"))
 }
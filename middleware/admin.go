@@ -1,62 +1,94 @@
 package middleware
 
 import (
-	"context"
+	"errors"
 	"log"
+	"slices"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/repository"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v4"
 )
 
-// DB ve Store global değişkenleri main.go'dan aktarılacak.
-var DB *pgx.Conn
+// permissionsKey, AdminRequired'ın kullanıcının izinlerini RequirePermission'a aktardığı Locals anahtarıdır.
+const permissionsKey = "permissions"
 
-//var Store *session.Store
+// AdminRequired, admin uçlarına yalnızca en az bir izne sahip rollerdeki kullanıcıların
+// erişmesine izin veren bir middleware'dır. Hangi ucun hangi izni istediği RequirePermission
+// ile belirtilir. AuthRequired ile aynı yöntemleri kabul eder; API anahtarlarının ayrıca admin:*
+// kapsamı taşıması gerekir. Roller kısa süreliğine önbellekte tutulur; rol değişiklikleri token
+// süresini beklemeden geçerli olur. İki adımlı doğrulamayı etkinleştirmemiş yetkili kullanıcılar reddedilir.
+func AdminRequired(cfg AuthConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := authenticate(c, cfg)
+		if err != nil {
+			return err
+		}
+		if err := checkScope(c, auth.ScopeAdminAll); err != nil {
+			return err
+		}
+		if cfg.Permissions == nil {
+			log.Println("Rol çözümleyicisi atanamadı. Bu bir konfigürasyon hatasıdır.")
+			return apierr.New(apierr.CodeInternal, "auth.permission_check_failed")
+		}
 
-// AdminRequired, kullanıcının admin rolüne sahip olup olmadığını kontrol eden bir middleware'dır.
-func AdminRequired(c *fiber.Ctx) error {
-	sess, err := Store.Get(c)
-	if err != nil {
-		log.Printf("Session alınamadı: %v\n", err)
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Oturum bulunamadı veya geçersiz."})
-	}
+		role, err := cfg.Permissions.Role(c.UserContext(), userID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				// Kullanıcı bulunamazsa yetki reddedilir.
+				return apierr.New(apierr.CodeForbidden, "auth.forbidden")
+			}
+			log.Println("Rol sorgulama hatası:", err)
+			return apierr.New(apierr.CodeInternal, "auth.permission_check_failed")
+		}
 
-	userIDLocal := sess.Get("userID")
-	if userIDLocal == nil {
-		// Eğer userID yoksa, kullanıcı oturum açmamıştır veya AuthRequired çalışmamıştır.
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Oturum açık değil."})
-	}
+		// Hiçbir izni olmayan roller (ör. user) admin uçlarına hiç erişemez.
+		if len(role.Permissions) == 0 {
+			return apierr.New(apierr.CodeAdminRequired, "auth.admin_required")
+		}
+		if err := checkTwoFactor(c, cfg, userID); err != nil {
+			return err
+		}
 
-	userID, ok := userIDLocal.(uuid.UUID)
-	if !ok {
-		log.Printf("AdminRequired: userID yerel değişkeni UUID tipinde değil: %v\n", userIDLocal)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Sunucu hatası, userID geçersiz."})
+		c.Locals("userID", userID)
+		c.Locals(permissionsKey, role.Permissions)
+		return c.Next()
 	}
+}
 
-	// Veritabanından kullanıcının rol adını (r.name) sorgula.
-	var roleName string
-	query := `
-        SELECT r.name 
-        FROM t_users u
-        JOIN t_roles r ON u.role_id = r.id
-        WHERE u.id = $1
-    `
-	err = DB.QueryRow(context.Background(), query, userID).Scan(&roleName)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			// Kullanıcı bulunamazsa yetki reddedilir.
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Bu işlem için yetkiniz yok."})
+// RequirePermission, kullanıcının rolünün permission iznini taşımasını şart koşar.
+// AdminRequired'dan sonra kullanılmalıdır.
+func RequirePermission(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		permissions, ok := c.Locals(permissionsKey).([]string)
+		if !ok {
+			log.Println("RequirePermission, AdminRequired olmadan kullanıldı. Bu bir konfigürasyon hatasıdır.")
+			return apierr.New(apierr.CodeInternal, "auth.permission_check_failed")
+		}
+		if !slices.Contains(permissions, permission) {
+			return apierr.New(apierr.CodePermissionDenied, "auth.permission_denied", permission)
 		}
-		log.Println("Admin yetkisi sorgulama hatası:", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Yetki kontrolü sırasında bir hata oluştu."})
+		return c.Next()
 	}
+}
 
-	// Rol adının "admin" olup olmadığını kontrol et.
-	if roleName != "admin" {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Bu işlem için admin yetkisi gereklidir."})
+// checkTwoFactor, yetkili hesabın iki adımlı doğrulamayı etkinleştirmiş olmasını şart koşar.
+func checkTwoFactor(c *fiber.Ctx, cfg AuthConfig, userID uuid.UUID) error {
+	if cfg.TwoFactor == nil {
+		log.Println("İki adımlı doğrulama repository'si atanamadı. Bu bir konfigürasyon hatasıdır.")
+		return apierr.New(apierr.CodeInternal, "auth.two_factor_missing")
 	}
 
-	// Eğer kullanıcı admin ise, bir sonraki işleyiciye geç.
-	return c.Next()
+	twoFactor, err := cfg.TwoFactor.Get(c.UserContext(), userID)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !twoFactor.Enabled()) {
+		return apierr.New(apierr.CodeTwoFactorRequired, "auth.two_factor_required")
+	}
+	if err != nil {
+		log.Println("İki adımlı doğrulama sorgulama hatası:", err)
+		return apierr.New(apierr.CodeInternal, "auth.permission_check_failed")
+	}
+	return nil
 }
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// API anahtarıyla doğrulanan isteklerin c.Locals anahtarları.
+const (
+	apiKeyUserKey   = "apiKeyUserID"
+	apiKeyScopesKey = "apiKeyScopes"
+)
+
+// lastUsedResolution, bir anahtarın son kullanılma zamanının en sık hangi aralıkla
+// yazılacağıdır; her istekte veritabanına yazmamak için kullanılır.
+const lastUsedResolution = time.Minute
+
+// RequireScope, API anahtarıyla gelen isteklerin verilen kapsamı taşımasını ister ve
+// kapsam yeterliyse kullanıcıyı handler'lara açar. Session ve erişim token'ıyla gelen
+// istekler kapsamla sınırlanmaz. AuthRequired'dan sonra kullanılmalıdır.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if AuthMethod(c) != AuthAPIKey {
+			return c.Next()
+		}
+		if err := checkScope(c, scope); err != nil {
+			return err
+		}
+		c.Locals("userID", c.Locals(apiKeyUserKey))
+		return c.Next()
+	}
+}
+
+// checkScope, istek bir API anahtarıyla geldiyse anahtarın scope kapsamını taşıdığını doğrular.
+func checkScope(c *fiber.Ctx, scope string) error {
+	if AuthMethod(c) != AuthAPIKey {
+		return nil
+	}
+	scopes, _ := c.Locals(apiKeyScopesKey).([]string)
+	if !auth.HasScope(scopes, scope) {
+		return apierr.New(apierr.CodeInsufficientScope, "auth.insufficient_scope", scope)
+	}
+	return nil
+}
+
+func authenticateAPIKey(c *fiber.Ctx, keys repository.APIKeyRepository, token string) (uuid.UUID, error) {
+	if keys == nil {
+		log.Println("API anahtarı repository'si atanamadı. Bu bir konfigürasyon hatasıdır.")
+		return uuid.Nil, apierr.New(apierr.CodeInternal, "auth.api_keys_missing")
+	}
+
+	key, err := keys.GetByHash(c.UserContext(), auth.HashToken(token))
+	if errors.Is(err, repository.ErrNotFound) {
+		return uuid.Nil, apierr.New(apierr.CodeAPIKeyInvalid, "auth.api_key_invalid")
+	}
+	if err != nil {
+		log.Println("API anahtarı sorgu hatası:", err)
+		return uuid.Nil, apierr.New(apierr.CodeInternal, "auth.permission_check_failed")
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		// Son kullanım zamanı bilgi amaçlıdır; yazılamaması isteği engellemez.
+		if err := keys.TouchLastUsed(c.UserContext(), key.ID, now); err != nil {
+			log.Println("API anahtarı son kullanım zamanı güncellenemedi:", err)
+		}
+	}
+
+	c.Locals(authMethodKey, AuthAPIKey)
+	c.Locals(apiKeyScopesKey, key.Scopes)
+	return key.UserID, nil
+}
@@ -1,42 +1,183 @@
 package middleware
 
 import (
+	"errors"
 	"log"
+	"strings"
+	"time"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/i18n"
+	"spoti/rbac"
+	"spoti/repository"
+	"spoti/sessions"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/session"
 	"github.com/google/uuid"
 )
 
-// Store değişkeni, main.go dosyasında atanacaktır.
-var Store *session.Store
+// Kimlik doğrulama yöntemleri; handler'lar isteğin nasıl doğrulandığını AuthMethod ile öğrenir.
+const (
+	AuthSession = "session"
+	AuthBearer  = "bearer"
+	AuthAPIKey  = "apikey"
+
+	authMethodKey = "authMethod"
+)
+
+// AuthConfig, kimlik doğrulama middleware'larının bağımlılıklarını toplar.
+type AuthConfig struct {
+	Store   *session.Store
+	Tokens  *auth.TokenManager
+	APIKeys repository.APIKeyRepository
+	// TwoFactor, AdminRequired'ın yetkili hesaplarda iki adımlı doğrulamayı zorunlu kılması için kullanılır.
+	TwoFactor repository.TwoFactorRepository
+	// Roles, AdminRequired'ın kullanıcıların rollerini ve izinlerini çözümlemesi için kullanılır.
+	Permissions *rbac.Resolver
+	// Sessions, session'ların son görülme zamanlarını kullanıcı başına dizinde günceller.
+	Sessions *sessions.Index
+}
 
 // AuthRequired middleware'ı, sadece oturum açmış kullanıcıların erişimine izin verir.
-func AuthRequired(c *fiber.Ctx) error {
-	if Store == nil {
+// İstek session çereziyle, Authorization: Bearer başlığındaki erişim token'ıyla veya
+// API anahtarıyla doğrulanabilir. API anahtarları yalnızca RequireScope kullanan
+// rotalarda kullanıcıya dönüşür; diğer rotalar bu istekleri oturumsuz görür.
+func AuthRequired(cfg AuthConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := authenticate(c, cfg)
+		if err != nil {
+			return err
+		}
+
+		if AuthMethod(c) == AuthAPIKey {
+			c.Locals(apiKeyUserKey, userID)
+			return c.Next()
+		}
+
+		// userID'yi bir sonraki handler'a iletmek için Local değişkenine kaydet
+		// Bu, değeri uuid.UUID tipinde tutar.
+		c.Locals("userID", userID)
+
+		// İstek zincirinde bir sonraki middleware veya handlera geç
+		return c.Next()
+	}
+}
+
+// AuthMethod, isteğin hangi yöntemle (AuthSession, AuthBearer, AuthAPIKey) doğrulandığını döndürür.
+func AuthMethod(c *fiber.Ctx) string {
+	method, _ := c.Locals(authMethodKey).(string)
+	return method
+}
+
+// authenticate, isteği doğrular ve kullanıcı ID'sini döndürür. Bearer başlığı varsa
+// session'a hiç bakılmaz; böylece çerezsiz istemciler için session oluşturulmaz.
+// Kullanıcının dil tercihi de bu adımda isteğe uygulanır.
+func authenticate(c *fiber.Ctx, cfg AuthConfig) (uuid.UUID, error) {
+	if token, ok := bearerToken(c); ok {
+		if auth.IsAPIKey(token) {
+			return authenticateAPIKey(c, cfg.APIKeys, token)
+		}
+		return authenticateBearer(c, cfg, token)
+	}
+	return authenticateSession(c, cfg)
+}
+
+func authenticateSession(c *fiber.Ctx, cfg AuthConfig) (uuid.UUID, error) {
+	store := cfg.Store
+	if store == nil {
 		log.Println("Session store atanamadı. Bu bir konfigürasyon hatasıdır.")
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Sunucu hatası, session store mevcut değil."})
+		return uuid.Nil, apierr.New(apierr.CodeInternal, "session.store_missing")
 	}
 
 	// Oturumu al
-	sess, err := Store.Get(c)
+	sess, err := store.Get(c)
 	if err != nil {
 		log.Printf("Session alınamadı: %v\n", err)
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Oturum bulunamadı veya geçersiz."})
+		return uuid.Nil, apierr.New(apierr.CodeSessionInvalid, "session.invalid")
 	}
 
 	// Session'da userID olup olmadığını kontrol et
-	userID := sess.Get("userID")
-	if userID == nil {
+	userID, ok := sess.Get("userID").(uuid.UUID)
+	if !ok {
 		log.Println("userID bulunamadı, oturum yetkisiz.")
+		authErr := unauthenticated(c, store, sess)
+		_ = sess.Destroy()
+		return uuid.Nil, authErr
+	}
+
+	// Şifre sıfırlama gibi işlemlerden önce açılmış session'lar geçersizdir.
+	authAt, _ := sess.Get("authAt").(time.Time)
+	if err := checkRevoked(cfg, userID, authAt, apierr.New(apierr.CodeSessionExpired, "session.expired")); err != nil {
 		_ = sess.Destroy()
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Lütfen giriş yapın."})
+		return uuid.Nil, err
 	}
 
-	// userID'yi bir sonraki handler'a iletmek için Local değişkenine kaydet
-	// Bu, değeri uuid.UUID tipinde tutar.
-	c.Locals("userID", userID.(uuid.UUID))
+	if cfg.Sessions != nil {
+		if err := cfg.Sessions.Touch(userID, sess.ID(), c.IP(), c.Get(fiber.HeaderUserAgent), time.Now()); err != nil {
+			log.Println("Session dizini güncellenemedi:", err)
+		}
+	}
+
+	c.Locals(authMethodKey, AuthSession)
+	applyUserLanguage(c, sess)
+	return userID, nil
+}
+
+func authenticateBearer(c *fiber.Ctx, cfg AuthConfig, token string) (uuid.UUID, error) {
+	tokens := cfg.Tokens
+	if tokens == nil {
+		log.Println("Token yöneticisi atanamadı. Bu bir konfigürasyon hatasıdır.")
+		return uuid.Nil, apierr.New(apierr.CodeInternal, "auth.tokens_missing")
+	}
 
-	// İstek zincirinde bir sonraki middleware veya handlera geç
-	return c.Next()
+	claims, err := tokens.ParseAccess(token)
+	if err != nil {
+		// RFC 6750: istemci token'ı yenilemesi gerektiğini bu başlıktan da anlayabilir.
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		if errors.Is(err, auth.ErrTokenExpired) {
+			return uuid.Nil, apierr.New(apierr.CodeTokenExpired, "auth.token_expired")
+		}
+		return uuid.Nil, apierr.New(apierr.CodeTokenInvalid, "auth.token_invalid")
+	}
+	userID, _ := claims.UserID()
+	if err := checkRevoked(cfg, userID, claims.IssuedAt.Time, apierr.New(apierr.CodeTokenExpired, "auth.token_expired")); err != nil {
+		return uuid.Nil, err
+	}
+
+	c.Locals(authMethodKey, AuthBearer)
+	if lang, ok := i18n.Parse(claims.Lang); ok {
+		i18n.SetLang(c, lang)
+	}
+	return userID, nil
+}
+
+// bearerToken, Authorization başlığındaki Bearer token'ı döndürür.
+func bearerToken(c *fiber.Ctx) (string, bool) {
+	scheme, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// unauthenticated, oturumda kullanıcı bulunmadığında dönecek hatayı seçer.
+// İstemci bir session çerezi gönderdiği hâlde store'da karşılığı yoksa oturumun
+// süresi dolmuştur; istemci bu durumu SESSION_EXPIRED koduyla ayırt edebilir.
+func unauthenticated(c *fiber.Ctx, store *session.Store, sess *session.Session) error {
+	source, name, _ := strings.Cut(store.KeyLookup, ":")
+	if sess.Fresh() && source == "cookie" && c.Cookies(name) != "" {
+		return apierr.New(apierr.CodeSessionExpired, "session.expired")
+	}
+	return apierr.New(apierr.CodeUnauthenticated, "auth.login_required")
+}
+
+// applyUserLanguage, kullanıcının oturumda saklanan dil tercihi varsa isteğin
+// dilini Accept-Language yerine bu tercihe göre belirler.
+func applyUserLanguage(c *fiber.Ctx, sess *session.Session) {
+	tag, _ := sess.Get("lang").(string)
+	if lang, ok := i18n.Parse(tag); ok {
+		i18n.SetLang(c, lang)
+	}
 }
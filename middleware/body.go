@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+
+	"spoti/apierr"
+	"spoti/validate"
+)
+
+// c.Locals anahtarları; doğrulanmış istek gövdesi ve sorgu parametreleri burada tutulur.
+const (
+	bodyKey  = "validatedBody"
+	queryKey = "validatedQuery"
+)
+
+// defaulter, ayrıştırmadan önce varsayılan değerlerini atayan DTO'lar tarafından gerçeklenir.
+type defaulter interface {
+	Defaults()
+}
+
+// ValidateBody, istek gövdesini T tipine ayrıştırır ve `validate` etiketlerine göre doğrular.
+// Gövde geçersizse isteği alan bazlı hatalarla reddeder; geçerliyse handler gövdeye
+// Body[T] ile erişir.
+func ValidateBody[T any]() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		body := new(T)
+		if d, ok := any(body).(defaulter); ok {
+			d.Defaults()
+		}
+		if err := c.BodyParser(body); err != nil {
+			return parseError(err, apierr.CodeInvalidBody, "request.invalid_body")
+		}
+		if errs := validate.Struct(body); len(errs) > 0 {
+			return apierr.Validation(errs...)
+		}
+		c.Locals(bodyKey, body)
+		return c.Next()
+	}
+}
+
+// ValidateQuery, sorgu parametrelerini `query` etiketleriyle T tipine ayrıştırır ve doğrular.
+// Handler parametrelere Query[T] ile erişir.
+func ValidateQuery[T any]() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		query := new(T)
+		if d, ok := any(query).(defaulter); ok {
+			d.Defaults()
+		}
+		if err := c.QueryParser(query); err != nil {
+			return parseError(err, apierr.CodeBadRequest, "request.invalid_query")
+		}
+		if errs := validate.Struct(query); len(errs) > 0 {
+			return apierr.Validation(errs...)
+		}
+		c.Locals(queryKey, query)
+		return c.Next()
+	}
+}
+
+// Body, ValidateBody[T] tarafından doğrulanan istek gövdesini döndürür.
+func Body[T any](c *fiber.Ctx) *T {
+	body, ok := c.Locals(bodyKey).(*T)
+	if !ok {
+		panic(fmt.Sprintf("middleware: %s rotasında ValidateBody[%T] kullanılmamış", c.Path(), *new(T)))
+	}
+	return body
+}
+
+// Query, ValidateQuery[T] tarafından doğrulanan sorgu parametrelerini döndürür.
+func Query[T any](c *fiber.Ctx) *T {
+	query, ok := c.Locals(queryKey).(*T)
+	if !ok {
+		panic(fmt.Sprintf("middleware: %s rotasında ValidateQuery[%T] kullanılmamış", c.Path(), *new(T)))
+	}
+	return query
+}
+
+// parseError, ayrıştırma hatasını mümkünse hangi alanın hatalı türde olduğunu
+// belirten bir doğrulama hatasına, değilse genel bir hataya çevirir.
+func parseError(err error, code apierr.Code, key string) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return apierr.Validation(typeFieldError(typeErr.Field))
+	}
+
+	var multi fiber.MultiError
+	if errors.As(err, &multi) {
+		fields := make([]string, 0, len(multi))
+		for field := range multi {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		details := make([]apierr.FieldError, len(fields))
+		for i, field := range fields {
+			details[i] = typeFieldError(field)
+		}
+		return apierr.Validation(details...)
+	}
+
+	return apierr.New(code, key)
+}
+
+func typeFieldError(field string) apierr.FieldError {
+	return apierr.FieldError{Field: field, Code: "type", Key: "validation.type"}
+}
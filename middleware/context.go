@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// disconnectPollInterval, istek sürerken istemci bağlantısının ne sıklıkla yoklandığıdır.
+const disconnectPollInterval = 200 * time.Millisecond
+
+// RequestContext, her istek için base'den türetilen ve timeout ile sınırlandırılan bir
+// context oluşturur ve c.UserContext() üzerinden handler'lara aktarır. İstemci bağlantıyı
+// koparırsa context iptal edilir.
+//
+// Context bilerek Fiber'in istek bağlamından türetilmez: fasthttp, kapanma başlar
+// başlamaz tüm isteklerin bağlamını iptal eder ve bu, boşaltılması beklenen
+// işlemleri (ör. PurchasePremium transaction'ı) yarıda keser. base yalnızca
+// kapanma süresi aşıldığında iptal edilmelidir. fasthttp, handler çalışırken
+// istemcinin bağlantıyı kopardığını da bildirmez; bu yüzden bağlantı istek süresince
+// watchDisconnect ile yoklanır. Yoklanamayan bağlantılarda terk edilmiş isteklerin
+// veritabanında harcayabileceği süreyi yalnızca timeout sınırlar.
+func RequestContext(base context.Context, timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(base, timeout)
+		defer cancel()
+
+		stop := watchDisconnect(c.Context().Conn(), cancel)
+		defer stop()
+
+		c.SetUserContext(ctx)
+		return c.Next()
+	}
+}
+
+// watchDisconnect, bağlantıyı disconnectPollInterval aralıklarla yoklar ve istemci bağlantıyı
+// kapattığında cancel'ı çağırır. Dönen fonksiyon yoklamayı durdurur ve bitmesini bekler;
+// böylece fasthttp bağlantıyı yeniden kullanmadan önce yoklama sona ermiş olur.
+//
+// net/http'de olduğu gibi, isteğini gönderip yazma yönünü kapatan (half-close) istemciler de
+// kopmuş sayılır.
+func watchDisconnect(conn net.Conn, cancel context.CancelFunc) func() {
+	// TLS bağlantılarında alttaki TCP bağlantısı yoklanır.
+	if tc, ok := conn.(interface{ NetConn() net.Conn }); ok {
+		conn = tc.NetConn()
+	}
+	sc, ok := conn.(syscall.Conn)
+	if !ok || !canProbe {
+		return func() {}
+	}
+	raw, err := sc.SyscallConn()
+	if err != nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		ticker := time.NewTicker(disconnectPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if peerClosed(raw) {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+	}
+}
@@ -0,0 +1,89 @@
+package middleware_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"spoti/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// serve, uygulamayı gerçek bir TCP dinleyicisinde çalıştırır ve adresini döndürür.
+func serve(t *testing.T, app *fiber.App) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = app.Listener(ln) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+	return ln.Addr().String()
+}
+
+func TestRequestContextCancelledOnDisconnect(t *testing.T) {
+	started := make(chan struct{})
+	result := make(chan error, 1)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(middleware.RequestContext(context.Background(), time.Minute))
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		close(started)
+		select {
+		case <-c.UserContext().Done():
+			result <- c.UserContext().Err()
+		case <-time.After(5 * time.Second):
+			result <- nil
+		}
+		return nil
+	})
+	addr := serve(t, app)
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(conn, "GET /slow HTTP/1.1\r\nHost: spoti.test\r\n\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	conn.Close()
+
+	if err := <-result; err != context.Canceled {
+		t.Fatalf("istemci koptuktan sonra context hatası = %v, beklenen %v", err, context.Canceled)
+	}
+}
+
+func TestRequestContextSurvivesCompletedRequest(t *testing.T) {
+	result := make(chan error, 1)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(middleware.RequestContext(context.Background(), time.Minute))
+	app.Get("/wait", func(c *fiber.Ctx) error {
+		// Bağlı kalan istemcinin isteği yoklama aralığından uzun sürse de iptal edilmez.
+		select {
+		case <-c.UserContext().Done():
+			result <- c.UserContext().Err()
+		case <-time.After(time.Second):
+			result <- nil
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	addr := serve(t, app)
+
+	resp, err := http.Get("http://" + addr + "/wait")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if err := <-result; err != nil {
+		t.Fatalf("bağlı istemcinin context'i iptal edildi: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusNoContent {
+		t.Fatalf("durum = %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package middleware
+
+import "syscall"
+
+// canProbe, bu platformda bağlantının yoklanabildiğini gösterir.
+const canProbe = false
+
+// peerClosed, bu platformlarda bağlantıyı yoklayamaz; istekleri yalnızca timeout sınırlar.
+func peerClosed(syscall.RawConn) bool {
+	return false
+}
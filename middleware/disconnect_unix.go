@@ -0,0 +1,22 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package middleware
+
+import "syscall"
+
+// canProbe, bu platformda bağlantının yoklanabildiğini gösterir.
+const canProbe = true
+
+// peerClosed, soketten veri tüketmeden (MSG_PEEK) ve beklemeden (MSG_DONTWAIT) okur. Karşı
+// taraf bağlantıyı kapattıysa okuma sıfır bayt döndürür; bekleyen veri yoksa EAGAIN döner.
+// Aynı bağlantıdan gelen bir sonraki istek (pipelining) okunmuş sayılmadığından fasthttp'nin
+// okuyacağı veri bozulmaz.
+func peerClosed(raw syscall.RawConn) bool {
+	var buf [1]byte
+	closed := false
+	err := raw.Control(func(fd uintptr) {
+		n, _, err := syscall.Recvfrom(int(fd), buf[:], syscall.MSG_PEEK|syscall.MSG_DONTWAIT)
+		closed = (n == 0 && err == nil) || err == syscall.ECONNRESET
+	})
+	return err != nil || closed
+}
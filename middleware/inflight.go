@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// InFlight, işlenmekte olan istekleri sayar. Sunucu kapanırken kaç isteğin
+// tamamlanmasının beklendiğini raporlamak için kullanılır.
+type InFlight struct {
+	active   atomic.Int64
+	draining atomic.Bool
+	drained  atomic.Int64
+}
+
+// Handler, isteğin başında sayacı artıran, bitince azaltan middleware'ı döndürür.
+func (f *InFlight) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		f.active.Add(1)
+		defer func() {
+			f.active.Add(-1)
+			if f.draining.Load() {
+				f.drained.Add(1)
+			}
+		}()
+		return c.Next()
+	}
+}
+
+// StartDrain, kapanmanın başladığını işaretler ve o an devam eden istek sayısını döndürür.
+// Bu noktadan sonra biten istekler Drained ile okunabilir.
+func (f *InFlight) StartDrain() int64 {
+	f.draining.Store(true)
+	return f.active.Load()
+}
+
+// Active, şu an işlenmekte olan istek sayısını döndürür.
+func (f *InFlight) Active() int64 {
+	return f.active.Load()
+}
+
+// Drained, StartDrain çağrıldıktan sonra tamamlanan istek sayısını döndürür.
+func (f *InFlight) Drained() int64 {
+	return f.drained.Load()
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"log"
+	"time"
+
+	"spoti/apierr"
+
+	"github.com/google/uuid"
+)
+
+// sessionsRevokedKey, kullanıcının oturumlarının en son ne zaman toplu olarak
+// geçersiz kılındığını tutan storage anahtarıdır.
+func sessionsRevokedKey(userID uuid.UUID) string {
+	return "sessions_revoked_at:" + userID.String()
+}
+
+// RevokeSessions, kullanıcının bu ana kadar açtığı tüm session'ları ve verilmiş tüm
+// erişim token'larını geçersiz kılar. Yenileme token'ları ayrıca repository üzerinden
+// iptal edilmelidir. İşaret, en uzun ömürlü session veya token sona erene kadar saklanır.
+func RevokeSessions(cfg AuthConfig, userID uuid.UUID) error {
+	ttl := cfg.Store.Expiration
+	if cfg.Tokens != nil && cfg.Tokens.AccessTTL() > ttl {
+		ttl = cfg.Tokens.AccessTTL()
+	}
+	now := time.Now().Format(time.RFC3339Nano)
+	return cfg.Store.Storage.Set(sessionsRevokedKey(userID), []byte(now), ttl)
+}
+
+// checkRevoked, issuedAt anında açılmış bir session veya token'ın RevokeSessions ile
+// geçersiz kılınıp kılınmadığını kontrol eder ve kılındıysa expired hatasını döndürür.
+// JWT "iat" alanı saniye hassasiyetinde olduğundan karşılaştırma saniyeye yuvarlanarak yapılır.
+func checkRevoked(cfg AuthConfig, userID uuid.UUID, issuedAt time.Time, expired *apierr.Error) error {
+	raw, err := cfg.Store.Storage.Get(sessionsRevokedKey(userID))
+	if err != nil {
+		log.Println("Oturum iptal kaydı okunamadı:", err)
+		return apierr.New(apierr.CodeInternal, "session.get_failed")
+	}
+	if raw == nil {
+		return nil
+	}
+	revokedAt, err := time.Parse(time.RFC3339Nano, string(raw))
+	if err != nil {
+		log.Println("Oturum iptal kaydı bozuk:", err)
+		return nil
+	}
+	if issuedAt.Truncate(time.Second).Before(revokedAt.Truncate(time.Second)) {
+		return expired
+	}
+	return nil
+}
@@ -1,23 +1,17 @@
 package middleware
 
 import (
-	"strconv"
-
-	"github.com/gofiber/fiber/v2"
+	"spoti/dto"
 )
 
-// ValidatePageQuery, isteğin "page" sorgu parametresini kontrol eder.
-// Eğer "page" parametresi geçerli değilse, 400 Bad Request hatası döndürür.
-func ValidatePageQuery(c *fiber.Ctx) error {
-	pageStr := c.Query("page", "1")
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		// Geçersiz bir değerse 400 hatası döndür
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error":   "Invalid page number",
-			"message": "Sayfa numarası pozitif bir tam sayı olmalıdır.",
-		})
-	}
-	// Eğer geçerliyse, sonraki middleware veya handler'a geç
-	return c.Next()
-}
+// ValidateListQuery, sayfalanan liste uçlarının "cursor", "page_size" ve "include_total"
+// sorgu parametrelerini doğrular; handler değerleri Query[dto.ListQuery] ile okur.
+var ValidateListQuery = ValidateQuery[dto.ListQuery]()
+
+// ValidateSongListQuery, şarkı kataloğunun sayfa belirteci, sayfa boyutu, filtre ve sıralama
+// parametrelerini doğrular; handler değerleri Query[dto.SongListQuery] ile okur.
+var ValidateSongListQuery = ValidateQuery[dto.SongListQuery]()
+
+// ValidateSuggestQuery, otomatik tamamlama ucunun "q" ve "limit" sorgu parametrelerini doğrular;
+// handler değerleri Query[dto.SuggestQuery] ile okur.
+var ValidateSuggestQuery = ValidateQuery[dto.SuggestQuery]()
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+
+	"spoti/apierr"
+	"spoti/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// VerifiedRequired, e-posta adresini doğrulamamış kullanıcıların isteğini reddeder.
+// AuthRequired'dan (API anahtarlı rotalarda RequireScope'tan) sonra kullanılmalıdır.
+// Doğrulama durumu her istekte veritabanından okunur; böylece bağlantıya tıklayan
+// kullanıcının yeniden giriş yapması gerekmez.
+func VerifiedRequired(users repository.UserRepository) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("userID").(uuid.UUID)
+		if !ok {
+			return apierr.New(apierr.CodeUnauthenticated, "auth.login_required")
+		}
+
+		user, err := users.GetByID(c.UserContext(), userID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return apierr.New(apierr.CodeUnauthenticated, "auth.login_required")
+		}
+		if err != nil {
+			log.Println("E-posta doğrulama durumu sorgulama hatası:", err)
+			return apierr.New(apierr.CodeInternal, "auth.permission_check_failed")
+		}
+		if !user.EmailVerified() {
+			return apierr.New(apierr.CodeEmailNotVerified, "verification.required")
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	"spoti/config"
+	"spoti/database"
+	"spoti/migrations"
+)
+
+// runMigrate, "spoti migrate" alt komutlarını çalıştırır.
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("migrate alt komutu eksik: up, down, status, redo, version veya create kullanın")
+	}
+	command, args := args[0], args[1:]
+
+	if command == "create" {
+		fs := flag.NewFlagSet("migrate create", flag.ExitOnError)
+		dir := fs.String("dir", "migrations", "Migration dosyasının oluşturulacağı dizin")
+		fs.Parse(args)
+		if fs.NArg() != 1 {
+			return errors.New("kullanım: spoti migrate create [-dir dizin] <ad>")
+		}
+		return migrations.Create(*dir, fs.Arg(0))
+	}
+
+	ctx := context.Background()
+	db, err := database.OpenSQL(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := migrations.Run(ctx, db, command); err != nil {
+		return fmt.Errorf("migrate %s başarısız: %w", command, err)
+	}
+	return nil
+}
+
+// migrateUp, sunucu açılışında bekleyen tüm migration'ları uygular.
+func migrateUp(ctx context.Context, cfg *config.Config) error {
+	db, err := database.OpenSQL(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	log.Println("Bekleyen migration'lar uygulanıyor...")
+	if err := migrations.Up(ctx, db); err != nil {
+		return fmt.Errorf("otomatik migration başarısız: %w", err)
+	}
+	return nil
+}
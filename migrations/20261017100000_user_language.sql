@@ -0,0 +1,6 @@
+-- +goose Up
+-- Kullanıcının yanıt mesajları için tercih ettiği dil. NULL ise Accept-Language başlığına bakılır.
+ALTER TABLE t_users ADD COLUMN language VARCHAR(5) CHECK (language IN ('tr', 'en'));
+
+-- +goose Down
+ALTER TABLE t_users DROP COLUMN language;
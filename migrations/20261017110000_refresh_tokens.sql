@@ -0,0 +1,17 @@
+-- +goose Up
+-- Bearer token girişlerinin yenileme token'ları. Token'ın kendisi değil SHA-256 özeti saklanır.
+CREATE TABLE t_refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES t_users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_refresh_tokens_user_id ON t_refresh_tokens (user_id);
+CREATE INDEX idx_refresh_tokens_family_id ON t_refresh_tokens (family_id);
+
+-- +goose Down
+DROP TABLE t_refresh_tokens;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Otomasyon istemcileri için kullanıcıya ait, kapsamlı API anahtarları.
+-- Anahtarın kendisi değil SHA-256 özeti saklanır; prefix yalnızca listelemede gösterilir.
+CREATE TABLE t_api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES t_users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (user_id, name)
+);
+
+-- +goose Down
+DROP TABLE t_api_keys;
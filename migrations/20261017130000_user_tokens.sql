@@ -0,0 +1,17 @@
+-- +goose Up
+-- E-postayla gönderilen tek kullanımlık token'lar (örneğin şifre sıfırlama).
+-- Token'ın kendisi değil SHA-256 özeti saklanır.
+CREATE TABLE t_user_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES t_users(id) ON DELETE CASCADE,
+    purpose VARCHAR(32) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_user_tokens_user_purpose ON t_user_tokens (user_id, purpose);
+
+-- +goose Down
+DROP TABLE t_user_tokens;
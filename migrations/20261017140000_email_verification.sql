@@ -0,0 +1,9 @@
+-- +goose Up
+-- E-posta adresinin doğrulandığı an. NULL ise hesap doğrulanmamıştır.
+ALTER TABLE t_users ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Bu migration'dan önce açılmış hesaplar doğrulanmış kabul edilir.
+UPDATE t_users SET email_verified_at = COALESCE(created_at, CURRENT_TIMESTAMP);
+
+-- +goose Down
+ALTER TABLE t_users DROP COLUMN email_verified_at;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- Kullanıcıların TOTP iki adımlı doğrulama ayarları. enabled_at NULL ise kurulum
+-- başlatılmış ama ilk kodla doğrulanmamıştır.
+CREATE TABLE t_user_totp (
+    user_id UUID PRIMARY KEY REFERENCES t_users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    enabled_at TIMESTAMP WITH TIME ZONE,
+    last_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tek kullanımlık kurtarma kodları; yalnızca SHA-256 özetleri saklanır.
+CREATE TABLE t_user_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES t_users(id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (user_id, code_hash)
+);
+
+-- +goose Down
+DROP TABLE t_user_recovery_codes;
+DROP TABLE t_user_totp;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- E-posta değişikliği token'ları onaylanacak yeni adresi taşır; diğer amaçlarda NULL'dır.
+ALTER TABLE t_user_tokens ADD COLUMN new_email VARCHAR(255);
+
+-- +goose Down
+ALTER TABLE t_user_tokens DROP COLUMN new_email;
@@ -0,0 +1,40 @@
+-- +goose Up
+-- Yerleşik roller migration'la oluşturulur; izinleri API üzerinden değiştirilemez.
+ALTER TABLE t_roles ADD COLUMN built_in BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- t_permissions, admin uçlarının istediği izinlerdir (ör. songs:write).
+CREATE TABLE t_permissions (
+    name VARCHAR(50) PRIMARY KEY
+);
+
+-- t_role_permissions, hangi rolün hangi izinlere sahip olduğunu tutar.
+CREATE TABLE t_role_permissions (
+    role_id UUID NOT NULL REFERENCES t_roles(id) ON DELETE CASCADE,
+    permission VARCHAR(50) NOT NULL REFERENCES t_permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission)
+);
+
+INSERT INTO t_permissions (name) VALUES
+    ('songs:write'), ('users:read'), ('users:write'), ('coupons:write'), ('roles:manage'), ('system:read');
+
+-- Şarkıları veya kullanıcıları yöneten ama ikisini birden yönetemeyen yerleşik roller.
+INSERT INTO t_roles (name) VALUES ('content-editor'), ('support') ON CONFLICT (name) DO NOTHING;
+UPDATE t_roles SET built_in = TRUE WHERE name IN ('admin', 'user', 'content-editor', 'support');
+
+-- admin tüm izinlere sahiptir; user hiçbir izne sahip değildir.
+INSERT INTO t_role_permissions (role_id, permission)
+SELECT r.id, p.name FROM t_roles r CROSS JOIN t_permissions p WHERE r.name = 'admin';
+INSERT INTO t_role_permissions (role_id, permission)
+SELECT id, 'songs:write' FROM t_roles WHERE name = 'content-editor';
+INSERT INTO t_role_permissions (role_id, permission)
+SELECT r.id, p.name FROM t_roles r CROSS JOIN t_permissions p
+WHERE r.name = 'support' AND p.name IN ('users:read', 'users:write');
+
+-- +goose Down
+-- Yerleşik ve sonradan eklenen rollerdeki kullanıcılar eski 'user' rolüne döner.
+UPDATE t_users SET role_id = (SELECT id FROM t_roles WHERE name = 'user')
+WHERE role_id IN (SELECT id FROM t_roles WHERE name NOT IN ('admin', 'user'));
+DELETE FROM t_roles WHERE name NOT IN ('admin', 'user');
+DROP TABLE t_role_permissions;
+DROP TABLE t_permissions;
+ALTER TABLE t_roles DROP COLUMN built_in;
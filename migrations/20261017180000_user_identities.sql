@@ -0,0 +1,17 @@
+-- +goose Up
+-- Harici OIDC sağlayıcılarındaki hesaplarla kullanıcılar arasındaki bağlantılar. Bir sağlayıcı
+-- hesabı (provider + subject) yalnızca bir kullanıcıya bağlanabilir.
+CREATE TABLE t_user_identities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES t_users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON t_user_identities (user_id);
+
+-- +goose Down
+DROP TABLE t_user_identities;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Şarkı araması başlık, sanatçı ve albümde tam metin olarak yapılır. spoti_search yapılandırması
+-- kelimeleri önce unaccent ile aksanlardan arındırır (ş→s, ğ→g, ı/İ→i), sonra küçük harfe çevirir;
+-- böylece "sezen aksu" araması "Sezen Aksu"yu, "istanbul" araması "İstanbul"u bulur. Kök bulma
+-- yapılmaz; şarkı ve sanatçı adları dile bağlı olarak kısaltılmamalıdır.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+CREATE TEXT SEARCH CONFIGURATION spoti_search (COPY = simple);
+ALTER TEXT SEARCH CONFIGURATION spoti_search
+    ALTER MAPPING FOR asciiword, asciihword, hword_asciipart, word, hword, hword_part
+    WITH unaccent, simple;
+
+-- Başlık eşleşmeleri sanatçıdan, sanatçı eşleşmeleri albümden daha yüksek puan alır.
+ALTER TABLE t_songs ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('spoti_search', title), 'A') ||
+    setweight(to_tsvector('spoti_search', artist), 'B') ||
+    setweight(to_tsvector('spoti_search', COALESCE(album, '')), 'C')
+) STORED;
+
+CREATE INDEX idx_songs_search_vector ON t_songs USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX idx_songs_search_vector;
+ALTER TABLE t_songs DROP COLUMN search_vector;
+DROP TEXT SEARCH CONFIGURATION spoti_search;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Yazım hatalarına dayanıklı arama ve otomatik tamamlama için trigram benzerliği kullanılır.
+-- İndeksler alanların spoti_fold ile sadeleştirilmiş hâli üzerindedir; sorgular da aynı
+-- ifadeyi kullanmalıdır, aksi hâlde indeks kullanılmaz.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- spoti_fold, spoti_search yapılandırmasıyla aynı sadeleştirmeyi (ş→s, ı/İ→i, küçük harf)
+-- yapar. unaccent STABLE tanımlı olduğundan sözlüğü açıkça veren IMMUTABLE bir sarmalayıcı
+-- gerekir; yalnızca böyle indeks ifadelerinde kullanılabilir.
+-- +goose StatementBegin
+CREATE FUNCTION spoti_fold(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT lower(public.unaccent('public.unaccent'::regdictionary, $1)) $$;
+-- +goose StatementEnd
+
+CREATE INDEX idx_songs_title_trgm ON t_songs USING GIN (spoti_fold(title) gin_trgm_ops);
+CREATE INDEX idx_songs_artist_trgm ON t_songs USING GIN (spoti_fold(artist) gin_trgm_ops);
+CREATE INDEX idx_songs_album_trgm ON t_songs USING GIN (spoti_fold(album) gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX idx_songs_album_trgm;
+DROP INDEX idx_songs_artist_trgm;
+DROP INDEX idx_songs_title_trgm;
+DROP FUNCTION spoti_fold(text);
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Kataloğun türe, yayın yılına ve eklenme zamanına göre filtrelenip sıralanabilmesi için.
+ALTER TABLE t_songs
+    ADD COLUMN genre VARCHAR(100),
+    ADD COLUMN release_year INT CHECK (release_year BETWEEN 1800 AND 2100),
+    ADD COLUMN created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- Filtreler sanatçı, albüm ve türü spoti_fold ile karşılaştırır; sanatçı ve albüm için
+-- trigram indeksleri eşitlik aramalarında da kullanılır.
+CREATE INDEX idx_songs_genre ON t_songs (spoti_fold(genre));
+CREATE INDEX idx_songs_release_year ON t_songs (release_year);
+CREATE INDEX idx_songs_click_count ON t_songs (click_count DESC, id);
+
+-- +goose Down
+DROP INDEX idx_songs_click_count;
+DROP INDEX idx_songs_release_year;
+DROP INDEX idx_songs_genre;
+ALTER TABLE t_songs DROP COLUMN created_at, DROP COLUMN release_year, DROP COLUMN genre;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- Listeler anahtar tabanlı sayfalandığından sayfa sınırı, sıralama anahtarının tamamını
+-- aynı yönde içeren indekslerle bulunur. Azalan sıralamalar indeksi geriye doğru okur.
+DROP INDEX idx_songs_click_count;
+CREATE INDEX idx_songs_click_count ON t_songs (click_count, id);
+CREATE INDEX idx_songs_title ON t_songs (title, id);
+CREATE INDEX idx_songs_artist ON t_songs (artist, title, id);
+CREATE INDEX idx_songs_duration ON t_songs (duration, id);
+-- Yeniliğe göre varsayılan (azalan) sıralamada yılı bilinmeyen şarkılar 0 yılına konur.
+CREATE INDEX idx_songs_newest ON t_songs ((COALESCE(release_year, 0)), created_at, id);
+
+CREATE INDEX idx_playlist_user_name ON t_playlist (user_id, name, id);
+
+-- +goose Down
+DROP INDEX idx_playlist_user_name;
+DROP INDEX idx_songs_newest;
+DROP INDEX idx_songs_duration;
+DROP INDEX idx_songs_artist;
+DROP INDEX idx_songs_title;
+DROP INDEX idx_songs_click_count;
+CREATE INDEX idx_songs_click_count ON t_songs (click_count DESC, id);
@@ -0,0 +1,83 @@
+-- +goose Up
+-- Sanatçılar ve albümler ayrı tablolara taşınır. t_songs.artist ve t_songs.album metinleri
+-- arama ve öneriler için kalır; bundan sonra kayıtlı adlardan üretilirler.
+
+-- spoti_name_key, iki adın aynı sanatçıyı veya albümü gösterip göstermediğini belirler:
+-- büyük/küçük harf ve aksan farkının yanında baştaki, sondaki ve art arda gelen boşluklar
+-- da yok sayılır. Böylece "Tarkan" ve "tarkan " aynı sanatçıdır.
+-- +goose StatementBegin
+CREATE FUNCTION spoti_name_key(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT public.spoti_fold(regexp_replace(btrim($1), '\s+', ' ', 'g')) $$;
+-- +goose StatementEnd
+
+CREATE TABLE t_artists (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_artists_name_key ON t_artists (spoti_name_key(name));
+
+-- Albüm, ilk sırada adı geçen sanatçıya aittir; aynı ad farklı sanatçılarda ayrı albümdür.
+CREATE TABLE t_albums (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    artist_id UUID NOT NULL REFERENCES t_artists(id),
+    title VARCHAR(255) NOT NULL,
+    release_date DATE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_albums_artist_title_key ON t_albums (artist_id, spoti_name_key(title));
+
+-- Bir şarkıda birden fazla sanatçının adı geçebilir; position, adların sırasıdır.
+CREATE TABLE t_song_artists (
+    song_id UUID NOT NULL REFERENCES t_songs(id) ON DELETE CASCADE,
+    artist_id UUID NOT NULL REFERENCES t_artists(id),
+    position INT NOT NULL,
+    PRIMARY KEY (song_id, artist_id)
+);
+CREATE INDEX idx_song_artists_artist ON t_song_artists (artist_id);
+
+ALTER TABLE t_songs
+    ADD COLUMN album_id UUID REFERENCES t_albums(id) ON DELETE SET NULL,
+    ADD COLUMN track_number INT CHECK (track_number > 0);
+CREATE INDEX idx_songs_album_track ON t_songs (album_id, track_number);
+
+-- Mevcut şarkılar taşınır. Aynı anahtara sahip yazımlardan en çok dinlenen şarkınınki
+-- sanatçının veya albümün kayıtlı adı olur; şarkıların metinleri de bu adlarla güncellenir.
+INSERT INTO t_artists (name)
+SELECT DISTINCT ON (spoti_name_key(artist)) regexp_replace(btrim(artist), '\s+', ' ', 'g')
+FROM t_songs
+WHERE btrim(artist) <> ''
+ORDER BY spoti_name_key(artist), click_count DESC, id;
+
+INSERT INTO t_song_artists (song_id, artist_id, position)
+SELECT s.id, a.id, 0
+FROM t_songs s
+JOIN t_artists a ON spoti_name_key(a.name) = spoti_name_key(s.artist);
+
+INSERT INTO t_albums (artist_id, title)
+SELECT DISTINCT ON (sa.artist_id, spoti_name_key(s.album)) sa.artist_id, regexp_replace(btrim(s.album), '\s+', ' ', 'g')
+FROM t_songs s
+JOIN t_song_artists sa ON sa.song_id = s.id
+WHERE btrim(COALESCE(s.album, '')) <> ''
+ORDER BY sa.artist_id, spoti_name_key(s.album), s.click_count DESC, s.id;
+
+UPDATE t_songs s
+SET artist = a.name
+FROM t_song_artists sa
+JOIN t_artists a ON a.id = sa.artist_id
+WHERE sa.song_id = s.id;
+
+UPDATE t_songs s
+SET album_id = al.id, album = al.title
+FROM t_song_artists sa
+JOIN t_albums al ON al.artist_id = sa.artist_id
+WHERE sa.song_id = s.id AND spoti_name_key(al.title) = spoti_name_key(s.album);
+
+-- +goose Down
+DROP INDEX idx_songs_album_track;
+ALTER TABLE t_songs DROP COLUMN track_number, DROP COLUMN album_id;
+DROP TABLE t_song_artists;
+DROP TABLE t_albums;
+DROP TABLE t_artists;
+DROP FUNCTION spoti_name_key(text);
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Yeniliğe göre sıralamada yılı bilinmeyen şarkılar her iki yönde de sona kalır: azalan
+-- sıralamada 0, artan sıralamada 9999 yılına konurlar (postgres.missingYear). İndeks ifadesi
+-- sorgudakiyle birebir aynı olmadığında kullanılmadığından artan sıralama için ayrı bir indeks
+-- gerekir; idx_songs_newest yalnızca 0 yerine konan azalan sıralamaya uyar.
+CREATE INDEX idx_songs_oldest ON t_songs ((COALESCE(release_year, 9999)), created_at, id);
+
+-- +goose Down
+DROP INDEX idx_songs_oldest;
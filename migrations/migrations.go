@@ -0,0 +1,53 @@
+// Package migrations, veritabanı şemasını oluşturan goose migration dosyalarını
+// binary'ye gömer; böylece dağıtımlar harici bir goose kurulumuna ihtiyaç duymaz.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+// FS, bu dizindeki tüm .sql migration dosyalarını içerir.
+//
+//go:embed *.sql
+var FS embed.FS
+
+func init() {
+	goose.SetBaseFS(FS)
+	if err := goose.SetDialect("postgres"); err != nil {
+		panic(err)
+	}
+}
+
+// Up, henüz uygulanmamış tüm migration'ları uygular.
+func Up(ctx context.Context, db *sql.DB) error {
+	return goose.UpContext(ctx, db, ".")
+}
+
+// Run, gömülü migration'lar üzerinde up, down, status, redo veya version komutunu çalıştırır.
+func Run(ctx context.Context, db *sql.DB, command string) error {
+	switch command {
+	case "up":
+		return goose.UpContext(ctx, db, ".")
+	case "down":
+		return goose.DownContext(ctx, db, ".")
+	case "status":
+		return goose.StatusContext(ctx, db, ".")
+	case "redo":
+		return goose.RedoContext(ctx, db, ".")
+	case "version":
+		return goose.VersionContext(ctx, db, ".")
+	default:
+		return fmt.Errorf("bilinmeyen migrate komutu: %q", command)
+	}
+}
+
+// Create, dir dizininde zaman damgalı yeni ve boş bir SQL migration dosyası oluşturur.
+// Yeni dosya, binary yeniden derlendiğinde gömülü migration'lara dahil olur.
+func Create(dir, name string) error {
+	return goose.Create(nil, dir, name, "sql")
+}
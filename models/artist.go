@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Artist modeli, t_artists tablosunu temsil eder. Adlar büyük/küçük harf, aksan ve boşluk
+// farkı gözetilmeden karşılaştırılır; aynı ada sahip tek bir sanatçı bulunur.
+type Artist struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Album modeli, t_albums tablosunu temsil eder. Albüm tek bir sanatçıya aittir.
+type Album struct {
+	ID       uuid.UUID `json:"id"`
+	Title    string    `json:"title"`
+	ArtistID uuid.UUID `json:"artist_id"`
+	// Artist, albümün sanatçısının adıdır; yalnızca okunurken doldurulur.
+	Artist string `json:"artist"`
+	// ReleaseDate, albümün yayın tarihidir; bilinmiyorsa nil'dir.
+	ReleaseDate *time.Time `json:"release_date"`
+	// TrackCount, albüme bağlı şarkıların sayısıdır; yalnızca okunurken doldurulur.
+	TrackCount int       `json:"track_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SongArtist, bir şarkıda adı geçen sanatçıdır (t_song_artists).
+type SongArtist struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// ArtistCredit, şarkıda adı geçen sanatçıları Song.Artist alanındaki gibi birleştirir.
+func ArtistCredit(artists []SongArtist) string {
+	names := make([]string, len(artists))
+	for i, artist := range artists {
+		names[i] = artist.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Identity modeli, t_user_identities tablosunu temsil eder; bir kullanıcıyı harici bir
+// OIDC sağlayıcısındaki hesabına (sağlayıcı adı + sub) bağlar.
+type Identity struct {
+	ID       uuid.UUID `json:"id"`
+	UserID   uuid.UUID `json:"user_id"`
+	Provider string    `json:"provider"`
+	Subject  string    `json:"subject"`
+	// Email, bağlantı kurulduğunda sağlayıcının bildirdiği adrestir.
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
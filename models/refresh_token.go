@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken modeli, t_refresh_tokens tablosunu temsil eder.
+// Token'ın kendisi saklanmaz; yalnızca SHA-256 özeti tutulur.
+type RefreshToken struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	// FamilyID, aynı girişten yenilemeyle türeyen tüm token'larda ortaktır.
+	// Kullanılmış bir token tekrar gelirse tüm aile iptal edilir.
+	FamilyID  uuid.UUID  `json:"family_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active, token'ın iptal edilmemiş ve süresinin dolmamış olduğunu bildirir.
+func (t *RefreshToken) Active(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -1,13 +1,49 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Song modeli, t_songs tablosunu temsil eder.
 type Song struct {
-	ID         uuid.UUID `json:"id"`
-	Title      string    `json:"title"`
-	Artist     string    `json:"artist"`
-	Album      string    `json:"album"`
-	Duration   int       `json:"duration"`
-	ClickCount int       `json:"click_count"`
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+	// Artist, şarkıda adı geçen sanatçıların virgülle birleştirilmiş adlarıdır; Album, şarkının
+	// albümünün adıdır. İkisi de arama ve gösterim içindir; kayıtlar Artists ve AlbumID'dedir.
+	Artist     string `json:"artist"`
+	Album      string `json:"album"`
+	Duration   int    `json:"duration"`
+	ClickCount int    `json:"click_count"`
+	Genre      string `json:"genre"`
+	// ReleaseYear, şarkının yayın yılıdır; bilinmiyorsa nil'dir.
+	ReleaseYear *int      `json:"release_year"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Artists, şarkıda adı geçen sanatçılardır; ilki albümün sanatçısıdır.
+	Artists []SongArtist `json:"artists"`
+	// AlbumID ve TrackNumber, şarkının albümü ve albümdeki sırasıdır; bilinmiyorsa nil'dir.
+	AlbumID     *uuid.UUID `json:"album_id"`
+	TrackNumber *int       `json:"track_number"`
+
+	// Rank ve Highlight yalnızca arama sonuçlarında doldurulur. Rank, sonucun aramayla ne kadar
+	// ilgili olduğunu gösterir; değerler yalnızca aynı aramanın sonuçları arasında karşılaştırılabilir.
+	Rank      float64        `json:"rank,omitempty"`
+	Highlight *SongHighlight `json:"highlight,omitempty"`
+}
+
+// SongHighlight, şarkı alanlarının eşleşen kelimeleri <mark> ile işaretlenmiş, HTML olarak
+// kaçışlanmış hâlleridir.
+type SongHighlight struct {
+	Title  string `json:"title"`
+	Artist string `json:"artist"`
+	Album  string `json:"album"`
+}
+
+// SongSuggestion, otomatik tamamlamada önerilen bir başlık, sanatçı veya albüm adıdır.
+type SongSuggestion struct {
+	// Kind, önerinin hangi alandan geldiğidir: title, artist veya album.
+	Kind  string  `json:"kind"`
+	Value string  `json:"value"`
+	Score float64 `json:"score"`
 }
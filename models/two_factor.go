@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactor modeli, t_user_totp tablosunu temsil eder.
+// EnabledAt nil ise kurulum başlatılmış ama ilk kodla doğrulanmamıştır.
+type TwoFactor struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	Secret    string     `json:"-"`
+	EnabledAt *time.Time `json:"enabled_at"`
+	// LastStep, kabul edilen son TOTP zaman adımıdır; aynı kodun tekrar kullanılmasını önler.
+	LastStep  int64     `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Enabled, iki adımlı doğrulamanın etkin olup olmadığını döndürür.
+func (t *TwoFactor) Enabled() bool {
+	return t.EnabledAt != nil
+}
@@ -1,20 +1,39 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Role modeli, t_roles tablosunu temsil eder.
 type Role struct {
 	ID   uuid.UUID `json:"id"`
 	Name string    `json:"name"`
+	// BuiltIn, rolün migration'la oluşturulduğunu ve izinlerinin değiştirilemeyeceğini gösterir.
+	BuiltIn bool `json:"built_in"`
+	// Permissions, t_role_permissions tablosundaki izin adlarıdır.
+	Permissions []string `json:"permissions"`
 }
 
 // User modeli, t_users tablosunu temsil eder.
+// API yanıtlarında doğrudan kullanılmaz; istemciye dto paketindeki görünümler gönderilir.
 type User struct {
 	ID        uuid.UUID `json:"id"`
 	RoleID    uuid.UUID `json:"role_id"`
 	Username  string    `json:"username"`
 	Email     string    `json:"email"`
-	Password  string    `json:"password"`
+	Password  string    `json:"-"`
 	HesapTuru string    `json:"hesap_turu"`
 	Cash      float64   `json:"cash"`
+	// Language, kullanıcının tercih ettiği yanıt dilidir; boşsa Accept-Language kullanılır.
+	Language  string    `json:"language"`
+	CreatedAt time.Time `json:"created_at"`
+	// EmailVerifiedAt, e-posta adresinin doğrulandığı andır; nil ise hesap doğrulanmamıştır.
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+}
+
+// EmailVerified, kullanıcının e-posta adresini doğrulayıp doğrulamadığını döndürür.
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
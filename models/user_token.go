@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tek kullanımlık kullanıcı token'larının amaçları.
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposeEmailChange       = "email_change"
+)
+
+// UserToken modeli, t_user_tokens tablosunu temsil eder.
+// E-postayla gönderilen tek kullanımlık token'lardır; yalnızca SHA-256 özetleri saklanır.
+type UserToken struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Purpose   string     `json:"purpose"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	// NewEmail, e-posta değişikliği token'larında onaylanacak yeni adrestir.
+	NewEmail string `json:"new_email,omitempty"`
+}
@@ -0,0 +1,306 @@
+// Package oidc, harici bir OpenID Connect sağlayıcısıyla yetkilendirme kodu + PKCE akışını yürütür.
+//
+// Sağlayıcının uç noktaları ilk kullanımda discovery belgesinden okunur ve saklanır; sağlayıcı
+// sunucu açılırken erişilemez durumdaysa giriş yalnızca o an başarısız olur. ID token'ları
+// sağlayıcının JWKS anahtarlarıyla doğrulanır; bilinmeyen bir anahtar kimliği görüldüğünde
+// anahtarlar yeniden indirilir.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"spoti/config"
+)
+
+// httpTimeout, sağlayıcıya yapılan her isteğin üst sınırıdır.
+const httpTimeout = 10 * time.Second
+
+// ErrExchange, yetkilendirme kodunun token'a çevrilemediğini veya ID token'ın geçersiz olduğunu bildirir.
+var ErrExchange = errors.New("oidc: kod değişimi başarısız")
+
+// Claims, ID token'dan okunan kullanıcı bilgileridir.
+type Claims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	Name              string
+}
+
+// Provider, tek bir OIDC sağlayıcısıyla konuşur.
+type Provider struct {
+	name         string
+	issuer       string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	redirectURL  string
+	client       *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]any
+}
+
+// discovery, /.well-known/openid-configuration belgesinin kullanılan alanlarıdır.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// New, yapılandırmadaki sağlayıcı için bir Provider oluşturur. redirectURL, sağlayıcının
+// kullanıcıyı geri gönderdiği adrestir.
+func New(cfg config.OIDCConfig, redirectURL string) *Provider {
+	return &Provider{
+		name:         cfg.Provider,
+		issuer:       strings.TrimRight(cfg.Issuer, "/"),
+		clientID:     cfg.ClientID,
+		clientSecret: cfg.ClientSecret,
+		scopes:       cfg.Scopes,
+		redirectURL:  redirectURL,
+		client:       &http.Client{Timeout: httpTimeout},
+	}
+}
+
+// Name, sağlayıcının bağlı hesaplarda saklanan adıdır.
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// NewVerifier, PKCE için rastgele bir code_verifier üretir. Aynı fonksiyon state ve nonce
+// değerleri için de kullanılır.
+func NewVerifier() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// challenge, code_verifier'dan S256 yöntemiyle code_challenge türetir.
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthURL, kullanıcının yönlendirileceği yetkilendirme adresini oluşturur.
+func (p *Provider) AuthURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(d.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("oidc: geçersiz authorization_endpoint: %w", err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.clientID)
+	q.Set("redirect_uri", p.redirectURL)
+	q.Set("scope", strings.Join(p.scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", challenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Exchange, yetkilendirme kodunu token uç noktasında ID token'a çevirir, token'ı doğrular ve
+// içindeki kullanıcı bilgilerini döndürür. nonce, AuthURL'e verilen değerle eşleşmelidir.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.redirectURL},
+		"client_id":     {p.clientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// Gizli anahtarı olmayan (public) istemciler yalnızca PKCE'ye dayanır.
+	if p.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+	}
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := p.doJSON(req, &token); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("%w: yanıtta id_token yok", ErrExchange)
+	}
+
+	claims, err := p.verify(ctx, d, token.IDToken, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	return claims, nil
+}
+
+// idTokenClaims, ID token'ın doğrulanan ve okunan alanlarıdır.
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce"`
+	Email             string `json:"email"`
+	EmailVerified     any    `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+	Name              string `json:"name"`
+}
+
+// verify, ID token'ın imzasını, yayıncısını, hedef kitlesini, süresini ve nonce değerini doğrular.
+func (p *Provider) verify(ctx context.Context, d *discovery, raw, nonce string) (*Claims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, d, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(p.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("nonce eşleşmiyor")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("sub alanı boş")
+	}
+
+	// Bazı sağlayıcılar email_verified değerini metin olarak gönderir.
+	verified := false
+	switch v := claims.EmailVerified.(type) {
+	case bool:
+		verified = v
+	case string:
+		verified = v == "true"
+	}
+
+	return &Claims{
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     verified,
+		PreferredUsername: claims.PreferredUsername,
+		Name:              claims.Name,
+	}, nil
+}
+
+// discover, sağlayıcının discovery belgesini ilk çağrıda indirir ve saklar. Hata durumunda
+// saklanmaz; sonraki giriş denemesi yeniden dener.
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var d discovery
+	if err := p.doJSON(req, &d); err != nil {
+		return nil, fmt.Errorf("oidc: discovery belgesi okunamadı: %w", err)
+	}
+	// Başka bir yayıncının belgesi kabul edilmez; aksi hâlde token doğrulaması yanlış yayıncıya göre yapılırdı.
+	if strings.TrimRight(d.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("oidc: discovery belgesindeki issuer (%q) yapılandırmayla eşleşmiyor", d.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, errors.New("oidc: discovery belgesinde uç noktalar eksik")
+	}
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+// key, kid kimlikli doğrulama anahtarını döndürür. Anahtar bilinmiyorsa sağlayıcı anahtarlarını
+// döndürmüş olabileceğinden JWKS yeniden indirilir.
+func (p *Provider) key(ctx context.Context, d *discovery, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookup(kid); ok {
+		return key, nil
+	}
+	keys, err := p.fetchKeys(ctx, d.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	if key, ok := p.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("oidc: %q kimlikli anahtar bulunamadı", kid)
+}
+
+// lookup, çağıranın kilidi tuttuğu varsayımıyla anahtarı arar. kid boşsa ve tek anahtar varsa o kullanılır.
+func (p *Provider) lookup(kid string) (any, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *Provider) fetchKeys(ctx context.Context, uri string) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set jwkSet
+	if err := p.doJSON(req, &set); err != nil {
+		return nil, fmt.Errorf("oidc: JWKS okunamadı: %w", err)
+	}
+	return set.publicKeys(), nil
+}
+
+// doJSON, isteği gönderir ve 2xx yanıtların gövdesini v'ye çözer.
+func (p *Provider) doJSON(req *http.Request, v any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s: HTTP %d: %s", req.Method, req.URL.Redacted(), resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,83 @@
+// Package rbac, kullanıcıların rollerini ve izinlerini çözümler ve kısa süreliğine önbellekte tutar.
+//
+// Admin uçları her istekte izin kontrolü yaptığından rol veritabanından her seferinde okunmaz.
+// Önbellek süreç içidir; rol ataması ve izin değişiklikleri bu süreçte hemen geçerli olur, diğer
+// sunucu örneklerinde ise en geç önbellek süresi dolduğunda geçerli olur.
+package rbac
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+)
+
+// Resolver, kullanıcıların rollerini izinleriyle birlikte çözümler.
+type Resolver struct {
+	roles repository.RoleRepository
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]entry
+}
+
+// sweepSize, süresi dolmuş kayıtların temizlenmeye başlandığı önbellek boyutudur.
+const sweepSize = 1024
+
+type entry struct {
+	role    models.Role
+	expires time.Time
+}
+
+// New, verilen repository'yi kullanan bir Resolver oluşturur. ttl, çözümlenen rollerin
+// önbellekte kalma süresidir; sıfırsa önbellek kullanılmaz.
+func New(roles repository.RoleRepository, ttl time.Duration) *Resolver {
+	return &Resolver{roles: roles, ttl: ttl, entries: make(map[uuid.UUID]entry)}
+}
+
+// Role, kullanıcının rolünü izinleriyle döndürür. Kullanıcı yoksa repository.ErrNotFound döner.
+func (r *Resolver) Role(ctx context.Context, userID uuid.UUID) (models.Role, error) {
+	now := time.Now()
+	r.mu.Lock()
+	e, ok := r.entries[userID]
+	r.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.role, nil
+	}
+
+	role, err := r.roles.ForUser(ctx, userID)
+	if err != nil {
+		return models.Role{}, err
+	}
+	if r.ttl > 0 {
+		r.mu.Lock()
+		if len(r.entries) >= sweepSize {
+			for id, e := range r.entries {
+				if !now.Before(e.expires) {
+					delete(r.entries, id)
+				}
+			}
+		}
+		r.entries[userID] = entry{role: *role, expires: now.Add(r.ttl)}
+		r.mu.Unlock()
+	}
+	return *role, nil
+}
+
+// Forget, kullanıcının önbellekteki rolünü siler; rolü değiştirildiğinde çağrılır.
+func (r *Resolver) Forget(userID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.entries, userID)
+}
+
+// Reset, önbelleği tamamen temizler; bir rolün izinleri değiştirildiğinde çağrılır.
+func (r *Resolver) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.entries)
+}
@@ -0,0 +1,135 @@
+package rbac_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"spoti/auth"
+	"spoti/models"
+	"spoti/rbac"
+	"spoti/repository"
+	"spoti/repository/memory"
+
+	"github.com/google/uuid"
+)
+
+func createUser(t *testing.T, mem *memory.Store, role string) uuid.UUID {
+	t.Helper()
+	user := &models.User{Username: uuid.NewString()[:8], Email: uuid.NewString() + "@spoti.test"}
+	if err := mem.Users().Create(context.Background(), user, role); err != nil {
+		t.Fatal(err)
+	}
+	return user.ID
+}
+
+func TestBuiltInRolePermissions(t *testing.T) {
+	tests := []struct {
+		role    string
+		allowed []string
+	}{
+		{auth.RoleAdmin, auth.Permissions},
+		{auth.RoleContentEditor, []string{auth.PermSongsWrite}},
+		{auth.RoleSupport, []string{auth.PermUsersRead, auth.PermUsersWrite}},
+		{auth.RoleUser, nil},
+	}
+	mem := memory.NewStore()
+	resolver := rbac.New(mem.Roles(), time.Minute)
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			role, err := resolver.Role(context.Background(), createUser(t, mem, tt.role))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if role.Name != tt.role {
+				t.Fatalf("rol = %s", role.Name)
+			}
+			for _, permission := range auth.Permissions {
+				if got, want := slices.Contains(role.Permissions, permission), slices.Contains(tt.allowed, permission); got != want {
+					t.Errorf("%s izni = %v, beklenen %v", permission, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestResolverUnknownUser(t *testing.T) {
+	resolver := rbac.New(memory.NewStore().Roles(), time.Minute)
+	if _, err := resolver.Role(context.Background(), uuid.New()); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("bilinmeyen kullanıcı hatası = %v", err)
+	}
+}
+
+func TestResolverCache(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		// invalidate, rol değişikliğinden sonra önbelleği temizler; nil ise temizlenmez.
+		invalidate func(r *rbac.Resolver, userID uuid.UUID)
+		// fresh, değişikliğin hemen görülmesinin beklenip beklenmediğidir.
+		fresh bool
+	}{
+		{"önbellek eski rolü döndürür", time.Minute, nil, false},
+		{"Forget kullanıcının rolünü yeniler", time.Minute, func(r *rbac.Resolver, id uuid.UUID) { r.Forget(id) }, true},
+		{"Reset tüm önbelleği yeniler", time.Minute, func(r *rbac.Resolver, _ uuid.UUID) { r.Reset() }, true},
+		{"sıfır süre önbelleği kapatır", 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mem := memory.NewStore()
+			resolver := rbac.New(mem.Roles(), tt.ttl)
+			userID := createUser(t, mem, auth.RoleUser)
+
+			if _, err := resolver.Role(ctx, userID); err != nil {
+				t.Fatal(err)
+			}
+			if err := mem.Users().SetRole(ctx, userID, auth.RoleSupport); err != nil {
+				t.Fatal(err)
+			}
+			if tt.invalidate != nil {
+				tt.invalidate(resolver, userID)
+			}
+
+			role, err := resolver.Role(ctx, userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := auth.RoleUser
+			if tt.fresh {
+				want = auth.RoleSupport
+			}
+			if role.Name != want {
+				t.Fatalf("rol = %s, beklenen %s", role.Name, want)
+			}
+		})
+	}
+}
+
+func TestResolverResetAfterPermissionChange(t *testing.T) {
+	ctx := context.Background()
+	mem := memory.NewStore()
+	if err := mem.Roles().Create(ctx, &models.Role{Name: "reviewer", Permissions: []string{auth.PermUsersRead}}); err != nil {
+		t.Fatal(err)
+	}
+	resolver := rbac.New(mem.Roles(), time.Minute)
+	userID := createUser(t, mem, "reviewer")
+
+	if _, err := resolver.Role(ctx, userID); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.Roles().SetPermissions(ctx, "reviewer", []string{auth.PermUsersRead, auth.PermSongsWrite}); err != nil {
+		t.Fatal(err)
+	}
+	resolver.Reset()
+
+	role, err := resolver.Role(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(role.Permissions, auth.PermSongsWrite) {
+		t.Fatalf("izin değişikliği görülmedi: %v", role.Permissions)
+	}
+}
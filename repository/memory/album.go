@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"spoti/models"
+	"spoti/repository"
+	"spoti/search"
+
+	"github.com/google/uuid"
+)
+
+// AlbumRepository, repository.AlbumRepository arayüzünün bellek içi gerçeklemesidir.
+type AlbumRepository struct {
+	s *Store
+}
+
+var _ repository.AlbumRepository = (*AlbumRepository)(nil)
+
+func (r *AlbumRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Album, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	album, ok := r.s.albums[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	album = r.s.albumView(album)
+	return &album, nil
+}
+
+func (r *AlbumRepository) Tracks(ctx context.Context, albumID uuid.UUID) ([]models.Song, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var songs []models.Song
+	for _, song := range r.s.songs {
+		if song.AlbumID != nil && *song.AlbumID == albumID {
+			songs = append(songs, song)
+		}
+	}
+	// PostgreSQL'deki "track_number NULLS LAST, title, id" sıralamasını taklit eder.
+	slices.SortFunc(songs, func(a, b models.Song) int {
+		if (a.TrackNumber == nil) != (b.TrackNumber == nil) {
+			if a.TrackNumber == nil {
+				return 1
+			}
+			return -1
+		}
+		if a.TrackNumber != nil {
+			if c := cmp.Compare(*a.TrackNumber, *b.TrackNumber); c != 0 {
+				return c
+			}
+		}
+		return cmp.Or(strings.Compare(a.Title, b.Title), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+	return songs, nil
+}
+
+func (r *AlbumRepository) Update(ctx context.Context, album *models.Album) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.albums[album.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	album.Title = search.CleanName(album.Title)
+	if other, ok := r.s.findAlbum(existing.ArtistID, album.Title); ok && other.ID != album.ID {
+		return repository.ErrConflict
+	}
+	existing.Title = album.Title
+	existing.ReleaseDate = album.ReleaseDate
+	r.s.albums[album.ID] = existing
+
+	for id, song := range r.s.songs {
+		if song.AlbumID != nil && *song.AlbumID == album.ID {
+			song.Album = album.Title
+			r.s.songs[id] = song
+		}
+	}
+	return nil
+}
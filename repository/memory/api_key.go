@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyRepository, repository.APIKeyRepository arayüzünün bellek içi gerçeklemesidir.
+type APIKeyRepository struct {
+	s *Store
+}
+
+var _ repository.APIKeyRepository = (*APIKeyRepository)(nil)
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[key.UserID]; !ok {
+		return repository.ErrNotFound
+	}
+	for _, existing := range r.s.apiKeys {
+		if existing.KeyHash == key.KeyHash || (existing.UserID == key.UserID && existing.Name == key.Name) {
+			return repository.ErrConflict
+		}
+	}
+	key.ID = uuid.New()
+	key.CreatedAt = time.Now()
+	key.Scopes = append([]string(nil), key.Scopes...)
+	r.s.apiKeys[key.ID] = *key
+	return nil
+}
+
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var keys []models.APIKey
+	for _, key := range r.s.apiKeys {
+		if key.UserID == userID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, key := range r.s.apiKeys {
+		if key.KeyHash == hash {
+			return &key, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *APIKeyRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key, ok := r.s.apiKeys[id]
+	if !ok || key.UserID != userID {
+		return repository.ErrNotFound
+	}
+	delete(r.s.apiKeys, id)
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if key, ok := r.s.apiKeys[id]; ok {
+		key.LastUsedAt = &at
+		r.s.apiKeys[id] = key
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"spoti/models"
+	"spoti/repository"
+	"spoti/search"
+
+	"github.com/google/uuid"
+)
+
+// ArtistRepository, repository.ArtistRepository arayüzünün bellek içi gerçeklemesidir.
+type ArtistRepository struct {
+	s *Store
+}
+
+var _ repository.ArtistRepository = (*ArtistRepository)(nil)
+
+func (r *ArtistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Artist, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	artist, ok := r.s.artists[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &artist, nil
+}
+
+func (r *ArtistRepository) Albums(ctx context.Context, artistID uuid.UUID) ([]models.Album, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var albums []models.Album
+	for _, album := range r.s.albums {
+		if album.ArtistID == artistID {
+			albums = append(albums, r.s.albumView(album))
+		}
+	}
+	// PostgreSQL'deki "release_date DESC NULLS LAST, title, id" sıralamasını taklit eder.
+	slices.SortFunc(albums, func(a, b models.Album) int {
+		if (a.ReleaseDate == nil) != (b.ReleaseDate == nil) {
+			if a.ReleaseDate == nil {
+				return 1
+			}
+			return -1
+		}
+		if a.ReleaseDate != nil {
+			if c := b.ReleaseDate.Compare(*a.ReleaseDate); c != 0 {
+				return c
+			}
+		}
+		return cmp.Or(strings.Compare(a.Title, b.Title), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+	return albums, nil
+}
+
+// albumView, çağıranın kilidi tuttuğu varsayımıyla albümün okunurken doldurulan alanlarını ekler.
+func (s *Store) albumView(album models.Album) models.Album {
+	album.Artist = s.artists[album.ArtistID].Name
+	for _, song := range s.songs {
+		if song.AlbumID != nil && *song.AlbumID == album.ID {
+			album.TrackCount++
+		}
+	}
+	return album
+}
+
+// resolveCredits, çağıranın yazma kilidini tuttuğu varsayımıyla şarkıda adı geçen sanatçıları
+// ve albümü PostgreSQL gerçeklemesindeki gibi kayıtlara çözer; olmayanları oluşturur.
+func (s *Store) resolveCredits(song *models.Song) {
+	var names []string
+	for _, artist := range song.Artists {
+		names = append(names, artist.Name)
+	}
+	if len(names) == 0 {
+		names = []string{song.Artist}
+	}
+
+	var artists []models.SongArtist
+	for _, name := range names {
+		name = search.CleanName(name)
+		if name == "" {
+			continue
+		}
+		artist := s.artistByName(name)
+		if !slices.ContainsFunc(artists, func(a models.SongArtist) bool { return a.ID == artist.ID }) {
+			artists = append(artists, models.SongArtist{ID: artist.ID, Name: artist.Name})
+		}
+	}
+	song.Artists = artists
+	song.Artist = models.ArtistCredit(artists)
+
+	song.Album = search.CleanName(song.Album)
+	song.AlbumID = nil
+	if song.Album == "" || len(artists) == 0 {
+		return
+	}
+	album := s.albumByTitle(artists[0].ID, song.Album)
+	song.Album = album.Title
+	song.AlbumID = &album.ID
+}
+
+// artistByName, çağıranın yazma kilidini tuttuğu varsayımıyla adı aynı anahtara sahip sanatçıyı
+// döndürür; yoksa oluşturur.
+func (s *Store) artistByName(name string) models.Artist {
+	key := search.NameKey(name)
+	for _, artist := range s.artists {
+		if search.NameKey(artist.Name) == key {
+			return artist
+		}
+	}
+	artist := models.Artist{ID: uuid.New(), Name: name, CreatedAt: time.Now()}
+	s.artists[artist.ID] = artist
+	return artist
+}
+
+// albumByTitle, çağıranın yazma kilidini tuttuğu varsayımıyla sanatçının adı aynı anahtara
+// sahip albümünü döndürür; yoksa oluşturur.
+func (s *Store) albumByTitle(artistID uuid.UUID, title string) models.Album {
+	if album, ok := s.findAlbum(artistID, title); ok {
+		return album
+	}
+	album := models.Album{ID: uuid.New(), Title: title, ArtistID: artistID, CreatedAt: time.Now()}
+	s.albums[album.ID] = album
+	return album
+}
+
+// findAlbum, çağıranın kilidi tuttuğu varsayımıyla sanatçının adı aynı anahtara sahip albümünü bulur.
+func (s *Store) findAlbum(artistID uuid.UUID, title string) (models.Album, bool) {
+	key := search.NameKey(title)
+	for _, album := range s.albums {
+		if album.ArtistID == artistID && search.NameKey(album.Title) == key {
+			return album, true
+		}
+	}
+	return models.Album{}, false
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+)
+
+// CouponRepository, repository.CouponRepository arayüzünün bellek içi gerçeklemesidir.
+type CouponRepository struct {
+	s *Store
+}
+
+var _ repository.CouponRepository = (*CouponRepository)(nil)
+
+func (r *CouponRepository) Create(ctx context.Context, coupon *models.Coupon) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.coupons {
+		if existing.Code == coupon.Code {
+			return repository.ErrConflict
+		}
+	}
+	coupon.ID = uuid.New()
+	r.s.coupons[coupon.ID] = *coupon
+	return nil
+}
+
+func (r *CouponRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	_, ok := r.s.coupons[id]
+	return ok, nil
+}
+
+func (r *CouponRepository) AssignToUser(ctx context.Context, couponID, userID uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	coupon, ok := r.s.coupons[couponID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if _, ok := r.s.users[userID]; !ok {
+		return repository.ErrNotFound
+	}
+	coupon.UserID = userID
+	r.s.coupons[couponID] = coupon
+	return nil
+}
+
+func (r *CouponRepository) AssignToAll(ctx context.Context, couponID uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	coupon, ok := r.s.coupons[couponID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	// PostgreSQL gerçeklemesindeki UPDATE ... FROM t_users gibi, kupon tek bir kullanıcıya bağlanır.
+	for userID := range r.s.users {
+		coupon.UserID = userID
+		break
+	}
+	r.s.coupons[couponID] = coupon
+	return nil
+}
+
+func (r *CouponRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Coupon, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var coupons []models.Coupon
+	for _, coupon := range r.s.coupons {
+		if coupon.UserID == userID {
+			coupons = append(coupons, coupon)
+		}
+	}
+	sort.Slice(coupons, func(i, j int) bool { return coupons[i].Code < coupons[j].Code })
+	return coupons, nil
+}
+
+func (r *CouponRepository) GetForUser(ctx context.Context, couponID, userID uuid.UUID) (*models.Coupon, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	coupon, ok := r.s.coupons[couponID]
+	if !ok || coupon.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &coupon, nil
+}
+
+func (r *CouponRepository) Redeem(ctx context.Context, couponID, userID uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	coupon, ok := r.s.coupons[couponID]
+	if !ok || coupon.UserID != userID || coupon.IsUsed {
+		return repository.ErrCouponUsed
+	}
+	user, ok := r.s.users[userID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+
+	coupon.IsUsed = true
+	r.s.coupons[couponID] = coupon
+	user.HesapTuru = "Premium"
+	r.s.users[userID] = user
+	return nil
+}
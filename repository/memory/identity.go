@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+)
+
+// IdentityRepository, repository.IdentityRepository arayüzünün bellek içi gerçeklemesidir.
+type IdentityRepository struct {
+	s *Store
+}
+
+var _ repository.IdentityRepository = (*IdentityRepository)(nil)
+
+func (r *IdentityRepository) Get(ctx context.Context, provider, subject string) (*models.Identity, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, identity := range r.s.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return &identity, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *IdentityRepository) Create(ctx context.Context, identity *models.Identity) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[identity.UserID]; !ok {
+		return repository.ErrNotFound
+	}
+	return r.insert(identity)
+}
+
+func (r *IdentityRepository) CreateUser(ctx context.Context, user *models.User, roleName string, identity *models.Identity) error {
+	// Kullanıcı ve bağlantı aynı kilit altında eklenmediğinden bağlantı çakışırsa kullanıcı geri alınır.
+	if err := r.s.Users().Create(ctx, user, roleName); err != nil {
+		return err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	identity.UserID = user.ID
+	if err := r.insert(identity); err != nil {
+		delete(r.s.users, user.ID)
+		return err
+	}
+	return nil
+}
+
+// insert, çağıranın kilidi tuttuğu varsayımıyla bağlantıyı ekler.
+func (r *IdentityRepository) insert(identity *models.Identity) error {
+	for _, existing := range r.s.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return repository.ErrConflict
+		}
+	}
+	identity.ID = uuid.New()
+	identity.CreatedAt = time.Now()
+	r.s.identities[identity.ID] = *identity
+	return nil
+}
@@ -0,0 +1,137 @@
+// Package memory, repository arayüzlerinin bellek içi gerçeklemelerini sağlar.
+// Handler'ları veritabanı olmadan test etmek ve yerel denemeler için kullanılır;
+// tüm repository'ler aynı Store üzerinde çalıştığından tablolar arası ilişkiler korunur.
+package memory
+
+import (
+	"slices"
+	"sync"
+
+	"spoti/auth"
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+)
+
+// Store, bellek içi repository'lerin paylaştığı tabloları tutar.
+type Store struct {
+	mu            sync.RWMutex
+	roles         map[uuid.UUID]models.Role
+	users         map[uuid.UUID]models.User
+	songs         map[uuid.UUID]models.Song
+	artists       map[uuid.UUID]models.Artist
+	albums        map[uuid.UUID]models.Album
+	playlists     map[uuid.UUID]models.Playlist
+	playlistSongs map[uuid.UUID][]uuid.UUID
+	coupons       map[uuid.UUID]models.Coupon
+	refreshTokens map[uuid.UUID]models.RefreshToken
+	apiKeys       map[uuid.UUID]models.APIKey
+	userTokens    map[uuid.UUID]models.UserToken
+	twoFactor     map[uuid.UUID]models.TwoFactor
+	recoveryCodes map[uuid.UUID][]recoveryCode
+	identities    map[uuid.UUID]models.Identity
+}
+
+// NewStore, migration'lardaki yerleşik rollerle ve izinleriyle boş bir Store oluşturur.
+func NewStore() *Store {
+	s := &Store{
+		roles:         make(map[uuid.UUID]models.Role),
+		users:         make(map[uuid.UUID]models.User),
+		songs:         make(map[uuid.UUID]models.Song),
+		artists:       make(map[uuid.UUID]models.Artist),
+		albums:        make(map[uuid.UUID]models.Album),
+		playlists:     make(map[uuid.UUID]models.Playlist),
+		playlistSongs: make(map[uuid.UUID][]uuid.UUID),
+		coupons:       make(map[uuid.UUID]models.Coupon),
+		refreshTokens: make(map[uuid.UUID]models.RefreshToken),
+		apiKeys:       make(map[uuid.UUID]models.APIKey),
+		userTokens:    make(map[uuid.UUID]models.UserToken),
+		twoFactor:     make(map[uuid.UUID]models.TwoFactor),
+		recoveryCodes: make(map[uuid.UUID][]recoveryCode),
+		identities:    make(map[uuid.UUID]models.Identity),
+	}
+	for name, permissions := range auth.BuiltInRoles {
+		role := models.Role{ID: uuid.New(), Name: name, BuiltIn: true, Permissions: slices.Sorted(slices.Values(permissions))}
+		s.roles[role.ID] = role
+	}
+	return s
+}
+
+// Users, Store üzerinde çalışan bir UserRepository döndürür.
+func (s *Store) Users() *UserRepository { return &UserRepository{s: s} }
+
+// Songs, Store üzerinde çalışan bir SongRepository döndürür.
+func (s *Store) Songs() *SongRepository { return &SongRepository{s: s} }
+
+// Artists, Store üzerinde çalışan bir ArtistRepository döndürür.
+func (s *Store) Artists() *ArtistRepository { return &ArtistRepository{s: s} }
+
+// Albums, Store üzerinde çalışan bir AlbumRepository döndürür.
+func (s *Store) Albums() *AlbumRepository { return &AlbumRepository{s: s} }
+
+// Playlists, Store üzerinde çalışan bir PlaylistRepository döndürür.
+func (s *Store) Playlists() *PlaylistRepository { return &PlaylistRepository{s: s} }
+
+// Coupons, Store üzerinde çalışan bir CouponRepository döndürür.
+func (s *Store) Coupons() *CouponRepository { return &CouponRepository{s: s} }
+
+// RefreshTokens, Store üzerinde çalışan bir RefreshTokenRepository döndürür.
+func (s *Store) RefreshTokens() *RefreshTokenRepository { return &RefreshTokenRepository{s: s} }
+
+// APIKeys, Store üzerinde çalışan bir APIKeyRepository döndürür.
+func (s *Store) APIKeys() *APIKeyRepository { return &APIKeyRepository{s: s} }
+
+// UserTokens, Store üzerinde çalışan bir UserTokenRepository döndürür.
+func (s *Store) UserTokens() *UserTokenRepository { return &UserTokenRepository{s: s} }
+
+// Identities, Store üzerinde çalışan bir IdentityRepository döndürür.
+func (s *Store) Identities() *IdentityRepository { return &IdentityRepository{s: s} }
+
+// Roles, Store üzerinde çalışan bir RoleRepository döndürür.
+func (s *Store) Roles() *RoleRepository { return &RoleRepository{s: s} }
+
+// TwoFactor, Store üzerinde çalışan bir TwoFactorRepository döndürür.
+func (s *Store) TwoFactor() *TwoFactorRepository { return &TwoFactorRepository{s: s} }
+
+// keysetPage, sıralama yönünde dizilmiş items içinden page'in istediği sayfayı seçer. compare,
+// bir satırı sınır anahtarıyla sıralama yönünde karşılaştırır. Satırlar PostgreSQL
+// gerçeklemesindeki gibi sınırdan itibaren en fazla Limit+1 tane okunup TrimPage ile sayfaya
+// çevrilir; Total, items'ın tamamıdır.
+func keysetPage[T, K any](items []T, page repository.Page[K], compare func(T, K) int) ([]T, repository.PageInfo) {
+	boundary, reverse := page.Boundary()
+	var rows []T
+	if reverse {
+		for i := len(items) - 1; i >= 0 && len(rows) <= page.Limit; i-- {
+			if compare(items[i], *boundary) < 0 {
+				rows = append(rows, items[i])
+			}
+		}
+	} else {
+		for _, item := range items {
+			if len(rows) > page.Limit {
+				break
+			}
+			if boundary == nil || compare(item, *boundary) > 0 {
+				rows = append(rows, item)
+			}
+		}
+	}
+
+	rows, info := repository.TrimPage(rows, page)
+	if page.WithTotal {
+		total := len(items)
+		info.Total = &total
+	}
+	return rows, info
+}
+
+// roleByName, çağıranın kilidi tuttuğu varsayımıyla rolü adıyla bulur.
+func (s *Store) roleByName(name string) (models.Role, bool) {
+	for _, role := range s.roles {
+		if role.Name == name {
+			return role, true
+		}
+	}
+	return models.Role{}, false
+}
@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+)
+
+// PlaylistRepository, repository.PlaylistRepository arayüzünün bellek içi gerçeklemesidir.
+type PlaylistRepository struct {
+	s *Store
+}
+
+var _ repository.PlaylistRepository = (*PlaylistRepository)(nil)
+
+func (r *PlaylistRepository) Create(ctx context.Context, playlist *models.Playlist) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[playlist.UserID]; !ok {
+		return repository.ErrNotFound
+	}
+	playlist.ID = uuid.New()
+	r.s.playlists[playlist.ID] = *playlist
+	return nil
+}
+
+func (r *PlaylistRepository) ListByUser(ctx context.Context, userID uuid.UUID, page repository.Page[repository.PlaylistKey]) ([]models.Playlist, repository.PageInfo, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var playlists []models.Playlist
+	for _, playlist := range r.s.playlists {
+		if playlist.UserID == userID {
+			playlists = append(playlists, playlist)
+		}
+	}
+	compare := func(playlist models.Playlist, key repository.PlaylistKey) int {
+		return cmp.Or(strings.Compare(playlist.Name, key.Name), bytes.Compare(playlist.ID[:], key.ID[:]))
+	}
+	slices.SortFunc(playlists, func(a, b models.Playlist) int { return compare(a, repository.NewPlaylistKey(&b)) })
+
+	playlists, info := keysetPage(playlists, page, compare)
+	return playlists, info, nil
+}
+
+func (r *PlaylistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Playlist, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	playlist, ok := r.s.playlists[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &playlist, nil
+}
+
+func (r *PlaylistRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.playlists[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.s.playlists, id)
+	delete(r.s.playlistSongs, id)
+	return nil
+}
+
+func (r *PlaylistRepository) Songs(ctx context.Context, playlistID uuid.UUID) ([]models.Song, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var songs []models.Song
+	for _, songID := range r.s.playlistSongs[playlistID] {
+		if song, ok := r.s.songs[songID]; ok {
+			songs = append(songs, song)
+		}
+	}
+	return songs, nil
+}
+
+func (r *PlaylistRepository) CountSongs(ctx context.Context, playlistID uuid.UUID) (int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return len(r.s.playlistSongs[playlistID]), nil
+}
+
+func (r *PlaylistRepository) AddSong(ctx context.Context, playlistID, songID uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.playlists[playlistID]; !ok {
+		return repository.ErrNotFound
+	}
+	if _, ok := r.s.songs[songID]; !ok {
+		return repository.ErrNotFound
+	}
+	for _, existing := range r.s.playlistSongs[playlistID] {
+		if existing == songID {
+			return repository.ErrConflict
+		}
+	}
+	r.s.playlistSongs[playlistID] = append(r.s.playlistSongs[playlistID], songID)
+	return nil
+}
+
+func (r *PlaylistRepository) FindUserSong(ctx context.Context, userID, songID uuid.UUID) (*models.Song, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for playlistID, playlist := range r.s.playlists {
+		if playlist.UserID != userID {
+			continue
+		}
+		for _, id := range r.s.playlistSongs[playlistID] {
+			if id == songID {
+				song := r.s.songs[songID]
+				return &song, nil
+			}
+		}
+	}
+	return nil, repository.ErrNotFound
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenRepository, repository.RefreshTokenRepository arayüzünün bellek içi gerçeklemesidir.
+type RefreshTokenRepository struct {
+	s *Store
+}
+
+var _ repository.RefreshTokenRepository = (*RefreshTokenRepository)(nil)
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.insert(token)
+}
+
+// insert, çağıranın yazma kilidini tuttuğu varsayımıyla token'ı kaydeder.
+func (r *RefreshTokenRepository) insert(token *models.RefreshToken) error {
+	if _, ok := r.s.users[token.UserID]; !ok {
+		return repository.ErrNotFound
+	}
+	for _, existing := range r.s.refreshTokens {
+		if existing.TokenHash == token.TokenHash {
+			return repository.ErrConflict
+		}
+	}
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+	r.s.refreshTokens[token.ID] = *token
+	return nil
+}
+
+func (r *RefreshTokenRepository) GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, token := range r.s.refreshTokens {
+		if token.TokenHash == hash {
+			return &token, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, oldID uuid.UUID, next *models.RefreshToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	old, ok := r.s.refreshTokens[oldID]
+	if !ok || old.RevokedAt != nil {
+		return repository.ErrConflict
+	}
+	if err := r.insert(next); err != nil {
+		return err
+	}
+	now := time.Now()
+	old.RevokedAt = &now
+	r.s.refreshTokens[oldID] = old
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	r.revokeWhere(func(t models.RefreshToken) bool { return t.FamilyID == familyID })
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	r.revokeWhere(func(t models.RefreshToken) bool { return t.UserID == userID })
+	return nil
+}
+
+func (r *RefreshTokenRepository) revokeWhere(match func(models.RefreshToken) bool) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	for id, token := range r.s.refreshTokens {
+		if token.RevokedAt == nil && match(token) {
+			token.RevokedAt = &now
+			r.s.refreshTokens[id] = token
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+)
+
+// RoleRepository, repository.RoleRepository arayüzünün bellek içi gerçeklemesidir.
+type RoleRepository struct {
+	s *Store
+}
+
+var _ repository.RoleRepository = (*RoleRepository)(nil)
+
+func (r *RoleRepository) List(ctx context.Context) ([]models.Role, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	roles := make([]models.Role, 0, len(r.s.roles))
+	for _, role := range r.s.roles {
+		roles = append(roles, cloneRole(role))
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+func (r *RoleRepository) GetByName(ctx context.Context, name string) (*models.Role, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	role, ok := r.s.roleByName(name)
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	role = cloneRole(role)
+	return &role, nil
+}
+
+func (r *RoleRepository) ForUser(ctx context.Context, userID uuid.UUID) (*models.Role, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	user, ok := r.s.users[userID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	role, ok := r.s.roles[user.RoleID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	role = cloneRole(role)
+	return &role, nil
+}
+
+func (r *RoleRepository) Create(ctx context.Context, role *models.Role) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.roleByName(role.Name); ok {
+		return repository.ErrConflict
+	}
+	role.ID = uuid.New()
+	role.BuiltIn = false
+	stored := cloneRole(*role)
+	r.s.roles[role.ID] = stored
+	role.Permissions = slices.Clone(stored.Permissions)
+	return nil
+}
+
+func (r *RoleRepository) SetPermissions(ctx context.Context, name string, permissions []string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	role, ok := r.s.roleByName(name)
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if role.BuiltIn {
+		return repository.ErrConflict
+	}
+	role.Permissions = permissions
+	r.s.roles[role.ID] = cloneRole(role)
+	return nil
+}
+
+// cloneRole, izin dilimini paylaşmayan ve PostgreSQL gerçeklemesi gibi sıralı izinler
+// içeren bir kopya döndürür.
+func cloneRole(role models.Role) models.Role {
+	role.Permissions = slices.Sorted(slices.Values(role.Permissions))
+	return role
+}
@@ -0,0 +1,372 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"spoti/models"
+	"spoti/repository"
+	"spoti/search"
+
+	"github.com/google/uuid"
+)
+
+// SongRepository, repository.SongRepository arayüzünün bellek içi gerçeklemesidir.
+type SongRepository struct {
+	s *Store
+}
+
+var _ repository.SongRepository = (*SongRepository)(nil)
+
+func (r *SongRepository) List(ctx context.Context, filter repository.SongFilter, page repository.Page[repository.SongKey]) ([]models.Song, repository.PageInfo, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	terms := search.Terms(filter.Search)
+	var matched []models.Song
+	for _, song := range r.s.songs {
+		if !matchFilter(song, filter) {
+			continue
+		}
+		if len(terms) == 0 {
+			matched = append(matched, song)
+			continue
+		}
+		if rank, highlight, ok := matchSong(song, terms); ok {
+			song.Rank = rank
+			song.Highlight = highlight
+			matched = append(matched, song)
+		}
+	}
+
+	sortBy := filter.Sort
+	if sortBy == repository.SongSortRelevance && len(terms) == 0 {
+		sortBy = repository.SongSortPopularity
+	}
+	order := songOrder(sortBy, filter.Desc)
+	slices.SortFunc(matched, order)
+
+	songs, info := keysetPage(matched, page, func(song models.Song, key repository.SongKey) int {
+		return order(song, keySong(key))
+	})
+	return songs, info, nil
+}
+
+// songOrder, iki şarkıyı sortBy sıralamasında desc yönüne göre karşılaştırır. Yayın yılı
+// bilinmeyen şarkılar her iki yönde de sona kalır (NULLS LAST); eşit değerler ID ile ayrılır.
+func songOrder(sortBy string, desc bool) func(a, b models.Song) int {
+	return func(a, b models.Song) int {
+		if sortBy == repository.SongSortNewest && (a.ReleaseYear == nil) != (b.ReleaseYear == nil) {
+			if a.ReleaseYear == nil {
+				return 1
+			}
+			return -1
+		}
+		c := cmp.Or(compareSongs(a, b, sortBy), bytes.Compare(a.ID[:], b.ID[:]))
+		if desc {
+			return -c
+		}
+		return c
+	}
+}
+
+// keySong, sayfa sınırının anahtarını songOrder ile karşılaştırılabilecek bir şarkıya çevirir.
+func keySong(key repository.SongKey) models.Song {
+	return models.Song{
+		ID:          key.ID,
+		Title:       key.Title,
+		Artist:      key.Artist,
+		Duration:    key.Duration,
+		ClickCount:  key.ClickCount,
+		ReleaseYear: key.ReleaseYear,
+		CreatedAt:   key.CreatedAt,
+		Rank:        key.Rank,
+	}
+}
+
+// matchFilter, şarkının arama dışındaki filtrelere uyup uymadığını döndürür.
+func matchFilter(song models.Song, filter repository.SongFilter) bool {
+	if filter.Artist != "" && !slices.ContainsFunc(song.Artists, func(a models.SongArtist) bool {
+		return search.NameKey(a.Name) == search.NameKey(filter.Artist)
+	}) {
+		return false
+	}
+	if filter.Album != "" && search.Fold(song.Album) != search.NameKey(filter.Album) {
+		return false
+	}
+	if filter.Genre != "" && search.Fold(song.Genre) != search.Fold(filter.Genre) {
+		return false
+	}
+	if filter.MinDuration > 0 && song.Duration < filter.MinDuration {
+		return false
+	}
+	if filter.MaxDuration > 0 && song.Duration > filter.MaxDuration {
+		return false
+	}
+	if (filter.YearFrom > 0 || filter.YearTo > 0) && song.ReleaseYear == nil {
+		return false
+	}
+	if filter.YearFrom > 0 && *song.ReleaseYear < filter.YearFrom {
+		return false
+	}
+	if filter.YearTo > 0 && *song.ReleaseYear > filter.YearTo {
+		return false
+	}
+	return true
+}
+
+// compareSongs, iki şarkıyı artan sıralamaya göre karşılaştırır; PostgreSQL gerçeklemesindeki
+// ORDER BY ifadelerini taklit eder.
+func compareSongs(a, b models.Song, sortBy string) int {
+	switch sortBy {
+	case repository.SongSortRelevance:
+		return cmp.Or(cmp.Compare(a.Rank, b.Rank), cmp.Compare(a.ClickCount, b.ClickCount))
+	case repository.SongSortTitle:
+		return strings.Compare(a.Title, b.Title)
+	case repository.SongSortArtist:
+		return cmp.Or(strings.Compare(a.Artist, b.Artist), strings.Compare(a.Title, b.Title))
+	case repository.SongSortDuration:
+		return cmp.Compare(a.Duration, b.Duration)
+	case repository.SongSortNewest:
+		if a.ReleaseYear != nil && b.ReleaseYear != nil {
+			if c := cmp.Compare(*a.ReleaseYear, *b.ReleaseYear); c != 0 {
+				return c
+			}
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	default:
+		return cmp.Compare(a.ClickCount, b.ClickCount)
+	}
+}
+
+// Alan ağırlıkları PostgreSQL'deki setweight sırasını (başlık A, sanatçı B, albüm C) taklit eder.
+var fieldWeights = [3]float64{1.0, 0.4, 0.2}
+
+// matchSong, her kelimenin şarkının başlık, sanatçı veya albümündeki bir kelimenin başıyla
+// eşleşip eşleşmediğini kontrol eder; eşleşiyorsa basit bir ilgi puanı ve vurguları döndürür.
+func matchSong(song models.Song, terms []string) (float64, *models.SongHighlight, bool) {
+	fields := [3]string{song.Title, song.Artist, song.Album}
+	var marked [3]string
+	rank := 0.0
+	found := make([]bool, len(terms))
+	for i, field := range fields {
+		var hits int
+		marked[i], hits = markWords(field, terms, found)
+		rank += fieldWeights[i] * float64(hits)
+	}
+	for _, ok := range found {
+		if !ok {
+			return 0, nil, false
+		}
+	}
+	return rank, &models.SongHighlight{
+		Title:  search.Mark(marked[0]),
+		Artist: search.Mark(marked[1]),
+		Album:  search.Mark(marked[2]),
+	}, true
+}
+
+// markWords, metindeki kelimelerden başı herhangi bir arama kelimesiyle eşleşenleri işaretler
+// ve eşleşen kelime sayısını döndürür. found, eşleşen arama kelimelerini kaydeder.
+func markWords(text string, terms []string, found []bool) (string, int) {
+	runes := []rune(text)
+	folded := []rune(search.Fold(text))
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+	var b strings.Builder
+	hits := 0
+	for i := 0; i < len(runes); {
+		if !isWord(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isWord(runes[j]) {
+			j++
+		}
+		word := string(folded[i:j])
+		matched := false
+		for k, term := range terms {
+			if strings.HasPrefix(word, term) {
+				found[k] = true
+				matched = true
+			}
+		}
+		if matched {
+			hits++
+			b.WriteString(search.StartSel + string(runes[i:j]) + search.StopSel)
+		} else {
+			b.WriteString(string(runes[i:j]))
+		}
+		i = j
+	}
+	return b.String(), hits
+}
+
+// Bellek içi gerçeklemenin benzerlik eşikleri PostgreSQL gerçeklemesindekilerle aynıdır.
+const (
+	suggestThreshold    = 0.4
+	didYouMeanThreshold = 0.3
+)
+
+func (r *SongRepository) Suggest(ctx context.Context, q string, limit int) ([]models.SongSuggestion, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	type candidate struct {
+		models.SongSuggestion
+		clicks int
+	}
+	seen := make(map[string]*candidate)
+	for _, song := range r.s.songs {
+		for _, field := range [...]struct{ kind, value string }{{"title", song.Title}, {"artist", song.Artist}, {"album", song.Album}} {
+			if field.value == "" {
+				continue
+			}
+			score := search.WordSimilarity(q, field.value)
+			if score < suggestThreshold {
+				continue
+			}
+			key := field.kind + "\x00" + search.Fold(field.value)
+			if c, ok := seen[key]; ok {
+				c.clicks += song.ClickCount
+				continue
+			}
+			seen[key] = &candidate{SongSuggestion: models.SongSuggestion{Kind: field.kind, Value: field.value, Score: score}, clicks: song.ClickCount}
+		}
+	}
+
+	candidates := make([]*candidate, 0, len(seen))
+	for _, c := range seen {
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.clicks != b.clicks {
+			return a.clicks > b.clicks
+		}
+		return a.Value < b.Value
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	var suggestions []models.SongSuggestion
+	for _, c := range candidates {
+		suggestions = append(suggestions, c.SongSuggestion)
+	}
+	return suggestions, nil
+}
+
+func (r *SongRepository) DidYouMean(ctx context.Context, searchText string) (string, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	best, bestScore, bestClicks := "", 0.0, 0
+	for _, song := range r.s.songs {
+		for _, value := range [...]string{song.Title, song.Artist, song.Album} {
+			score := search.Similarity(searchText, value)
+			if score < didYouMeanThreshold {
+				continue
+			}
+			if score > bestScore || (score == bestScore && song.ClickCount > bestClicks) {
+				best, bestScore, bestClicks = value, score, song.ClickCount
+			}
+		}
+	}
+	return best, nil
+}
+
+func (r *SongRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Song, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	song, ok := r.s.songs[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &song, nil
+}
+
+func (r *SongRepository) IncrementClickCount(ctx context.Context, id uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	song, ok := r.s.songs[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	song.ClickCount++
+	r.s.songs[id] = song
+	return nil
+}
+
+func (r *SongRepository) Create(ctx context.Context, song *models.Song) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.resolveCredits(song)
+	song.ID = uuid.New()
+	song.CreatedAt = time.Now()
+	r.s.songs[song.ID] = *song
+	return nil
+}
+
+func (r *SongRepository) Update(ctx context.Context, song *models.Song) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.songs[song.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	r.s.resolveCredits(song)
+	existing.Title = song.Title
+	existing.Artist = song.Artist
+	existing.Album = song.Album
+	existing.Duration = song.Duration
+	existing.Genre = song.Genre
+	existing.ReleaseYear = song.ReleaseYear
+	existing.Artists = song.Artists
+	existing.AlbumID = song.AlbumID
+	existing.TrackNumber = song.TrackNumber
+	r.s.songs[song.ID] = existing
+	return nil
+}
+
+func (r *SongRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.songs[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.s.songs, id)
+
+	// t_playlist_songs ON DELETE CASCADE davranışını taklit et.
+	for playlistID, songIDs := range r.s.playlistSongs {
+		r.s.playlistSongs[playlistID] = removeID(songIDs, id)
+	}
+	return nil
+}
+
+// removeID, ids içindeki id'yi çıkarılmış olarak döndürür.
+func removeID(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	out := ids[:0]
+	for _, existing := range ids {
+		if existing != id {
+			out = append(out, existing)
+		}
+	}
+	return out
+}
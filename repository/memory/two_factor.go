@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorRepository, repository.TwoFactorRepository arayüzünün bellek içi gerçeklemesidir.
+type TwoFactorRepository struct {
+	s *Store
+}
+
+var _ repository.TwoFactorRepository = (*TwoFactorRepository)(nil)
+
+// recoveryCode, t_user_recovery_codes tablosundaki bir satırdır.
+type recoveryCode struct {
+	hash string
+	used bool
+}
+
+func (r *TwoFactorRepository) Get(ctx context.Context, userID uuid.UUID) (*models.TwoFactor, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	tf, ok := r.s.twoFactor[userID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &tf, nil
+}
+
+func (r *TwoFactorRepository) Begin(ctx context.Context, userID uuid.UUID, secret string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[userID]; !ok {
+		return repository.ErrNotFound
+	}
+	if existing, ok := r.s.twoFactor[userID]; ok && existing.Enabled() {
+		return repository.ErrConflict
+	}
+	r.s.twoFactor[userID] = models.TwoFactor{UserID: userID, Secret: secret, CreatedAt: time.Now()}
+	return nil
+}
+
+func (r *TwoFactorRepository) Enable(ctx context.Context, userID uuid.UUID, step int64, recoveryHashes []string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	tf, ok := r.s.twoFactor[userID]
+	if !ok || tf.Enabled() {
+		return repository.ErrNotFound
+	}
+	now := time.Now()
+	tf.EnabledAt = &now
+	tf.LastStep = step
+	r.s.twoFactor[userID] = tf
+	r.s.replaceRecoveryCodes(userID, recoveryHashes)
+	return nil
+}
+
+func (r *TwoFactorRepository) Disable(ctx context.Context, userID uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.twoFactor[userID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.s.twoFactor, userID)
+	delete(r.s.recoveryCodes, userID)
+	return nil
+}
+
+func (r *TwoFactorRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	tf, ok := r.s.twoFactor[userID]
+	if !ok || tf.LastStep >= step {
+		return repository.ErrConflict
+	}
+	tf.LastStep = step
+	r.s.twoFactor[userID] = tf
+	return nil
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, hashes []string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.replaceRecoveryCodes(userID, hashes)
+	return nil
+}
+
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	codes := r.s.recoveryCodes[userID]
+	for i := range codes {
+		if codes[i].hash == hash && !codes[i].used {
+			codes[i].used = true
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (r *TwoFactorRepository) RemainingRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	count := 0
+	for _, code := range r.s.recoveryCodes[userID] {
+		if !code.used {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// replaceRecoveryCodes, çağıranın kilidi tuttuğu varsayımıyla kullanıcının kurtarma kodlarını değiştirir.
+func (s *Store) replaceRecoveryCodes(userID uuid.UUID, hashes []string) {
+	codes := make([]recoveryCode, len(hashes))
+	for i, hash := range hashes {
+		codes[i] = recoveryCode{hash: hash}
+	}
+	s.recoveryCodes[userID] = codes
+}
@@ -0,0 +1,263 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+)
+
+// UserRepository, repository.UserRepository arayüzünün bellek içi gerçeklemesidir.
+type UserRepository struct {
+	s *Store
+}
+
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+func (r *UserRepository) List(ctx context.Context, page repository.Page[repository.UserKey]) ([]models.User, repository.PageInfo, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	users := make([]models.User, 0, len(r.s.users))
+	for _, user := range r.s.users {
+		user.Password = ""
+		users = append(users, user)
+	}
+	compare := func(user models.User, key repository.UserKey) int {
+		return cmp.Or(strings.Compare(user.Username, key.Username), bytes.Compare(user.ID[:], key.ID[:]))
+	}
+	slices.SortFunc(users, func(a, b models.User) int { return compare(a, repository.NewUserKey(&b)) })
+
+	users, info := keysetPage(users, page, compare)
+	return users, info, nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	user.Password = ""
+	return &user, nil
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, user := range r.s.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User, roleName string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	role, ok := r.s.roleByName(roleName)
+	if !ok {
+		return fmt.Errorf("%q rolü: %w", roleName, repository.ErrNotFound)
+	}
+	for _, existing := range r.s.users {
+		if existing.ID == user.ID || existing.Username == user.Username || existing.Email == user.Email {
+			return repository.ErrConflict
+		}
+	}
+
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	user.RoleID = role.ID
+	user.CreatedAt = time.Now()
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+func (r *UserRepository) UpdateProfile(ctx context.Context, id uuid.UUID, username string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	for _, existing := range r.s.users {
+		if existing.ID != id && existing.Username == username {
+			return repository.ErrConflict
+		}
+	}
+	user.Username = username
+	r.s.users[id] = user
+	return nil
+}
+
+func (r *UserRepository) UpdateAccount(ctx context.Context, id uuid.UUID, hesapTuru string, cash float64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	user.HesapTuru = hesapTuru
+	user.Cash = cash
+	r.s.users[id] = user
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.s.users, id)
+
+	// t_playlist, t_refresh_tokens, t_api_keys, t_user_tokens ve 2FA tabloları ON DELETE CASCADE, t_cupons ON DELETE SET NULL davranışlarını taklit et.
+	for playlistID, playlist := range r.s.playlists {
+		if playlist.UserID == id {
+			delete(r.s.playlists, playlistID)
+			delete(r.s.playlistSongs, playlistID)
+		}
+	}
+	for tokenID, token := range r.s.refreshTokens {
+		if token.UserID == id {
+			delete(r.s.refreshTokens, tokenID)
+		}
+	}
+	for keyID, key := range r.s.apiKeys {
+		if key.UserID == id {
+			delete(r.s.apiKeys, keyID)
+		}
+	}
+	for tokenID, token := range r.s.userTokens {
+		if token.UserID == id {
+			delete(r.s.userTokens, tokenID)
+		}
+	}
+	delete(r.s.twoFactor, id)
+	delete(r.s.recoveryCodes, id)
+	for identityID, identity := range r.s.identities {
+		if identity.UserID == id {
+			delete(r.s.identities, identityID)
+		}
+	}
+	for couponID, coupon := range r.s.coupons {
+		if coupon.UserID == id {
+			coupon.UserID = uuid.Nil
+			r.s.coupons[couponID] = coupon
+		}
+	}
+	return nil
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, id uuid.UUID, roleName string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	role, ok := r.s.roleByName(roleName)
+	if !ok {
+		return fmt.Errorf("%q rolü: %w", roleName, repository.ErrNotFound)
+	}
+	user, ok := r.s.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	user.RoleID = role.ID
+	r.s.users[id] = user
+	return nil
+}
+
+func (r *UserRepository) SetPassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	user.Password = passwordHash
+	r.s.users[id] = user
+	return nil
+}
+
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	r.s.users[id] = user
+	return nil
+}
+
+func (r *UserRepository) ChangeEmail(ctx context.Context, id uuid.UUID, email string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	for _, existing := range r.s.users {
+		if existing.ID != id && existing.Email == email {
+			return repository.ErrConflict
+		}
+	}
+	now := time.Now()
+	user.Email = email
+	user.EmailVerifiedAt = &now
+	r.s.users[id] = user
+	return nil
+}
+
+func (r *UserRepository) SetLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	user.Language = language
+	r.s.users[id] = user
+	return nil
+}
+
+func (r *UserRepository) PurchasePremium(ctx context.Context, id uuid.UUID, price float64) (float64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return 0, repository.ErrNotFound
+	}
+	if user.Cash < price {
+		return 0, repository.ErrInsufficientFunds
+	}
+	user.Cash -= price
+	user.HesapTuru = "Premium"
+	r.s.users[id] = user
+	return user.Cash, nil
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+)
+
+// UserTokenRepository, repository.UserTokenRepository arayüzünün bellek içi gerçeklemesidir.
+type UserTokenRepository struct {
+	s *Store
+}
+
+var _ repository.UserTokenRepository = (*UserTokenRepository)(nil)
+
+func (r *UserTokenRepository) Create(ctx context.Context, token *models.UserToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[token.UserID]; !ok {
+		return repository.ErrNotFound
+	}
+	for id, existing := range r.s.userTokens {
+		if existing.TokenHash == token.TokenHash {
+			return repository.ErrConflict
+		}
+		if existing.UserID == token.UserID && existing.Purpose == token.Purpose && existing.UsedAt == nil {
+			delete(r.s.userTokens, id)
+		}
+	}
+	token.ID = uuid.New()
+	token.CreatedAt = time.Now()
+	r.s.userTokens[token.ID] = *token
+	return nil
+}
+
+func (r *UserTokenRepository) Consume(ctx context.Context, hash, purpose string) (*models.UserToken, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	for id, token := range r.s.userTokens {
+		if token.TokenHash == hash && token.Purpose == purpose && token.UsedAt == nil && now.Before(token.ExpiresAt) {
+			token.UsedAt = &now
+			r.s.userTokens[id] = token
+			return &token, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+
+	"spoti/models"
+	"spoti/repository"
+	"spoti/search"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// AlbumRepository, repository.AlbumRepository arayüzünün PostgreSQL gerçeklemesidir.
+type AlbumRepository struct {
+	db *pgxpool.Pool
+}
+
+var _ repository.AlbumRepository = (*AlbumRepository)(nil)
+
+// NewAlbumRepository, verilen bağlantı havuzunu kullanan bir AlbumRepository oluşturur.
+func NewAlbumRepository(db *pgxpool.Pool) *AlbumRepository {
+	return &AlbumRepository{db: db}
+}
+
+func (r *AlbumRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Album, error) {
+	var album models.Album
+	query := `SELECT ` + albumColumns + ` FROM t_albums al JOIN t_artists a ON a.id = al.artist_id WHERE al.id = $1`
+	if err := scanAlbum(r.db.QueryRow(ctx, query, id), &album); err != nil {
+		return nil, notFound(err)
+	}
+	return &album, nil
+}
+
+func (r *AlbumRepository) Tracks(ctx context.Context, albumID uuid.UUID) ([]models.Song, error) {
+	query := `SELECT ` + songColumns + ` FROM t_songs s WHERE s.album_id = $1
+		ORDER BY s.track_number NULLS LAST, s.title, s.id`
+	rows, err := r.db.Query(ctx, query, albumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var songs []models.Song
+	for rows.Next() {
+		var song models.Song
+		if err := scanSong(rows, &song); err != nil {
+			return nil, err
+		}
+		songs = append(songs, song)
+	}
+	return songs, rows.Err()
+}
+
+func (r *AlbumRepository) Update(ctx context.Context, album *models.Album) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	album.Title = search.CleanName(album.Title)
+	commandTag, err := tx.Exec(ctx, `UPDATE t_albums SET title = $1, release_date = $2 WHERE id = $3`, album.Title, album.ReleaseDate, album.ID)
+	if err != nil {
+		return conflict(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	// Şarkıların album metni arama ve gösterim için kayıtlı adla aynı tutulur.
+	if _, err := tx.Exec(ctx, `UPDATE t_songs SET album = $1 WHERE album_id = $2`, album.Title, album.ID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
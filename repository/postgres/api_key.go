@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// APIKeyRepository, repository.APIKeyRepository arayüzünün PostgreSQL gerçeklemesidir.
+type APIKeyRepository struct {
+	db *pgxpool.Pool
+}
+
+var _ repository.APIKeyRepository = (*APIKeyRepository)(nil)
+
+// NewAPIKeyRepository, verilen bağlantı havuzunu kullanan bir APIKeyRepository oluşturur.
+func NewAPIKeyRepository(db *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	query := `INSERT INTO t_api_keys (user_id, name, prefix, key_hash, scopes) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	err := r.db.QueryRow(ctx, query, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scopes).Scan(&key.ID, &key.CreatedAt)
+	return conflict(err)
+}
+
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	rows, err := r.db.Query(ctx, `SELECT id, user_id, name, prefix, key_hash, scopes, created_at, last_used_at FROM t_api_keys WHERE user_id = $1 ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		var key models.APIKey
+		if err := rows.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &key.Scopes, &key.CreatedAt, &key.LastUsedAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	query := `SELECT id, user_id, name, prefix, key_hash, scopes, created_at, last_used_at FROM t_api_keys WHERE key_hash = $1`
+	err := r.db.QueryRow(ctx, query, hash).Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &key.Scopes, &key.CreatedAt, &key.LastUsedAt)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	commandTag, err := r.db.Exec(ctx, `DELETE FROM t_api_keys WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE t_api_keys SET last_used_at = $1 WHERE id = $2`, at, id)
+	return err
+}
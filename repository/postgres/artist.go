@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// ArtistRepository, repository.ArtistRepository arayüzünün PostgreSQL gerçeklemesidir.
+type ArtistRepository struct {
+	db *pgxpool.Pool
+}
+
+var _ repository.ArtistRepository = (*ArtistRepository)(nil)
+
+// NewArtistRepository, verilen bağlantı havuzunu kullanan bir ArtistRepository oluşturur.
+func NewArtistRepository(db *pgxpool.Pool) *ArtistRepository {
+	return &ArtistRepository{db: db}
+}
+
+func (r *ArtistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Artist, error) {
+	var artist models.Artist
+	query := `SELECT id, name, created_at FROM t_artists WHERE id = $1`
+	if err := r.db.QueryRow(ctx, query, id).Scan(&artist.ID, &artist.Name, &artist.CreatedAt); err != nil {
+		return nil, notFound(err)
+	}
+	return &artist, nil
+}
+
+func (r *ArtistRepository) Albums(ctx context.Context, artistID uuid.UUID) ([]models.Album, error) {
+	query := `SELECT ` + albumColumns + ` FROM t_albums al JOIN t_artists a ON a.id = al.artist_id
+		WHERE al.artist_id = $1
+		ORDER BY al.release_date DESC NULLS LAST, al.title, al.id`
+	rows, err := r.db.Query(ctx, query, artistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var albums []models.Album
+	for rows.Next() {
+		var album models.Album
+		if err := scanAlbum(rows, &album); err != nil {
+			return nil, err
+		}
+		albums = append(albums, album)
+	}
+	return albums, rows.Err()
+}
+
+// albumColumns, t_albums al ve t_artists a takma adlarıyla birleştirildiğinde albüm alanlarını
+// scanAlbum'un beklediği sırayla seçer.
+const albumColumns = `al.id, al.title, al.artist_id, a.name, al.release_date, al.created_at,
+	(SELECT COUNT(*) FROM t_songs s WHERE s.album_id = al.id)`
+
+// scanAlbum, albumColumns ile seçilen satırı album'e okur.
+func scanAlbum(row pgx.Row, album *models.Album) error {
+	return row.Scan(&album.ID, &album.Title, &album.ArtistID, &album.Artist, &album.ReleaseDate, &album.CreatedAt, &album.TrackCount)
+}
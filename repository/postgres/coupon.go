@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// CouponRepository, repository.CouponRepository arayüzünün PostgreSQL gerçeklemesidir.
+type CouponRepository struct {
+	db *pgxpool.Pool
+}
+
+var _ repository.CouponRepository = (*CouponRepository)(nil)
+
+// NewCouponRepository, verilen bağlantı havuzunu kullanan bir CouponRepository oluşturur.
+func NewCouponRepository(db *pgxpool.Pool) *CouponRepository {
+	return &CouponRepository{db: db}
+}
+
+func (r *CouponRepository) Create(ctx context.Context, coupon *models.Coupon) error {
+	query := `INSERT INTO t_cupons (code) VALUES ($1) RETURNING id`
+	return conflict(r.db.QueryRow(ctx, query, coupon.Code).Scan(&coupon.ID))
+}
+
+func (r *CouponRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM t_cupons WHERE id = $1)", id).Scan(&exists)
+	return exists, err
+}
+
+func (r *CouponRepository) AssignToUser(ctx context.Context, couponID, userID uuid.UUID) error {
+	commandTag, err := r.db.Exec(ctx, `UPDATE t_cupons SET user_id = $1 WHERE id = $2`, userID, couponID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *CouponRepository) AssignToAll(ctx context.Context, couponID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `UPDATE t_cupons SET user_id = u.id FROM t_users u WHERE t_cupons.id = $1`, couponID)
+	return err
+}
+
+func (r *CouponRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Coupon, error) {
+	rows, err := r.db.Query(ctx, `SELECT id, code, is_used FROM t_cupons WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var coupons []models.Coupon
+	for rows.Next() {
+		var coupon models.Coupon
+		if err := rows.Scan(&coupon.ID, &coupon.Code, &coupon.IsUsed); err != nil {
+			return nil, err
+		}
+		coupon.UserID = userID
+		coupons = append(coupons, coupon)
+	}
+	return coupons, rows.Err()
+}
+
+func (r *CouponRepository) GetForUser(ctx context.Context, couponID, userID uuid.UUID) (*models.Coupon, error) {
+	var coupon models.Coupon
+	err := r.db.QueryRow(ctx, "SELECT id, code, is_used FROM t_cupons WHERE id = $1 AND user_id = $2", couponID, userID).Scan(&coupon.ID, &coupon.Code, &coupon.IsUsed)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	coupon.UserID = userID
+	return &coupon, nil
+}
+
+func (r *CouponRepository) Redeem(ctx context.Context, couponID, userID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Kuponu yalnızca henüz kullanılmamışsa kullanıldı olarak işaretle; aynı kuponla
+	// eşzamanlı gelen ikinci istek burada hiçbir satırı güncelleyemez.
+	commandTag, err := tx.Exec(ctx, "UPDATE t_cupons SET is_used = TRUE, used_at = CURRENT_TIMESTAMP WHERE id = $1 AND user_id = $2 AND is_used = FALSE", couponID, userID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrCouponUsed
+	}
+
+	// Kullanıcının hesap türünü Premium yap
+	commandTag, err = tx.Exec(ctx, "UPDATE t_users SET hesap_turu = 'Premium' WHERE id = $1", userID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// IdentityRepository, repository.IdentityRepository arayüzünün PostgreSQL gerçeklemesidir.
+type IdentityRepository struct {
+	db *pgxpool.Pool
+}
+
+var _ repository.IdentityRepository = (*IdentityRepository)(nil)
+
+// NewIdentityRepository, verilen bağlantı havuzunu kullanan bir IdentityRepository oluşturur.
+func NewIdentityRepository(db *pgxpool.Pool) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+func (r *IdentityRepository) Get(ctx context.Context, provider, subject string) (*models.Identity, error) {
+	var identity models.Identity
+	query := `SELECT id, user_id, provider, subject, email, created_at FROM t_user_identities WHERE provider = $1 AND subject = $2`
+	err := r.db.QueryRow(ctx, query, provider, subject).Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.Email, &identity.CreatedAt)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &identity, nil
+}
+
+func (r *IdentityRepository) Create(ctx context.Context, identity *models.Identity) error {
+	return insertIdentity(ctx, r.db, identity)
+}
+
+func (r *IdentityRepository) CreateUser(ctx context.Context, user *models.User, roleName string, identity *models.Identity) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, "SELECT id FROM t_roles WHERE name = $1", roleName).Scan(&user.RoleID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%q rolü: %w", roleName, repository.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO t_users (id, username, email, password, hesap_turu, cash, role_id, email_verified_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING created_at`
+	err = tx.QueryRow(ctx, query, user.ID, user.Username, user.Email, user.Password, user.HesapTuru, user.Cash, user.RoleID, user.EmailVerifiedAt).Scan(&user.CreatedAt)
+	if err != nil {
+		return conflict(err)
+	}
+
+	identity.UserID = user.ID
+	if err := insertIdentity(ctx, tx, identity); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// querier, hem bağlantı havuzunun hem de işlemlerin sağladığı sorgu metodudur.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func insertIdentity(ctx context.Context, q querier, identity *models.Identity) error {
+	query := `INSERT INTO t_user_identities (user_id, provider, subject, email) VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	err := q.QueryRow(ctx, query, identity.UserID, identity.Provider, identity.Subject, identity.Email).Scan(&identity.ID, &identity.CreatedAt)
+	return conflict(err)
+}
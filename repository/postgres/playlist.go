@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// PlaylistRepository, repository.PlaylistRepository arayüzünün PostgreSQL gerçeklemesidir.
+type PlaylistRepository struct {
+	db *pgxpool.Pool
+}
+
+var _ repository.PlaylistRepository = (*PlaylistRepository)(nil)
+
+// NewPlaylistRepository, verilen bağlantı havuzunu kullanan bir PlaylistRepository oluşturur.
+func NewPlaylistRepository(db *pgxpool.Pool) *PlaylistRepository {
+	return &PlaylistRepository{db: db}
+}
+
+func (r *PlaylistRepository) Create(ctx context.Context, playlist *models.Playlist) error {
+	query := `INSERT INTO t_playlist (name, user_id) VALUES ($1, $2) RETURNING id`
+	return r.db.QueryRow(ctx, query, playlist.Name, playlist.UserID).Scan(&playlist.ID)
+}
+
+func (r *PlaylistRepository) ListByUser(ctx context.Context, userID uuid.UUID, page repository.Page[repository.PlaylistKey]) ([]models.Playlist, repository.PageInfo, error) {
+	query := `SELECT id, name, user_id FROM t_playlist WHERE user_id = $1`
+	args := []any{userID, page.Limit + 1}
+	order := "ASC"
+	boundary, reverse := page.Boundary()
+	if reverse {
+		order = "DESC"
+	}
+	if boundary != nil {
+		op := ">"
+		if reverse {
+			op = "<"
+		}
+		query += ` AND (name, id) ` + op + ` ($3, $4)`
+		args = append(args, boundary.Name, boundary.ID)
+	}
+	query += ` ORDER BY name ` + order + `, id ` + order + ` LIMIT $2`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	var playlists []models.Playlist
+	for rows.Next() {
+		var playlist models.Playlist
+		if err := rows.Scan(&playlist.ID, &playlist.Name, &playlist.UserID); err != nil {
+			return nil, repository.PageInfo{}, err
+		}
+		playlists = append(playlists, playlist)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+
+	playlists, info := repository.TrimPage(playlists, page)
+	if page.WithTotal {
+		var count int
+		if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM t_playlist WHERE user_id = $1`, userID).Scan(&count); err != nil {
+			return nil, repository.PageInfo{}, err
+		}
+		info.Total = &count
+	}
+	return playlists, info, nil
+}
+
+func (r *PlaylistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Playlist, error) {
+	var playlist models.Playlist
+	query := `SELECT id, name, user_id FROM t_playlist WHERE id = $1`
+	if err := r.db.QueryRow(ctx, query, id).Scan(&playlist.ID, &playlist.Name, &playlist.UserID); err != nil {
+		return nil, notFound(err)
+	}
+	return &playlist, nil
+}
+
+func (r *PlaylistRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	commandTag, err := r.db.Exec(ctx, `DELETE FROM t_playlist WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PlaylistRepository) Songs(ctx context.Context, playlistID uuid.UUID) ([]models.Song, error) {
+	query := `
+        SELECT ` + songColumns + `
+        FROM t_playlist_songs ps
+        JOIN t_songs s ON ps.song_id = s.id
+        WHERE ps.playlist_id = $1
+    `
+	rows, err := r.db.Query(ctx, query, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var songs []models.Song
+	for rows.Next() {
+		var song models.Song
+		if err := scanSong(rows, &song); err != nil {
+			return nil, err
+		}
+		songs = append(songs, song)
+	}
+	return songs, rows.Err()
+}
+
+func (r *PlaylistRepository) CountSongs(ctx context.Context, playlistID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM t_playlist_songs WHERE playlist_id = $1`, playlistID).Scan(&count)
+	return count, err
+}
+
+func (r *PlaylistRepository) AddSong(ctx context.Context, playlistID, songID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `INSERT INTO t_playlist_songs (playlist_id, song_id) VALUES ($1, $2)`, playlistID, songID)
+	return conflict(err)
+}
+
+func (r *PlaylistRepository) FindUserSong(ctx context.Context, userID, songID uuid.UUID) (*models.Song, error) {
+	var song models.Song
+	query := `
+        SELECT ` + songColumns + `
+        FROM t_playlist_songs ps
+        JOIN t_playlist p ON ps.playlist_id = p.id
+        JOIN t_songs s ON ps.song_id = s.id
+        WHERE p.user_id = $1 AND s.id = $2
+        LIMIT 1
+    `
+	err := scanSong(r.db.QueryRow(ctx, query, userID, songID), &song)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &song, nil
+}
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"errors"
+
+	"spoti/repository"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// uniqueViolation, PostgreSQL'in benzersizlik kısıtı ihlali için kullandığı SQLSTATE kodudur.
+const uniqueViolation = "23505"
+
+// notFound, pgx.ErrNoRows hatasını repository.ErrNotFound'a çevirir.
+func notFound(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+// conflict, benzersizlik kısıtı ihlallerini repository.ErrConflict'e çevirir.
+func conflict(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return repository.ErrConflict
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// RefreshTokenRepository, repository.RefreshTokenRepository arayüzünün PostgreSQL gerçeklemesidir.
+type RefreshTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+var _ repository.RefreshTokenRepository = (*RefreshTokenRepository)(nil)
+
+// NewRefreshTokenRepository, verilen bağlantı havuzunu kullanan bir RefreshTokenRepository oluşturur.
+func NewRefreshTokenRepository(db *pgxpool.Pool) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+const insertRefreshToken = `INSERT INTO t_refresh_tokens (user_id, family_id, token_hash, expires_at) VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	err := r.db.QueryRow(ctx, insertRefreshToken, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt).Scan(&token.ID, &token.CreatedAt)
+	return conflict(err)
+}
+
+func (r *RefreshTokenRepository) GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	query := `SELECT id, user_id, family_id, token_hash, expires_at, created_at, revoked_at FROM t_refresh_tokens WHERE token_hash = $1`
+	err := r.db.QueryRow(ctx, query, hash).Scan(&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt, &token.CreatedAt, &token.RevokedAt)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &token, nil
+}
+
+func (r *RefreshTokenRepository) Rotate(ctx context.Context, oldID uuid.UUID, next *models.RefreshToken) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Aynı token'la eşzamanlı gelen ikinci yenileme isteği burada hiçbir satırı güncelleyemez.
+	commandTag, err := tx.Exec(ctx, `UPDATE t_refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL`, oldID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrConflict
+	}
+
+	err = tx.QueryRow(ctx, insertRefreshToken, next.UserID, next.FamilyID, next.TokenHash, next.ExpiresAt).Scan(&next.ID, &next.CreatedAt)
+	if err != nil {
+		return conflict(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	return r.revoke(ctx, `UPDATE t_refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE family_id = $1 AND revoked_at IS NULL`, familyID)
+}
+
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	return r.revoke(ctx, `UPDATE t_refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+}
+
+func (r *RefreshTokenRepository) revoke(ctx context.Context, query string, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
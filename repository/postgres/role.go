@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// RoleRepository, repository.RoleRepository arayüzünün PostgreSQL gerçeklemesidir.
+type RoleRepository struct {
+	db *pgxpool.Pool
+}
+
+var _ repository.RoleRepository = (*RoleRepository)(nil)
+
+// NewRoleRepository, verilen bağlantı havuzunu kullanan bir RoleRepository oluşturur.
+func NewRoleRepository(db *pgxpool.Pool) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// roleSelect, rolleri izinleriyle birlikte tek satırda döndüren sorgunun başıdır.
+const roleSelect = `
+	SELECT r.id, r.name, r.built_in,
+		COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+	FROM t_roles r
+	LEFT JOIN t_role_permissions rp ON rp.role_id = r.id`
+
+func (r *RoleRepository) List(ctx context.Context) ([]models.Role, error) {
+	rows, err := r.db.Query(ctx, roleSelect+` GROUP BY r.id ORDER BY r.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []models.Role
+	for rows.Next() {
+		var role models.Role
+		if err := rows.Scan(&role.ID, &role.Name, &role.BuiltIn, &role.Permissions); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (r *RoleRepository) GetByName(ctx context.Context, name string) (*models.Role, error) {
+	var role models.Role
+	err := r.db.QueryRow(ctx, roleSelect+` WHERE r.name = $1 GROUP BY r.id`, name).Scan(&role.ID, &role.Name, &role.BuiltIn, &role.Permissions)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &role, nil
+}
+
+func (r *RoleRepository) ForUser(ctx context.Context, userID uuid.UUID) (*models.Role, error) {
+	var role models.Role
+	query := roleSelect + ` JOIN t_users u ON u.role_id = r.id WHERE u.id = $1 GROUP BY r.id`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&role.ID, &role.Name, &role.BuiltIn, &role.Permissions)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &role, nil
+}
+
+func (r *RoleRepository) Create(ctx context.Context, role *models.Role) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := tx.QueryRow(ctx, `INSERT INTO t_roles (name) VALUES ($1) RETURNING id`, role.Name).Scan(&role.ID); err != nil {
+		return conflict(err)
+	}
+	if err := replacePermissions(ctx, tx, role.ID, role.Permissions); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *RoleRepository) SetPermissions(ctx context.Context, name string, permissions []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		roleID  uuid.UUID
+		builtIn bool
+	)
+	err = tx.QueryRow(ctx, `SELECT id, built_in FROM t_roles WHERE name = $1 FOR UPDATE`, name).Scan(&roleID, &builtIn)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if builtIn {
+		return repository.ErrConflict
+	}
+	if err := replacePermissions(ctx, tx, roleID, permissions); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// replacePermissions, verilen işlem içinde rolün izinlerini yenileriyle değiştirir.
+func replacePermissions(ctx context.Context, tx pgx.Tx, roleID uuid.UUID, permissions []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM t_role_permissions WHERE role_id = $1`, roleID); err != nil {
+		return err
+	}
+	for _, permission := range permissions {
+		if _, err := tx.Exec(ctx, `INSERT INTO t_role_permissions (role_id, permission) VALUES ($1, $2)`, roleID, permission); err != nil {
+			return err
+		}
+	}
+	return nil
+}
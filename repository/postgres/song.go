@@ -0,0 +1,467 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"spoti/models"
+	"spoti/repository"
+	"spoti/search"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// SongRepository, repository.SongRepository arayüzünün PostgreSQL gerçeklemesidir.
+type SongRepository struct {
+	db *pgxpool.Pool
+}
+
+var _ repository.SongRepository = (*SongRepository)(nil)
+
+// NewSongRepository, verilen bağlantı havuzunu kullanan bir SongRepository oluşturur.
+func NewSongRepository(db *pgxpool.Pool) *SongRepository {
+	return &SongRepository{db: db}
+}
+
+// songColumns, t_songs tablosu s takma adıyla sorgulandığında şarkı alanlarını scanSong'un
+// beklediği sırayla seçer. Boş bırakılabilen metin alanları boş metne çevrilir; şarkıda adı
+// geçen sanatçılar sıralarıyla bir JSON dizisi olarak okunur.
+const songColumns = `s.id, s.title, s.artist, COALESCE(s.album, ''), s.duration, s.click_count, COALESCE(s.genre, ''), s.release_year, s.created_at,
+	s.album_id, s.track_number,
+	COALESCE((SELECT json_agg(json_build_object('id', a.id, 'name', a.name) ORDER BY sa.position)
+		FROM t_song_artists sa JOIN t_artists a ON a.id = sa.artist_id WHERE sa.song_id = s.id), '[]')`
+
+// scanSong, songColumns ile seçilen satırı song'a okur; extra, ardından seçilen sütunlardır.
+func scanSong(row pgx.Row, song *models.Song, extra ...any) error {
+	dest := append([]any{&song.ID, &song.Title, &song.Artist, &song.Album, &song.Duration, &song.ClickCount, &song.Genre, &song.ReleaseYear, &song.CreatedAt,
+		&song.AlbumID, &song.TrackNumber, &song.Artists}, extra...)
+	return row.Scan(dest...)
+}
+
+// songKeyColumns, sortBy sıralamasının anahtar ifadeleridir; satırlar bu ifadelere göre
+// sıralanır ve sayfa sınırı aynı ifadelerle karşılaştırılır. Eşit değerlerde sıranın sayfalar
+// arasında değişmemesi için anahtar her zaman id ile biter. rank, ilgi puanının ifadesidir.
+// Yayın yılı bilinmeyen şarkılar her iki yönde de sona kalsın diye yıl, yönün en sonuna
+// düşen bir değerle karşılaştırılır (NULLS LAST); böylece satır karşılaştırması da bozulmaz.
+func songKeyColumns(sortBy string, desc bool, rank string) []string {
+	switch sortBy {
+	case repository.SongSortRelevance:
+		return []string{rank, "s.click_count", "s.id"}
+	case repository.SongSortTitle:
+		return []string{"s.title", "s.id"}
+	case repository.SongSortArtist:
+		return []string{"s.artist", "s.title", "s.id"}
+	case repository.SongSortDuration:
+		return []string{"s.duration", "s.id"}
+	case repository.SongSortNewest:
+		return []string{"COALESCE(s.release_year, " + strconv.Itoa(missingYear(desc)) + ")", "s.created_at", "s.id"}
+	default:
+		return []string{"s.click_count", "s.id"}
+	}
+}
+
+// songKeyValues, anahtarın songKeyColumns ile aynı sıradaki değerleridir.
+func songKeyValues(key repository.SongKey) []any {
+	switch key.Sort {
+	case repository.SongSortRelevance:
+		// ts_rank_cd real döndürür; anahtar da real'den okunduğundan karşılaştırma kayıpsızdır.
+		return []any{float32(key.Rank), key.ClickCount, key.ID}
+	case repository.SongSortTitle:
+		return []any{key.Title, key.ID}
+	case repository.SongSortArtist:
+		return []any{key.Artist, key.Title, key.ID}
+	case repository.SongSortDuration:
+		return []any{key.Duration, key.ID}
+	case repository.SongSortNewest:
+		year := missingYear(key.Desc)
+		if key.ReleaseYear != nil {
+			year = *key.ReleaseYear
+		}
+		return []any{year, key.CreatedAt, key.ID}
+	default:
+		return []any{key.ClickCount, key.ID}
+	}
+}
+
+// missingYear, yayın yılı bilinmeyen şarkıların sıralamada yerine konduğu yıldır.
+// Tablodaki CHECK kısıtı yılı 1800-2100 aralığında tuttuğundan her iki değer de aralık dışıdır.
+// Her değerin kendi ifade indeksi vardır (idx_songs_newest, idx_songs_oldest); değerler
+// değişirse indeksler de yeni bir migration'la değiştirilmelidir.
+func missingYear(desc bool) int {
+	if desc {
+		return 0
+	}
+	return 9999
+}
+
+// orderBy, anahtar ifadelerinden verilen yönde bir ORDER BY listesi oluşturur.
+func orderBy(columns []string, desc bool) string {
+	direction := " ASC"
+	if desc {
+		direction = " DESC"
+	}
+	return strings.Join(columns, direction+", ") + direction
+}
+
+// headlineOptions, ts_headline'ın eşleşmeleri search.StartSel/StopSel ile işaretlemesini sağlar.
+// Alanlar kısa olduğundan parça seçilmez, metnin tamamı döner.
+var headlineOptions = fmt.Sprintf("StartSel=%s, StopSel=%s, HighlightAll=true", search.StartSel, search.StopSel)
+
+func (r *SongRepository) List(ctx context.Context, filter repository.SongFilter, page repository.Page[repository.SongKey]) ([]models.Song, repository.PageInfo, error) {
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	whereClause := func() string {
+		if len(where) == 0 {
+			return ""
+		}
+		return " WHERE " + strings.Join(where, " AND ")
+	}
+
+	// Arama varsa eşleşme search_vector üzerindeki GIN indeksiyle bulunur.
+	from := "t_songs s"
+	rank := "0"
+	terms := search.Terms(filter.Search)
+	if len(terms) > 0 {
+		from += ", to_tsquery('spoti_search', " + arg(search.PrefixQuery(terms)) + ") q"
+		where = append(where, "s.search_vector @@ q")
+		rank = "ts_rank_cd(s.search_vector, q)"
+	}
+	// Sanatçı, şarkıda adı geçenlerin herhangi biri olabilir. Albüm adı kayıtlı yazımıyla
+	// saklandığından spoti_fold(s.album) indeksi spoti_name_key ile de kullanılabilir.
+	if filter.Artist != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM t_song_artists sa JOIN t_artists a ON a.id = sa.artist_id
+			WHERE sa.song_id = s.id AND spoti_name_key(a.name) = spoti_name_key(`+arg(filter.Artist)+`))`)
+	}
+	if filter.Album != "" {
+		where = append(where, "spoti_fold(s.album) = spoti_name_key("+arg(filter.Album)+")")
+	}
+	if filter.Genre != "" {
+		where = append(where, "spoti_fold(s.genre) = spoti_fold("+arg(filter.Genre)+")")
+	}
+	if filter.MinDuration > 0 {
+		where = append(where, "s.duration >= "+arg(filter.MinDuration))
+	}
+	if filter.MaxDuration > 0 {
+		where = append(where, "s.duration <= "+arg(filter.MaxDuration))
+	}
+	if filter.YearFrom > 0 {
+		where = append(where, "s.release_year >= "+arg(filter.YearFrom))
+	}
+	if filter.YearTo > 0 {
+		where = append(where, "s.release_year <= "+arg(filter.YearTo))
+	}
+
+	// Toplam, sayfa sınırından bağımsız olduğundan sınır koşulu eklenmeden sayılır.
+	var total *int
+	if page.WithTotal {
+		var count int
+		if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM `+from+whereClause(), args...).Scan(&count); err != nil {
+			return nil, repository.PageInfo{}, err
+		}
+		total = &count
+	}
+
+	sortBy := filter.Sort
+	if sortBy == repository.SongSortRelevance && len(terms) == 0 {
+		sortBy = repository.SongSortPopularity
+	}
+	keyColumns := songKeyColumns(sortBy, filter.Desc, rank)
+
+	// Sınırdan sonraki satırlar anahtar ifadelerinin satır karşılaştırmasıyla seçilir; önceki
+	// sayfa istendiğinde satırlar ters yönde okunur ve TrimPage ile yeniden çevrilir.
+	desc := filter.Desc
+	boundary, reverse := page.Boundary()
+	if reverse {
+		desc = !desc
+	}
+	if boundary != nil {
+		values := songKeyValues(*boundary)
+		placeholders := make([]string, len(values))
+		for i, v := range values {
+			placeholders[i] = arg(v)
+		}
+		op := " > "
+		if desc {
+			op = " < "
+		}
+		where = append(where, "("+strings.Join(keyColumns, ", ")+")"+op+"("+strings.Join(placeholders, ", ")+")")
+	}
+
+	// Sayfa iç sorguda seçilir; vurgular yalnızca döndürülen satırlar için hesaplanır, çünkü
+	// ts_headline belgeyi yeniden ayrıştırdığından pahalıdır. Dış sorgu ilgi puanını iç
+	// sorgunun rank sütunundan okur.
+	columns := songColumns
+	qColumn := ""
+	if len(terms) > 0 {
+		h := arg(headlineOptions)
+		columns += `, s.rank, ts_headline('spoti_search', s.title, s.q, ` + h + `),
+			ts_headline('spoti_search', s.artist, s.q, ` + h + `),
+			ts_headline('spoti_search', COALESCE(s.album, ''), s.q, ` + h + `)`
+		qColumn = ", q"
+	}
+	query := `
+		SELECT ` + columns + ` FROM (
+			SELECT s.*, ` + rank + ` AS rank` + qColumn + `
+			FROM ` + from + whereClause() + `
+			ORDER BY ` + orderBy(keyColumns, desc) + `
+			LIMIT ` + arg(page.Limit+1) + `
+		) s
+		ORDER BY ` + orderBy(songKeyColumns(sortBy, filter.Desc, "s.rank"), desc)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	var songs []models.Song
+	for rows.Next() {
+		var song models.Song
+		if len(terms) == 0 {
+			if err := scanSong(rows, &song); err != nil {
+				return nil, repository.PageInfo{}, err
+			}
+			songs = append(songs, song)
+			continue
+		}
+
+		var (
+			highlight models.SongHighlight
+			score     float32
+		)
+		if err := scanSong(rows, &song, &score, &highlight.Title, &highlight.Artist, &highlight.Album); err != nil {
+			return nil, repository.PageInfo{}, err
+		}
+		song.Rank = float64(score)
+		song.Highlight = &models.SongHighlight{
+			Title:  search.Mark(highlight.Title),
+			Artist: search.Mark(highlight.Artist),
+			Album:  search.Mark(highlight.Album),
+		}
+		songs = append(songs, song)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+
+	songs, info := repository.TrimPage(songs, page)
+	info.Total = total
+	return songs, info, nil
+}
+
+// suggestThreshold, otomatik tamamlamada bir adın önerilmesi için gereken en düşük word_similarity değeridir.
+// <% operatörünün varsayılan eşiği (0.6) bir harfi eksik yazılmış adları bile elediğinden
+// eşik sorguda açıkça karşılaştırılır.
+const suggestThreshold = 0.4
+
+func (r *SongRepository) Suggest(ctx context.Context, q string, limit int) ([]models.SongSuggestion, error) {
+	// word_similarity metnin herhangi bir bölümüne benzerliğe bakar; yazılmakta olan kelimeler
+	// de bu sayede eşleşir. Eşik oturum ayarıyla değil sorgunun içinde uygulandığından her
+	// tuşlamada bir transaction açmak gerekmez. Fonksiyon biçimindeki koşul trigram
+	// indekslerini kullanmaz; sorgunun süresini SuggestTimeout sınırlar.
+	query := `
+		SELECT kind, value, score FROM (
+			SELECT 'title' AS kind, min(title) AS value, max(word_similarity($1, spoti_fold(title))) AS score, sum(click_count) AS clicks
+			FROM t_songs WHERE word_similarity($1, spoti_fold(title)) >= $3 GROUP BY spoti_fold(title)
+			UNION ALL
+			SELECT 'artist', min(artist), max(word_similarity($1, spoti_fold(artist))), sum(click_count)
+			FROM t_songs WHERE word_similarity($1, spoti_fold(artist)) >= $3 GROUP BY spoti_fold(artist)
+			UNION ALL
+			SELECT 'album', min(album), max(word_similarity($1, spoti_fold(album))), sum(click_count)
+			FROM t_songs WHERE word_similarity($1, spoti_fold(album)) >= $3 GROUP BY spoti_fold(album)
+		) s
+		ORDER BY score DESC, clicks DESC, value
+		LIMIT $2`
+	rows, err := r.db.Query(ctx, query, search.Fold(q), limit, suggestThreshold)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suggestions []models.SongSuggestion
+	for rows.Next() {
+		var (
+			suggestion models.SongSuggestion
+			score      float32
+		)
+		if err := rows.Scan(&suggestion.Kind, &suggestion.Value, &score); err != nil {
+			return nil, err
+		}
+		suggestion.Score = float64(score)
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, rows.Err()
+}
+
+func (r *SongRepository) DidYouMean(ctx context.Context, searchText string) (string, error) {
+	// % (similarity) metnin tamamına benzerliğe bakar; yanlış yazılmış bir adın tamamı aranır.
+	query := `
+		SELECT value FROM (
+			SELECT title AS value, similarity($1, spoti_fold(title)) AS score, click_count FROM t_songs WHERE spoti_fold(title) % $1
+			UNION ALL
+			SELECT artist, similarity($1, spoti_fold(artist)), click_count FROM t_songs WHERE spoti_fold(artist) % $1
+			UNION ALL
+			SELECT album, similarity($1, spoti_fold(album)), click_count FROM t_songs WHERE spoti_fold(album) % $1
+		) s
+		ORDER BY score DESC, click_count DESC
+		LIMIT 1`
+	var value string
+	err := r.db.QueryRow(ctx, query, search.Fold(searchText)).Scan(&value)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return value, err
+}
+
+func (r *SongRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Song, error) {
+	var song models.Song
+	query := `SELECT ` + songColumns + ` FROM t_songs s WHERE s.id = $1`
+	err := scanSong(r.db.QueryRow(ctx, query, id), &song)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &song, nil
+}
+
+func (r *SongRepository) IncrementClickCount(ctx context.Context, id uuid.UUID) error {
+	commandTag, err := r.db.Exec(ctx, `UPDATE t_songs SET click_count = click_count + 1 WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SongRepository) Create(ctx context.Context, song *models.Song) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := resolveCredits(ctx, tx, song); err != nil {
+		return err
+	}
+	query := `INSERT INTO t_songs (title, artist, album, duration, genre, release_year, album_id, track_number)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8) RETURNING id, created_at`
+	err = tx.QueryRow(ctx, query, song.Title, song.Artist, song.Album, song.Duration, song.Genre, song.ReleaseYear, song.AlbumID, song.TrackNumber).Scan(&song.ID, &song.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if err := setSongArtists(ctx, tx, song); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *SongRepository) Update(ctx context.Context, song *models.Song) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := resolveCredits(ctx, tx, song); err != nil {
+		return err
+	}
+	query := `UPDATE t_songs SET title = $1, artist = $2, album = $3, duration = $4, genre = NULLIF($5, ''), release_year = $6, album_id = $7, track_number = $8 WHERE id = $9`
+	commandTag, err := tx.Exec(ctx, query, song.Title, song.Artist, song.Album, song.Duration, song.Genre, song.ReleaseYear, song.AlbumID, song.TrackNumber, song.ID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	if err := setSongArtists(ctx, tx, song); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// resolveCredits, şarkıda adı geçen sanatçıları ve albümü adlarıyla t_artists ve t_albums
+// kayıtlarına çözer; olmayanları oluşturur. Aynı anahtara sahip bir kayıt varsa onun adı
+// kullanılır. ON CONFLICT ... DO UPDATE, eşzamanlı eklemelerde de var olan kaydı döndürür.
+func resolveCredits(ctx context.Context, tx pgx.Tx, song *models.Song) error {
+	var names []string
+	for _, artist := range song.Artists {
+		names = append(names, artist.Name)
+	}
+	if len(names) == 0 {
+		names = []string{song.Artist}
+	}
+
+	var artists []models.SongArtist
+	for _, name := range names {
+		name = search.CleanName(name)
+		if name == "" {
+			continue
+		}
+		var artist models.SongArtist
+		query := `INSERT INTO t_artists (name) VALUES ($1)
+			ON CONFLICT ((spoti_name_key(name))) DO UPDATE SET name = t_artists.name
+			RETURNING id, name`
+		if err := tx.QueryRow(ctx, query, name).Scan(&artist.ID, &artist.Name); err != nil {
+			return err
+		}
+		// Farklı yazılmış aynı sanatçı bir kez sayılır.
+		if !slices.ContainsFunc(artists, func(a models.SongArtist) bool { return a.ID == artist.ID }) {
+			artists = append(artists, artist)
+		}
+	}
+	song.Artists = artists
+	song.Artist = models.ArtistCredit(artists)
+
+	song.Album = search.CleanName(song.Album)
+	song.AlbumID = nil
+	if song.Album == "" || len(artists) == 0 {
+		return nil
+	}
+	var albumID uuid.UUID
+	query := `INSERT INTO t_albums (artist_id, title) VALUES ($1, $2)
+		ON CONFLICT (artist_id, (spoti_name_key(title))) DO UPDATE SET title = t_albums.title
+		RETURNING id, title`
+	if err := tx.QueryRow(ctx, query, artists[0].ID, song.Album).Scan(&albumID, &song.Album); err != nil {
+		return err
+	}
+	song.AlbumID = &albumID
+	return nil
+}
+
+// setSongArtists, şarkının t_song_artists kayıtlarını song.Artists ile değiştirir.
+func setSongArtists(ctx context.Context, tx pgx.Tx, song *models.Song) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM t_song_artists WHERE song_id = $1`, song.ID); err != nil {
+		return err
+	}
+	for i, artist := range song.Artists {
+		query := `INSERT INTO t_song_artists (song_id, artist_id, position) VALUES ($1, $2, $3)`
+		if _, err := tx.Exec(ctx, query, song.ID, artist.ID, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SongRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	commandTag, err := r.db.Exec(ctx, `DELETE FROM t_songs WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
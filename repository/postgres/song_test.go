@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+
+	"spoti/migrations"
+	"spoti/repository"
+)
+
+// TestNewestSortIndexes, yeniliğe göre sıralamanın her iki yönündeki anahtar ifadesinin bir
+// migration'daki ifade indeksiyle birebir aynı olduğunu doğrular. İfadeler farklı olursa
+// PostgreSQL indeksi kullanmaz ve sayfalar tablo taranarak sıralanır.
+func TestNewestSortIndexes(t *testing.T) {
+	files, err := fs.Glob(migrations.FS, "*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema strings.Builder
+	for _, name := range files {
+		data, err := fs.ReadFile(migrations.FS, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Yalnızca Up bölümleri şemayı oluşturur.
+		up, _, _ := strings.Cut(string(data), "-- +goose Down")
+		schema.WriteString(up)
+	}
+
+	for _, desc := range []bool{true, false} {
+		columns := songKeyColumns(repository.SongSortNewest, desc, "")
+		if !slices.Equal(columns[1:], []string{"s.created_at", "s.id"}) {
+			t.Fatalf("beklenmeyen sıralama anahtarı: %v", columns)
+		}
+		index := "ON t_songs ((" + strings.Replace(columns[0], "s.", "", 1) + "), created_at, id)"
+		if !strings.Contains(schema.String(), index) {
+			t.Errorf("desc=%v için %s anahtarına uyan bir indeks yok (missingYear = %d)", desc, columns[0], missingYear(desc))
+		}
+	}
+}
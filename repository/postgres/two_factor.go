@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// TwoFactorRepository, repository.TwoFactorRepository arayüzünün PostgreSQL gerçeklemesidir.
+type TwoFactorRepository struct {
+	db *pgxpool.Pool
+}
+
+var _ repository.TwoFactorRepository = (*TwoFactorRepository)(nil)
+
+// NewTwoFactorRepository, verilen bağlantı havuzunu kullanan bir TwoFactorRepository oluşturur.
+func NewTwoFactorRepository(db *pgxpool.Pool) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+func (r *TwoFactorRepository) Get(ctx context.Context, userID uuid.UUID) (*models.TwoFactor, error) {
+	var tf models.TwoFactor
+	query := `SELECT user_id, secret, enabled_at, last_step, created_at FROM t_user_totp WHERE user_id = $1`
+	err := r.db.QueryRow(ctx, query, userID).Scan(&tf.UserID, &tf.Secret, &tf.EnabledAt, &tf.LastStep, &tf.CreatedAt)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &tf, nil
+}
+
+func (r *TwoFactorRepository) Begin(ctx context.Context, userID uuid.UUID, secret string) error {
+	// Etkin bir kurulumun üzerine yazılmaz; WHERE koşulu sağlanmazsa hiçbir satır dönmez.
+	query := `INSERT INTO t_user_totp (user_id, secret) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_step = 0, created_at = CURRENT_TIMESTAMP
+		WHERE t_user_totp.enabled_at IS NULL
+		RETURNING user_id`
+	var id uuid.UUID
+	err := r.db.QueryRow(ctx, query, userID, secret).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrConflict
+	}
+	return err
+}
+
+func (r *TwoFactorRepository) Enable(ctx context.Context, userID uuid.UUID, step int64, recoveryHashes []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, `UPDATE t_user_totp SET enabled_at = CURRENT_TIMESTAMP, last_step = $2 WHERE user_id = $1 AND enabled_at IS NULL`, userID, step)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	if err := replaceRecoveryCodes(ctx, tx, userID, recoveryHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TwoFactorRepository) Disable(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	commandTag, err := tx.Exec(ctx, `DELETE FROM t_user_totp WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM t_user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *TwoFactorRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	// Aynı kodla eşzamanlı gelen ikinci istek burada hiçbir satırı güncelleyemez.
+	commandTag, err := r.db.Exec(ctx, `UPDATE t_user_totp SET last_step = $2 WHERE user_id = $1 AND last_step < $2`, userID, step)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrConflict
+	}
+	return nil
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, hashes []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, hashes); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string) error {
+	commandTag, err := r.db.Exec(ctx, `UPDATE t_user_recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`, userID, hash)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TwoFactorRepository) RemainingRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM t_user_recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+// replaceRecoveryCodes, verilen işlem içinde kullanıcının kurtarma kodlarını yenileriyle değiştirir.
+func replaceRecoveryCodes(ctx context.Context, tx pgx.Tx, userID uuid.UUID, hashes []string) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM t_user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, hash := range hashes {
+		if _, err := tx.Exec(ctx, `INSERT INTO t_user_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// UserRepository, repository.UserRepository arayüzünün PostgreSQL gerçeklemesidir.
+type UserRepository struct {
+	db *pgxpool.Pool
+}
+
+var _ repository.UserRepository = (*UserRepository)(nil)
+
+// NewUserRepository, verilen bağlantı havuzunu kullanan bir UserRepository oluşturur.
+func NewUserRepository(db *pgxpool.Pool) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) List(ctx context.Context, page repository.Page[repository.UserKey]) ([]models.User, repository.PageInfo, error) {
+	// Kullanıcı adı benzersiz olduğundan sınır, username üzerindeki UNIQUE indeksiyle bulunur.
+	query := `SELECT id, username, email, role_id, hesap_turu, cash, COALESCE(language, ''), created_at, email_verified_at FROM t_users`
+	args := []any{page.Limit + 1}
+	order := "ASC"
+	boundary, reverse := page.Boundary()
+	if reverse {
+		order = "DESC"
+	}
+	if boundary != nil {
+		op := ">"
+		if reverse {
+			op = "<"
+		}
+		query += ` WHERE (username, id) ` + op + ` ($2, $3)`
+		args = append(args, boundary.Username, boundary.ID)
+	}
+	query += ` ORDER BY username ` + order + `, id ` + order + ` LIMIT $1`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.RoleID, &user.HesapTuru, &user.Cash, &user.Language, &user.CreatedAt, &user.EmailVerifiedAt); err != nil {
+			return nil, repository.PageInfo{}, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+
+	users, info := repository.TrimPage(users, page)
+	if page.WithTotal {
+		var count int
+		if err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM t_users`).Scan(&count); err != nil {
+			return nil, repository.PageInfo{}, err
+		}
+		info.Total = &count
+	}
+	return users, info, nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	query := `SELECT id, username, email, role_id, hesap_turu, cash, COALESCE(language, ''), created_at, email_verified_at FROM t_users WHERE id = $1`
+	err := r.db.QueryRow(ctx, query, id).Scan(&user.ID, &user.Username, &user.Email, &user.RoleID, &user.HesapTuru, &user.Cash, &user.Language, &user.CreatedAt, &user.EmailVerifiedAt)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	query := `SELECT id, username, email, password, role_id, hesap_turu, cash, COALESCE(language, ''), created_at, email_verified_at FROM t_users WHERE email = $1`
+	err := r.db.QueryRow(ctx, query, email).Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.RoleID, &user.HesapTuru, &user.Cash, &user.Language, &user.CreatedAt, &user.EmailVerifiedAt)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User, roleName string) error {
+	err := r.db.QueryRow(ctx, "SELECT id FROM t_roles WHERE name = $1", roleName).Scan(&user.RoleID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%q rolü: %w", roleName, repository.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO t_users (id, username, email, password, hesap_turu, cash, role_id) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING created_at`
+	err = r.db.QueryRow(ctx, query, user.ID, user.Username, user.Email, user.Password, user.HesapTuru, user.Cash, user.RoleID).Scan(&user.CreatedAt)
+	return conflict(err)
+}
+
+func (r *UserRepository) UpdateProfile(ctx context.Context, id uuid.UUID, username string) error {
+	commandTag, err := r.db.Exec(ctx, `UPDATE t_users SET username = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`, username, id)
+	if err != nil {
+		return conflict(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdateAccount(ctx context.Context, id uuid.UUID, hesapTuru string, cash float64) error {
+	commandTag, err := r.db.Exec(ctx, `UPDATE t_users SET hesap_turu = $1, cash = $2 WHERE id = $3`, hesapTuru, cash, id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	commandTag, err := r.db.Exec(ctx, `DELETE FROM t_users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, id uuid.UUID, roleName string) error {
+	var roleID uuid.UUID
+	err := r.db.QueryRow(ctx, "SELECT id FROM t_roles WHERE name = $1", roleName).Scan(&roleID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%q rolü: %w", roleName, repository.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+
+	commandTag, err := r.db.Exec(ctx, `UPDATE t_users SET role_id = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`, roleID, id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) SetPassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	commandTag, err := r.db.Exec(ctx, `UPDATE t_users SET password = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`, passwordHash, id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	commandTag, err := r.db.Exec(ctx, `UPDATE t_users SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) ChangeEmail(ctx context.Context, id uuid.UUID, email string) error {
+	commandTag, err := r.db.Exec(ctx, `UPDATE t_users SET email = $1, email_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = $2`, email, id)
+	if err != nil {
+		return conflict(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) SetLanguage(ctx context.Context, id uuid.UUID, language string) error {
+	commandTag, err := r.db.Exec(ctx, `UPDATE t_users SET language = NULLIF($1, ''), updated_at = CURRENT_TIMESTAMP WHERE id = $2`, language, id)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) PurchasePremium(ctx context.Context, id uuid.UUID, price float64) (float64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Aynı anda gelen iki satın alma isteğinin bakiyeyi iki kez düşmemesi için satırı kilitle.
+	var cash float64
+	if err := tx.QueryRow(ctx, "SELECT cash FROM t_users WHERE id = $1 FOR UPDATE", id).Scan(&cash); err != nil {
+		return 0, notFound(err)
+	}
+	if cash < price {
+		return 0, repository.ErrInsufficientFunds
+	}
+
+	newCash := cash - price
+	if _, err := tx.Exec(ctx, "UPDATE t_users SET cash = $1, hesap_turu = 'Premium' WHERE id = $2", newCash, id); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return newCash, nil
+}
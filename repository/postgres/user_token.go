@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+
+	"spoti/models"
+	"spoti/repository"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// UserTokenRepository, repository.UserTokenRepository arayüzünün PostgreSQL gerçeklemesidir.
+type UserTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+var _ repository.UserTokenRepository = (*UserTokenRepository)(nil)
+
+// NewUserTokenRepository, verilen bağlantı havuzunu kullanan bir UserTokenRepository oluşturur.
+func NewUserTokenRepository(db *pgxpool.Pool) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+func (r *UserTokenRepository) Create(ctx context.Context, token *models.UserToken) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM t_user_tokens WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`, token.UserID, token.Purpose); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO t_user_tokens (user_id, purpose, token_hash, expires_at, new_email) VALUES ($1, $2, $3, $4, NULLIF($5, '')) RETURNING id, created_at`
+	if err := tx.QueryRow(ctx, query, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.NewEmail).Scan(&token.ID, &token.CreatedAt); err != nil {
+		return conflict(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *UserTokenRepository) Consume(ctx context.Context, hash, purpose string) (*models.UserToken, error) {
+	var token models.UserToken
+	// Aynı token'la eşzamanlı gelen ikinci istek burada hiçbir satırı güncelleyemez.
+	query := `UPDATE t_user_tokens SET used_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING id, user_id, purpose, token_hash, expires_at, created_at, used_at, COALESCE(new_email, '')`
+	err := r.db.QueryRow(ctx, query, hash, purpose).Scan(&token.ID, &token.UserID, &token.Purpose, &token.TokenHash, &token.ExpiresAt, &token.CreatedAt, &token.UsedAt, &token.NewEmail)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &token, nil
+}
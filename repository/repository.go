@@ -0,0 +1,336 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"spoti/models"
+
+	"github.com/google/uuid"
+)
+
+// Repository katmanının döndürdüğü ortak hatalar.
+// Handler'lar bu hataları errors.Is ile kontrol ederek HTTP durum kodunu belirler.
+var (
+	ErrNotFound          = errors.New("kayıt bulunamadı")
+	ErrConflict          = errors.New("kayıt zaten mevcut")
+	ErrInsufficientFunds = errors.New("yetersiz bakiye")
+	ErrCouponUsed        = errors.New("kupon zaten kullanılmış")
+)
+
+// Page, anahtar tabanlı (keyset) sayfalama isteğidir. Satırlar OFFSET ile atlanmaz; önceki
+// sayfanın sınırındaki satırın sıralama anahtarından devam edilir. Böylece derin sayfalar da
+// indeksle okunur ve araya eklenen satırlar sayfaların kaymasına yol açmaz.
+type Page[K any] struct {
+	// Limit, döndürülecek en fazla satır sayısıdır.
+	Limit int
+	// After verilmişse anahtarı ondan sonra, Before verilmişse ondan önce gelen satırlar döner;
+	// ikisi birlikte verilmez. İkisi de nil ise ilk sayfa döner.
+	After  *K
+	Before *K
+	// WithTotal, filtreye uyan toplam satır sayısının da hesaplanmasını ister.
+	WithTotal bool
+}
+
+// Boundary, sayfanın sınır anahtarını ve satırların sıralamanın tersi yönünde okunup
+// okunmayacağını döndürür. İlk sayfada anahtar nil'dir.
+func (p Page[K]) Boundary() (key *K, reverse bool) {
+	if p.Before != nil {
+		return p.Before, true
+	}
+	return p.After, false
+}
+
+// PageInfo, dönen sayfanın öncesinde ve sonrasında satır bulunup bulunmadığını bildirir.
+type PageInfo struct {
+	HasPrev bool
+	HasNext bool
+	// Total, yalnızca Page.WithTotal istendiğinde doldurulur.
+	Total *int
+}
+
+// TrimPage, sınırdan itibaren okunma yönünde alınmış en fazla Limit+1 satırı sayfaya çevirir.
+// Fazladan okunan satır yalnızca o yönde devamı olduğunu gösterir ve atılır; Before
+// isteklerinde ters sırada okunan satırlar sıralama yönüne döndürülür. Sınırın diğer
+// tarafında, sınırı oluşturan satır bulunduğundan devamı olduğu kabul edilir.
+func TrimPage[T, K any](rows []T, page Page[K]) ([]T, PageInfo) {
+	more := len(rows) > page.Limit
+	if more {
+		rows = rows[:page.Limit]
+	}
+	if page.Before != nil {
+		slices.Reverse(rows)
+		return rows, PageInfo{HasPrev: more, HasNext: true}
+	}
+	return rows, PageInfo{HasPrev: page.After != nil, HasNext: more}
+}
+
+// UserKey, kullanıcının listedeki konumudur; kullanıcılar kullanıcı adına göre sıralanır.
+type UserKey struct {
+	Username string    `json:"u"`
+	ID       uuid.UUID `json:"id"`
+}
+
+// NewUserKey, kullanıcının listedeki anahtarını döndürür.
+func NewUserKey(user *models.User) UserKey {
+	return UserKey{Username: user.Username, ID: user.ID}
+}
+
+// PlaylistKey, çalma listesinin listedeki konumudur; listeler ada göre sıralanır.
+type PlaylistKey struct {
+	Name string    `json:"n"`
+	ID   uuid.UUID `json:"id"`
+}
+
+// NewPlaylistKey, çalma listesinin listedeki anahtarını döndürür.
+func NewPlaylistKey(playlist *models.Playlist) PlaylistKey {
+	return PlaylistKey{Name: playlist.Name, ID: playlist.ID}
+}
+
+// UserRepository, t_users tablosuna erişimi soyutlar. Roller ve izinler RoleRepository üzerinden okunur.
+type UserRepository interface {
+	// List, kullanıcıları kullanıcı adına göre sıralayarak page'in istediği sayfayı döndürür.
+	List(ctx context.Context, page Page[UserKey]) ([]models.User, PageInfo, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	// GetByEmail, giriş kontrolü için şifre hash'i dahil kullanıcıyı döndürür.
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	// Create, kullanıcıyı verilen rol adıyla kaydeder ve user.RoleID alanını doldurur.
+	Create(ctx context.Context, user *models.User, roleName string) error
+	// UpdateProfile, kullanıcının kendi değiştirebileceği alanları günceller.
+	// Kullanıcı adı başka bir kullanıcıda varsa ErrConflict döner.
+	UpdateProfile(ctx context.Context, id uuid.UUID, username string) error
+	// UpdateAccount, hesap türünü ve bakiyeyi değiştirir; yalnızca admin akışlarında kullanılır.
+	UpdateAccount(ctx context.Context, id uuid.UUID, hesapTuru string, cash float64) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// SetRole, kullanıcının rolünü verilen rol adıyla değiştirir.
+	SetRole(ctx context.Context, id uuid.UUID, roleName string) error
+	// SetPassword, kullanıcının şifre hash'ini değiştirir.
+	SetPassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	// MarkEmailVerified, kullanıcının e-posta adresini doğrulanmış olarak işaretler.
+	// Adres zaten doğrulanmışsa ilk doğrulama zamanı korunur.
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+	// ChangeEmail, kullanıcının e-posta adresini değiştirir ve yeni adresi doğrulanmış olarak
+	// işaretler. Adres başka bir kullanıcıda varsa ErrConflict döner.
+	ChangeEmail(ctx context.Context, id uuid.UUID, email string) error
+	// SetLanguage, kullanıcının dil tercihini değiştirir; boş dil tercihi kaldırır.
+	SetLanguage(ctx context.Context, id uuid.UUID, language string) error
+	// PurchasePremium, bakiyeden price kadar düşerek hesabı Premium yapar ve yeni bakiyeyi döndürür.
+	PurchasePremium(ctx context.Context, id uuid.UUID, price float64) (float64, error)
+}
+
+// RoleRepository, t_roles, t_permissions ve t_role_permissions tablolarına erişimi soyutlar.
+// Döndürülen roller izinleriyle birlikte gelir.
+type RoleRepository interface {
+	List(ctx context.Context) ([]models.Role, error)
+	GetByName(ctx context.Context, name string) (*models.Role, error)
+	// ForUser, kullanıcının rolünü izinleriyle döndürür; kullanıcı yoksa ErrNotFound döner.
+	ForUser(ctx context.Context, userID uuid.UUID) (*models.Role, error)
+	// Create, rolü izinleriyle kaydeder ve role.ID alanını doldurur. Aynı adda bir rol
+	// varsa ErrConflict döner.
+	Create(ctx context.Context, role *models.Role) error
+	// SetPermissions, rolün izinlerini verilenlerle değiştirir. Rol yoksa ErrNotFound,
+	// yerleşik bir rolse ErrConflict döner.
+	SetPermissions(ctx context.Context, name string, permissions []string) error
+}
+
+// Şarkı listesinin sıralanabileceği alanlar.
+const (
+	SongSortRelevance  = "relevance"
+	SongSortTitle      = "title"
+	SongSortArtist     = "artist"
+	SongSortDuration   = "duration"
+	SongSortPopularity = "popularity"
+	SongSortNewest     = "newest"
+)
+
+// SongFilter, şarkı listeleme sorgusunun parametrelerini tutar.
+type SongFilter struct {
+	// Search boş değilse başlık, sanatçı ve albümde kelime başı eşleşmesiyle arama yapılır.
+	Search string
+	// Artist, Album ve Genre verilmişse büyük/küçük harf ve aksan farkı gözetilmeden tam eşleşme
+	// aranır. Artist, şarkıda adı geçen sanatçılardan herhangi biriyle eşleşir.
+	Artist string
+	Album  string
+	Genre  string
+	// MinDuration ve MaxDuration saniye, YearFrom ve YearTo yayın yılı olarak sınırları dahil
+	// aralıklardır. Sıfır değerler sınır koymaz.
+	MinDuration int
+	MaxDuration int
+	YearFrom    int
+	YearTo      int
+	// Sort, SongSort* sabitlerinden biridir; Desc azalan sıralama ister. Eşit değerler her
+	// zaman aynı sırada döner. SongSortRelevance yalnızca Search ile anlamlıdır.
+	Sort string
+	Desc bool
+}
+
+// SongKey, şarkının bir sıralamadaki konumudur. Yalnızca Sort'un kullandığı alanlar doldurulur;
+// Sort ve Desc, anahtarın başka bir sıralamayla kullanılmasını önlemek için saklanır.
+type SongKey struct {
+	Sort        string    `json:"s"`
+	Desc        bool      `json:"d,omitempty"`
+	Rank        float64   `json:"r,omitempty"`
+	Title       string    `json:"t,omitempty"`
+	Artist      string    `json:"a,omitempty"`
+	Duration    int       `json:"du,omitempty"`
+	ClickCount  int       `json:"c,omitempty"`
+	ReleaseYear *int      `json:"y,omitempty"`
+	CreatedAt   time.Time `json:"ca,omitzero"`
+	ID          uuid.UUID `json:"id"`
+}
+
+// NewSongKey, şarkının sortBy sıralamasındaki anahtarını döndürür.
+func NewSongKey(song *models.Song, sortBy string, desc bool) SongKey {
+	key := SongKey{Sort: sortBy, Desc: desc, ID: song.ID}
+	switch sortBy {
+	case SongSortRelevance:
+		key.Rank, key.ClickCount = song.Rank, song.ClickCount
+	case SongSortTitle:
+		key.Title = song.Title
+	case SongSortArtist:
+		key.Artist, key.Title = song.Artist, song.Title
+	case SongSortDuration:
+		key.Duration = song.Duration
+	case SongSortNewest:
+		key.ReleaseYear, key.CreatedAt = song.ReleaseYear, song.CreatedAt
+	default:
+		key.ClickCount = song.ClickCount
+	}
+	return key
+}
+
+// SongRepository, t_songs tablosuna erişimi soyutlar.
+type SongRepository interface {
+	// List, filtreye uyan şarkılardan page'in istediği sayfayı döndürür. Sıralama filtre ile
+	// belirlenir; sınır anahtarı aynı Sort ve Desc ile üretilmiş olmalıdır.
+	List(ctx context.Context, filter SongFilter, page Page[SongKey]) ([]models.Song, PageInfo, error)
+	// Suggest, q'ya trigram benzerliğiyle yakın başlık, sanatçı ve albüm adlarını en benzerden
+	// başlayarak en fazla limit kadar döndürür. Aynı ad bir kez önerilir.
+	Suggest(ctx context.Context, q string, limit int) ([]models.SongSuggestion, error)
+	// DidYouMean, sonuç bulunamayan bir arama için en benzer başlık, sanatçı veya albüm adını
+	// döndürür; yeterince benzer bir ad yoksa boş metin döner.
+	DidYouMean(ctx context.Context, search string) (string, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Song, error)
+	IncrementClickCount(ctx context.Context, id uuid.UUID) error
+	// Create ve Update, şarkıda adı geçen sanatçıları (Artists boşsa Artist'i) ve albümü adlarıyla
+	// t_artists ve t_albums kayıtlarına çözer; olmayanları oluşturur. Albüm ilk sanatçıya aittir.
+	// Şarkının Artists, Artist, AlbumID ve Album alanları kayıtlı adlarla doldurulur.
+	Create(ctx context.Context, song *models.Song) error
+	Update(ctx context.Context, song *models.Song) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// ArtistRepository, t_artists tablosuna erişimi soyutlar. Sanatçılar şarkılar kaydedilirken
+// SongRepository tarafından oluşturulur.
+type ArtistRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Artist, error)
+	// Albums, sanatçının albümlerini en yeniden başlayarak döndürür; yayın tarihi bilinmeyenler sona kalır.
+	Albums(ctx context.Context, artistID uuid.UUID) ([]models.Album, error)
+}
+
+// AlbumRepository, t_albums tablosuna erişimi soyutlar. Albümler şarkılar kaydedilirken
+// SongRepository tarafından oluşturulur.
+type AlbumRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Album, error)
+	// Tracks, albümün şarkılarını parça numarasına göre döndürür; numarası bilinmeyenler sona kalır.
+	Tracks(ctx context.Context, albumID uuid.UUID) ([]models.Song, error)
+	// Update, albümün adını ve yayın tarihini değiştirir; şarkıların album metni de güncellenir.
+	// Sanatçının aynı adda başka bir albümü varsa ErrConflict döner.
+	Update(ctx context.Context, album *models.Album) error
+}
+
+// PlaylistRepository, t_playlist ve t_playlist_songs tablolarına erişimi soyutlar.
+type PlaylistRepository interface {
+	Create(ctx context.Context, playlist *models.Playlist) error
+	// ListByUser, kullanıcının çalma listelerini ada göre sıralayarak page'in istediği sayfayı döndürür.
+	ListByUser(ctx context.Context, userID uuid.UUID, page Page[PlaylistKey]) ([]models.Playlist, PageInfo, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Playlist, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Songs(ctx context.Context, playlistID uuid.UUID) ([]models.Song, error)
+	CountSongs(ctx context.Context, playlistID uuid.UUID) (int, error)
+	AddSong(ctx context.Context, playlistID, songID uuid.UUID) error
+	// FindUserSong, kullanıcının herhangi bir çalma listesinde bulunan şarkıyı döndürür.
+	FindUserSong(ctx context.Context, userID, songID uuid.UUID) (*models.Song, error)
+}
+
+// CouponRepository, t_cupons tablosuna erişimi soyutlar.
+type CouponRepository interface {
+	// Create, kuponu kaydeder; aynı kodda bir kupon varsa ErrConflict döner.
+	Create(ctx context.Context, coupon *models.Coupon) error
+	Exists(ctx context.Context, id uuid.UUID) (bool, error)
+	AssignToUser(ctx context.Context, couponID, userID uuid.UUID) error
+	AssignToAll(ctx context.Context, couponID uuid.UUID) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Coupon, error)
+	GetForUser(ctx context.Context, couponID, userID uuid.UUID) (*models.Coupon, error)
+	// Redeem, kuponu kullanıldı olarak işaretler ve kullanıcıyı tek bir işlemde Premium yapar.
+	Redeem(ctx context.Context, couponID, userID uuid.UUID) error
+}
+
+// RefreshTokenRepository, t_refresh_tokens tablosuna erişimi soyutlar.
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	// Rotate, eski token'ı iptal eder ve aynı işlemde yerine next'i kaydeder.
+	// Eski token daha önce iptal edilmişse hiçbir şey yapmaz ve ErrConflict döner.
+	Rotate(ctx context.Context, oldID uuid.UUID, next *models.RefreshToken) error
+	// RevokeFamily, aynı girişten türeyen tüm token'ları iptal eder.
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	// RevokeAllForUser, kullanıcının tüm yenileme token'larını iptal eder.
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+}
+
+// APIKeyRepository, t_api_keys tablosuna erişimi soyutlar.
+type APIKeyRepository interface {
+	// Create, anahtarı kaydeder; kullanıcının aynı adda bir anahtarı varsa ErrConflict döner.
+	Create(ctx context.Context, key *models.APIKey) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error)
+	GetByHash(ctx context.Context, hash string) (*models.APIKey, error)
+	// Delete, kullanıcıya ait anahtarı siler; anahtar başka bir kullanıcınınsa ErrNotFound döner.
+	Delete(ctx context.Context, id, userID uuid.UUID) error
+	// TouchLastUsed, anahtarın son kullanılma zamanını günceller.
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+// UserTokenRepository, t_user_tokens tablosuna erişimi soyutlar.
+type UserTokenRepository interface {
+	// Create, token'ı kaydeder ve kullanıcının aynı amaçla verilmiş kullanılmamış
+	// token'larını siler; böylece yalnızca en son gönderilen bağlantı geçerli kalır.
+	Create(ctx context.Context, token *models.UserToken) error
+	// Consume, süresi dolmamış ve kullanılmamış token'ı tek bir işlemde kullanıldı olarak
+	// işaretler ve döndürür. Böyle bir token yoksa ErrNotFound döner.
+	Consume(ctx context.Context, hash, purpose string) (*models.UserToken, error)
+}
+
+// IdentityRepository, t_user_identities tablosuna erişimi soyutlar.
+type IdentityRepository interface {
+	// Get, sağlayıcıdaki hesaba bağlı kaydı döndürür; bağlantı yoksa ErrNotFound döner.
+	Get(ctx context.Context, provider, subject string) (*models.Identity, error)
+	// Create, sağlayıcı hesabını mevcut bir kullanıcıya bağlar. Hesap zaten bağlıysa ErrConflict döner.
+	Create(ctx context.Context, identity *models.Identity) error
+	// CreateUser, kullanıcıyı verilen rol adıyla oluşturur ve sağlayıcı hesabına tek bir işlemde
+	// bağlar; identity.UserID alanı doldurulur. Kullanıcı adı veya e-posta kullanılıyorsa ErrConflict döner.
+	CreateUser(ctx context.Context, user *models.User, roleName string, identity *models.Identity) error
+}
+
+// TwoFactorRepository, t_user_totp ve t_user_recovery_codes tablolarına erişimi soyutlar.
+type TwoFactorRepository interface {
+	// Get, kullanıcının TOTP ayarını döndürür; kurulum hiç başlatılmamışsa ErrNotFound döner.
+	Get(ctx context.Context, userID uuid.UUID) (*models.TwoFactor, error)
+	// Begin, etkin olmayan bir kurulumu verilen gizli anahtarla başlatır veya yeniler.
+	// İki adımlı doğrulama zaten etkinse ErrConflict döner.
+	Begin(ctx context.Context, userID uuid.UUID, secret string) error
+	// Enable, kurulumu etkinleştirir ve kurtarma kodlarını tek bir işlemde kaydeder.
+	Enable(ctx context.Context, userID uuid.UUID, step int64, recoveryHashes []string) error
+	// Disable, TOTP ayarını ve tüm kurtarma kodlarını siler.
+	Disable(ctx context.Context, userID uuid.UUID) error
+	// UseStep, step son kabul edilen adımdan büyükse onu kaydeder; değilse ErrConflict döner.
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) error
+	// ReplaceRecoveryCodes, kullanıcının tüm kurtarma kodlarını yenileriyle değiştirir.
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, hashes []string) error
+	// UseRecoveryCode, kullanılmamış kurtarma kodunu kullanıldı olarak işaretler; yoksa ErrNotFound döner.
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, hash string) error
+	// RemainingRecoveryCodes, kullanılmamış kurtarma kodlarının sayısını döndürür.
+	RemainingRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"spoti/auth"
+	"spoti/dto"
+	"spoti/handlers"
+	"spoti/middleware"
+)
+
+// setupRoutes, tüm API rotalarını uygulamaya kaydeder.
+// JSON gövdesi alan her rota, gövdeyi handler'dan önce ValidateBody ile doğrular.
+// API anahtarlarıyla çağrılabilecek rotalar RequireScope ile gereken kapsamı belirtir.
+// Doğrulanmamış hesaplara kapatılabilen işlemler RequireVerified ile işaretlenir.
+// Admin rotaları gereken rol iznini RequirePermission ile belirtir.
+func setupRoutes(app *fiber.App, h *handlers.Handler) {
+	api := app.Group("/api")
+	authCfg := h.AuthConfig()
+
+	songsRead := middleware.RequireScope(auth.ScopeSongsRead)
+	playlistsRead := middleware.RequireScope(auth.ScopePlaylistsRead)
+	playlistsWrite := middleware.RequireScope(auth.ScopePlaylistsWrite)
+
+	// Kimlik Doğrulama (Authentication) rotaları
+	api.Post("/user/register", middleware.ValidateBody[dto.RegisterRequest](), h.RegisterUser)
+	api.Post("/user/login", middleware.ValidateBody[dto.LoginRequest](), h.LoginUser)
+	api.Post("/user/logout", h.LogoutUser)
+
+	// Bearer token yenileme ve iptal rotaları
+	api.Post("/auth/refresh", middleware.ValidateBody[dto.RefreshRequest](), h.RefreshToken)
+	api.Post("/auth/revoke", middleware.ValidateBody[dto.RefreshRequest](), h.RevokeToken)
+
+	// Şifre sıfırlama rotaları
+	api.Post("/auth/forgot-password", middleware.ValidateBody[dto.ForgotPasswordRequest](), h.ForgotPassword)
+	api.Post("/auth/reset-password", middleware.ValidateBody[dto.ResetPasswordRequest](), h.ResetPassword)
+
+	// İki adımlı doğrulamada girişin ikinci adımı
+	api.Post("/auth/2fa", middleware.ValidateBody[dto.TwoFactorLoginRequest](), h.VerifyTwoFactorLogin)
+
+	// Harici sağlayıcıyla (OIDC) giriş; sağlayıcı kullanıcıyı callback adresine geri gönderir.
+	api.Get("/auth/oidc/login", h.OIDCLogin)
+	api.Get("/auth/oidc/callback", h.OIDCCallback)
+
+	// E-posta doğrulama; bağlantı oturum gerektirmez, yeniden gönderim ise oturum ister.
+	api.Post("/auth/verify-email", middleware.ValidateBody[dto.VerifyEmailRequest](), h.VerifyEmail)
+	api.Post("/auth/confirm-email", middleware.ValidateBody[dto.ConfirmEmailChangeRequest](), h.ConfirmEmailChange)
+
+	// --- API Route'ları ---
+	// User API'leri için rotalar
+	userAPI := api.Group("/user", middleware.AuthRequired(authCfg))
+	userAPI.Get("/", h.GetUser)
+	userAPI.Put("/", middleware.ValidateBody[dto.UpdateProfileRequest](), h.UpdateUser)
+	userAPI.Delete("/", h.DeleteUser)
+	userAPI.Put("/language", middleware.ValidateBody[dto.LanguageRequest](), h.UpdateLanguage)
+	userAPI.Get("/profile/:userID", h.GetPublicProfile)
+	userAPI.Post("/verify-email/resend", h.ResendVerification)
+
+	// Şifre ve e-posta değişikliği; ikisi de mevcut şifreyi ister.
+	userAPI.Put("/password", middleware.ValidateBody[dto.ChangePasswordRequest](), h.ChangePassword)
+	userAPI.Put("/email", middleware.ValidateBody[dto.ChangeEmailRequest](), h.ChangeEmail)
+
+	// Açık oturumların yönetimi
+	userAPI.Get("/sessions", h.ListSessions)
+	userAPI.Delete("/sessions", h.RevokeOtherSessions)
+	userAPI.Delete("/sessions/:sessionID", h.RevokeSession)
+
+	// İki adımlı doğrulama (TOTP) yönetimi
+	userAPI.Get("/2fa", h.GetTwoFactorStatus)
+	userAPI.Post("/2fa/setup", h.SetupTwoFactor)
+	userAPI.Post("/2fa/enable", middleware.ValidateBody[dto.TwoFactorCodeRequest](), h.EnableTwoFactor)
+	userAPI.Post("/2fa/disable", middleware.ValidateBody[dto.TwoFactorCodeRequest](), h.DisableTwoFactor)
+	userAPI.Post("/2fa/recovery-codes", middleware.ValidateBody[dto.TwoFactorCodeRequest](), h.RegenerateRecoveryCodes)
+
+	// API anahtarı yönetimi; yalnızca session veya erişim token'ıyla yapılabilir.
+	userAPI.Post("/api-keys", h.RequireVerified(auth.RestrictAPIKeys), middleware.ValidateBody[dto.CreateAPIKeyRequest](), h.CreateAPIKey)
+	userAPI.Get("/api-keys", h.ListAPIKeys)
+	userAPI.Delete("/api-keys/:keyID", h.RevokeAPIKey)
+
+	// Şarkı ve Çalma Listesi (Playlist) Rotaları
+	userAPI.Get("/song", songsRead, middleware.ValidateSongListQuery, h.GetSongs)
+	userAPI.Get("/song/suggest", songsRead, middleware.ValidateSuggestQuery, h.SuggestSongs)
+	userAPI.Get("/song/:songID", songsRead, h.GetSongByID)
+	userAPI.Get("/artist/:artistID", songsRead, h.GetArtist)
+	userAPI.Get("/artist/:artistID/albums", songsRead, h.GetArtistAlbums)
+	userAPI.Get("/album/:albumID", songsRead, h.GetAlbum)
+	userAPI.Get("/album/:albumID/tracks", songsRead, h.GetAlbumTracks)
+	userAPI.Post("/playlist", playlistsWrite, h.RequireVerified(auth.RestrictPlaylistCreate), middleware.ValidateBody[dto.CreatePlaylistRequest](), h.CreatePlaylist)
+	userAPI.Get("/playlist", playlistsRead, middleware.ValidateListQuery, h.GetUserPlaylists)
+	userAPI.Get("/playlist/:playlistID", playlistsRead, h.GetPlaylistByID)
+	userAPI.Delete("/playlist/:playlistID", playlistsWrite, h.DeletePlaylist)
+	userAPI.Post("/playlist/:playlistID/:songID", playlistsWrite, h.AddSongToPlaylist)
+
+	// Rota çakışmasını önlemek için rotalar güncellendi
+	userAPI.Get("/playlist/by-user/:userID", playlistsRead, middleware.ValidateListQuery, h.GetUserPlaylistsByUserID)
+	userAPI.Get("/playlist/by-user/:userID/:songID", playlistsRead, h.GetUserPlaylistSongByUserID)
+
+	// Kupon ve Premium Üyelik Rotaları
+	userAPI.Get("/coupon", h.GetUserCoupons)
+	userAPI.Post("/premium/start", h.RequireVerified(auth.RestrictPremium), h.StartPremiumPurchase)
+	userAPI.Post("/premium", h.RequireVerified(auth.RestrictPremium), middleware.ValidateBody[dto.PurchasePremiumRequest](), h.PurchasePremium)
+
+	// Admin API'leri için rotalar. Her rota gereken izni RequirePermission ile belirtir.
+	adminAPI := api.Group("/admin", middleware.AdminRequired(authCfg))
+	songsWrite := middleware.RequirePermission(auth.PermSongsWrite)
+	usersRead := middleware.RequirePermission(auth.PermUsersRead)
+	usersWrite := middleware.RequirePermission(auth.PermUsersWrite)
+	couponsWrite := middleware.RequirePermission(auth.PermCouponsWrite)
+	rolesManage := middleware.RequirePermission(auth.PermRolesManage)
+	systemRead := middleware.RequirePermission(auth.PermSystemRead)
+
+	adminAPI.Get("/user", usersRead, middleware.ValidateListQuery, h.GetAllUsers)
+	adminAPI.Get("/user/:userID", usersRead, h.GetUserByID)
+	adminAPI.Put("/user/:userID", usersWrite, middleware.ValidateBody[dto.UpdateAccountRequest](), h.UpdateUserByID)
+	adminAPI.Delete("/user/:userID", usersWrite, h.DeleteUserByID)
+	adminAPI.Post("/user/:userID/logout", usersWrite, h.ForceLogoutUser)
+	adminAPI.Get("/db/stats", systemRead, h.GetDBStats)
+
+	// Giriş kilidi rotaları
+	adminAPI.Get("/lockouts", usersRead, h.ListLockouts)
+	adminAPI.Get("/lockouts/user/:userID", usersRead, h.GetUserLockout)
+	adminAPI.Delete("/lockouts/user/:userID", usersWrite, h.ClearUserLockout)
+	adminAPI.Get("/lockouts/ip/:ip", usersRead, h.GetIPLockout)
+	adminAPI.Delete("/lockouts/ip/:ip", usersWrite, h.ClearIPLockout)
+
+	// Rol ve izin rotaları
+	adminAPI.Get("/roles", rolesManage, h.ListRoles)
+	adminAPI.Post("/roles", rolesManage, middleware.ValidateBody[dto.CreateRoleRequest](), h.CreateRole)
+	adminAPI.Put("/roles/:name", rolesManage, middleware.ValidateBody[dto.UpdateRoleRequest](), h.UpdateRole)
+	adminAPI.Get("/permissions", rolesManage, h.ListPermissions)
+	adminAPI.Put("/user/:userID/role", rolesManage, middleware.ValidateBody[dto.AssignRoleRequest](), h.AssignRole)
+
+	// Yeni Admin Rotaları
+	adminAPI.Post("/song", songsWrite, middleware.ValidateBody[dto.SongRequest](), h.AdminCreateSong)
+	adminAPI.Delete("/song/:songID", songsWrite, h.AdminDeleteSong)
+	adminAPI.Put("/song/:songID", songsWrite, middleware.ValidateBody[dto.SongRequest](), h.AdminUpdateSong)
+	adminAPI.Put("/album/:albumID", songsWrite, middleware.ValidateBody[dto.AlbumRequest](), h.AdminUpdateAlbum)
+
+	// Kupon Admin Rotaları
+	adminAPI.Post("/coupon", couponsWrite, middleware.ValidateBody[dto.CreateCouponRequest](), h.CreateCoupon)
+	adminAPI.Post("/coupon/assign", couponsWrite, middleware.ValidateBody[dto.AssignCouponRequest](), h.AssignCoupon)
+}
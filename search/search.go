@@ -0,0 +1,134 @@
+// Package search, şarkı aramasında kullanılan metin normalleştirmesini ve vurgulama
+// işaretlerini tek yerde toplar.
+//
+// Arama metni kelimelere ayrılır ve her kelime Türkçe karakterler ile aksanlar sadeleştirilmiş
+// küçük harfli biçimine çevrilir (ı/İ→i, ş→s, ğ→g, ç→c, ö→o, ü→u). PostgreSQL tarafında aynı
+// sadeleştirmeyi spoti_search tam metin yapılandırması (unaccent + simple) yapar.
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// MaxTerms, tek bir aramada dikkate alınan en fazla kelime sayısıdır; fazlası yok sayılır.
+const MaxTerms = 8
+
+// Vurgulanan kelimeleri çevreleyen işaretler. Unicode özel kullanım alanındaki bu karakterler
+// şarkı verisinde geçmez; Mark ile HTML'e çevrilmeden önce metin kaçışlanabilsin diye
+// doğrudan <mark> yerine kullanılırlar.
+const (
+	StartSel = "\uE000"
+	StopSel  = "\uE001"
+)
+
+// folds, küçük harfe çevirmeden önce tek karaktere sadeleştirilen harflerdir. İ özel olarak
+// ele alınır; unicode.ToLower onu noktalı i'ye, strings.ToLower ise iki karaktere çevirir.
+var folds = map[rune]rune{
+	'İ': 'i', 'I': 'i', 'ı': 'i', 'î': 'i', 'í': 'i', 'ì': 'i', 'ï': 'i', 'Î': 'i', 'Í': 'i',
+	'Ş': 's', 'ş': 's', 'Ğ': 'g', 'ğ': 'g', 'Ç': 'c', 'ç': 'c', 'Ñ': 'n', 'ñ': 'n',
+	'Ö': 'o', 'ö': 'o', 'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'Ó': 'o', 'Ô': 'o',
+	'Ü': 'u', 'ü': 'u', 'û': 'u', 'ú': 'u', 'ù': 'u', 'Û': 'u', 'Ú': 'u',
+	'Â': 'a', 'â': 'a', 'á': 'a', 'à': 'a', 'ä': 'a', 'ã': 'a', 'å': 'a', 'Á': 'a', 'Ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e', 'É': 'e', 'È': 'e',
+}
+
+// Fold, metni karakter karakter sadeleştirir. Her karakter tek bir karaktere çevrildiğinden
+// sonucun karakter konumları girdiyle aynıdır.
+func Fold(s string) string {
+	return strings.Map(func(r rune) rune {
+		if f, ok := folds[r]; ok {
+			return f
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// CleanName, sanatçı veya albüm adındaki baştaki ve sondaki boşlukları atar, aradaki
+// boşlukları teke indirir.
+func CleanName(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// NameKey, iki adın aynı sanatçıyı veya albümü gösterip göstermediğini belirleyen anahtardır;
+// PostgreSQL'deki spoti_name_key ile aynı sadeleştirmeyi yapar.
+func NameKey(s string) string {
+	return Fold(CleanName(s))
+}
+
+// Terms, arama metnini sadeleştirilmiş kelimelere ayırır. Harf ve rakam dışındaki karakterler
+// ayraç sayılır; böylece kelimeler tsquery sözdizimine güvenle yerleştirilebilir.
+func Terms(s string) []string {
+	terms := words(s)
+	if len(terms) > MaxTerms {
+		terms = terms[:MaxTerms]
+	}
+	return terms
+}
+
+// words, metni sınır uygulamadan sadeleştirilmiş kelimelere ayırır.
+func words(s string) []string {
+	return strings.FieldsFunc(Fold(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// PrefixQuery, kelimelerden her birinin bir kelimenin başıyla eşleşmesini isteyen tsquery
+// metnini oluşturur (ör. "tark:* & kuz:*"); yazarken arama yapılabilmesini sağlar.
+func PrefixQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term + ":*"
+	}
+	return strings.Join(parts, " & ")
+}
+
+// Mark, StartSel/StopSel işaretli metni HTML olarak kaçışlar ve işaretleri <mark> etiketlerine çevirir.
+func Mark(s string) string {
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, StartSel, "<mark>")
+	return strings.ReplaceAll(s, StopSel, "</mark>")
+}
+
+// Trigrams, metnin pg_trgm ile aynı biçimde üretilen üçlü karakter kümesidir: her kelimenin
+// başına iki, sonuna bir boşluk eklenir ve ardışık üçlüler alınır.
+func Trigrams(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, word := range words(s) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+// Similarity, iki metnin ortak üçlülerinin tüm üçlülere oranıdır (pg_trgm similarity).
+func Similarity(a, b string) float64 {
+	ta, tb := Trigrams(a), Trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if _, ok := tb[t]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// WordSimilarity, q'nun metnin bir kelimesinin başına veya metnin tamamına en fazla ne kadar
+// benzediğini döndürür. pg_trgm word_similarity fonksiyonuna yazarken tamamlama için yeterli
+// bir yaklaşımdır; veritabanı olmadan çalışan bellek içi gerçekleme tarafından kullanılır.
+func WordSimilarity(q, text string) float64 {
+	best := Similarity(q, text)
+	for _, word := range words(text) {
+		runes := []rune(word)
+		for n := 1; n <= len(runes); n++ {
+			best = max(best, Similarity(q, string(runes[:n])))
+		}
+	}
+	return best
+}
@@ -0,0 +1,157 @@
+package search_test
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"testing"
+	"unicode/utf8"
+
+	"spoti/search"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"İSTANBUL", "istanbul"},
+		{"Işık", "isik"},
+		{"Şımarık", "simarik"},
+		{"Çiçek Dağı", "cicek dagi"},
+		{"Gönül Üzgün", "gonul uzgun"},
+		{"Beyoncé", "beyonce"},
+		{"Niño", "nino"},
+		{"ABC 123", "abc 123"},
+	}
+	for _, tt := range tests {
+		got := search.Fold(tt.in)
+		if got != tt.want {
+			t.Errorf("Fold(%q) = %q, beklenen %q", tt.in, got, tt.want)
+		}
+		// Vurgulama karakter konumlarına dayandığından uzunluk korunmalıdır.
+		if utf8.RuneCountInString(got) != utf8.RuneCountInString(tt.in) {
+			t.Errorf("Fold(%q) karakter sayısını değiştirdi", tt.in)
+		}
+	}
+}
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Tarkan Kuzu Kuzu", []string{"tarkan", "kuzu", "kuzu"}},
+		{"  şımarık!  ", []string{"simarik"}},
+		{"a:* & b | !c", []string{"a", "b", "c"}},
+		{"rock'n'roll", []string{"rock", "n", "roll"}},
+		{"", nil},
+		{"--- ***", nil},
+		{"1 2 3 4 5 6 7 8 9 10", []string{"1", "2", "3", "4", "5", "6", "7", "8"}},
+	}
+	for _, tt := range tests {
+		if got := search.Terms(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("Terms(%q) = %q, beklenen %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPrefixQuery(t *testing.T) {
+	tests := []struct {
+		terms []string
+		want  string
+	}{
+		{[]string{"tark"}, "tark:*"},
+		{[]string{"tark", "kuz"}, "tark:* & kuz:*"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := search.PrefixQuery(tt.terms); got != tt.want {
+			t.Errorf("PrefixQuery(%q) = %q, beklenen %q", tt.terms, got, tt.want)
+		}
+	}
+}
+
+func TestMark(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{search.StartSel + "Tarkan" + search.StopSel, "<mark>Tarkan</mark>"},
+		{"Kuzu " + search.StartSel + "Kuzu" + search.StopSel, "Kuzu <mark>Kuzu</mark>"},
+		{"<script>" + search.StartSel + "x" + search.StopSel, "&lt;script&gt;<mark>x</mark>"},
+		{"Rock & Roll", "Rock &amp; Roll"},
+	}
+	for _, tt := range tests {
+		if got := search.Mark(tt.in); got != tt.want {
+			t.Errorf("Mark(%q) = %q, beklenen %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTrigrams(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"cat", []string{"  c", " ca", "at ", "cat"}},
+		{"Çay", []string{"  c", " ca", "ay ", "cay"}},
+		{"a b", []string{"  a", " a ", "  b", " b "}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := slices.Sorted(maps.Keys(search.Trigrams(tt.in)))
+		if want := slices.Sorted(slices.Values(tt.want)); !slices.Equal(got, want) {
+			t.Errorf("Trigrams(%q) = %q, beklenen %q", tt.in, got, want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	// Beklenen değerler PostgreSQL pg_trgm similarity ve word_similarity çıktılarıdır.
+	tests := []struct {
+		name string
+		fn   func(a, b string) float64
+		a, b string
+		want float64
+	}{
+		{"aynı metin", search.Similarity, "tarkan", "Tarkan", 1},
+		{"ortak üçlü yok", search.Similarity, "abc", "xyz", 0},
+		{"boş metin", search.Similarity, "", "tarkan", 0},
+		{"pg_trgm örneği", search.Similarity, "word", "two words", 4.0 / 11},
+		{"yazım hatası", search.Similarity, "trakan", "tarkan", 3.0 / 11},
+		{"kelime başı", search.WordSimilarity, "tark", "Kuzu Kuzu Tarkan", 1},
+		{"ikinci kelime", search.WordSimilarity, "kuz", "Tarkan Kuzu", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("(%q, %q) = %v, beklenen %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameKey(t *testing.T) {
+	tests := []struct {
+		in, clean, key string
+	}{
+		{"Tarkan", "Tarkan", "tarkan"},
+		{"  tarkan ", "tarkan", "tarkan"},
+		{"Barış   Manço", "Barış Manço", "baris manco"},
+		{"BARIŞ MANÇO\t", "BARIŞ MANÇO", "baris manco"},
+		{"Ölürüm Sana", "Ölürüm Sana", "olurum sana"},
+		{"   ", "", ""},
+	}
+	for _, tt := range tests {
+		if got := search.CleanName(tt.in); got != tt.clean {
+			t.Errorf("CleanName(%q) = %q, beklenen %q", tt.in, got, tt.clean)
+		}
+		if got := search.NameKey(tt.in); got != tt.key {
+			t.Errorf("NameKey(%q) = %q, beklenen %q", tt.in, got, tt.key)
+		}
+	}
+
+	// Bitişik yazılan adlar ayrı sanatçı sayılır; yalnızca boşluk sayısı ve harf farkı yok sayılır.
+	if search.NameKey("Sezen Aksu") == search.NameKey("SezenAksu") {
+		t.Error("farklı adlar aynı anahtarı aldı")
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+
+	"spoti/auth"
+	"spoti/models"
+	"spoti/repository"
+)
+
+// Demo kullanıcılarının ortak şifresi; yalnızca yerel geliştirme içindir.
+const demoPassword = "demo1234"
+
+var demoSongs = []models.Song{
+	{Title: "Şımarık", Artist: "Tarkan", Album: "Ölürüm Sana", Duration: 234},
+	{Title: "Kuzu Kuzu", Artist: "Tarkan", Album: "Karma", Duration: 239},
+	{Title: "Gidiyorum", Artist: "Sezen Aksu", Album: "Düş Bahçeleri", Duration: 271},
+	{Title: "Firuze", Artist: "Sezen Aksu", Album: "Firuze", Duration: 262},
+	{Title: "Bir Derdim Var", Artist: "mor ve ötesi", Album: "Dünya Yalan Söylüyor", Duration: 258},
+	{Title: "Cambaz", Artist: "mor ve ötesi", Album: "Büyük Düşler", Duration: 219},
+	{Title: "Islak Islak", Artist: "Barış Manço", Album: "Sözüm Meclisten Dışarı", Duration: 301},
+	{Title: "Gülpembe", Artist: "Barış Manço", Album: "30 Sene Sonra", Duration: 290},
+	{Title: "Sen Ağlama", Artist: "Sertab Erener", Album: "Lâl", Duration: 248},
+	{Title: "Everyway That I Can", Artist: "Sertab Erener", Album: "No Boundaries", Duration: 182},
+	{Title: "Ah Istanbul", Artist: "Candan Erçetin", Album: "Neden", Duration: 245},
+	{Title: "Yalnızlık Senfonisi", Artist: "Duman", Album: "Belki Alışman Lazım", Duration: 276},
+}
+
+type demoUser struct {
+	username  string
+	email     string
+	hesapTuru string
+	playlists map[string][]int // çalma listesi adı -> demoSongs indeksleri
+}
+
+var demoUsers = []demoUser{
+	{
+		username:  "demo",
+		email:     "demo@spoti.local",
+		hesapTuru: "Free",
+		playlists: map[string][]int{
+			"Yol Şarkıları": {0, 4, 11},
+			"Nostalji":      {2, 6, 7},
+		},
+	},
+	{
+		username:  "premium",
+		email:     "premium@spoti.local",
+		hesapTuru: "Premium",
+		playlists: map[string][]int{
+			"Favoriler": {0, 1, 2, 3, 5, 8, 9, 10},
+		},
+	},
+}
+
+// seedDemoData, yerel geliştirme için örnek şarkı, kullanıcı ve çalma listeleri ekler.
+// Demo kullanıcısı zaten varsa hiçbir şey yapmaz; böylece komut tekrar çalıştırılabilir.
+func seedDemoData(ctx context.Context, repos adminRepos) error {
+	_, err := repos.users.GetByEmail(ctx, demoUsers[0].email)
+	if err == nil {
+		log.Println("Demo verileri zaten yüklenmiş, atlanıyor.")
+		return nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	songIDs := make([]uuid.UUID, len(demoSongs))
+	for i := range demoSongs {
+		song := demoSongs[i]
+		if err := repos.songs.Create(ctx, &song); err != nil {
+			return fmt.Errorf("%q şarkısı eklenemedi: %w", song.Title, err)
+		}
+		songIDs[i] = song.ID
+	}
+
+	hashed, err := auth.HashPassword(demoPassword)
+	if err != nil {
+		return fmt.Errorf("şifre hashlenemedi: %w", err)
+	}
+
+	for _, du := range demoUsers {
+		user := models.User{
+			ID:        uuid.New(),
+			Username:  du.username,
+			Email:     du.email,
+			Password:  hashed,
+			HesapTuru: du.hesapTuru,
+			Cash:      100.00,
+		}
+		if err := repos.users.Create(ctx, &user, "user"); err != nil {
+			return fmt.Errorf("%q kullanıcısı eklenemedi: %w", du.email, err)
+		}
+
+		for name, songIndexes := range du.playlists {
+			playlist := models.Playlist{Name: name, UserID: user.ID}
+			if err := repos.playlists.Create(ctx, &playlist); err != nil {
+				return fmt.Errorf("%q çalma listesi eklenemedi: %w", name, err)
+			}
+			for _, idx := range songIndexes {
+				if err := repos.playlists.AddSong(ctx, playlist.ID, songIDs[idx]); err != nil {
+					return fmt.Errorf("%q çalma listesine şarkı eklenemedi: %w", name, err)
+				}
+			}
+		}
+	}
+
+	log.Printf("%d şarkı ve %d demo kullanıcısı eklendi (şifre: %s).\n", len(demoSongs), len(demoUsers), demoPassword)
+	return nil
+}
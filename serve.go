@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/gofiber/storage/redis/v3"
+
+	"spoti/apierr"
+	"spoti/auth"
+	"spoti/config"
+	"spoti/database"
+	"spoti/handlers"
+	"spoti/i18n"
+	"spoti/kv"
+	"spoti/lockout"
+	"spoti/mail"
+	"spoti/middleware"
+	"spoti/oidc"
+	"spoti/rbac"
+	"spoti/repository/postgres"
+	"spoti/sessions"
+)
+
+// runServe, bağımlılıkları kurar ve HTTP sunucusunu başlatır.
+// SIGINT veya SIGTERM alındığında yeni bağlantıları reddeder, devam eden isteklerin
+// bitmesini server.shutdown_timeout kadar bekler, arka plan işlerini durdurur ve
+// veritabanı havuzu ile Redis bağlantısını kapatır.
+func runServe(cfg *config.Config, args []string) error {
+	if err := cfg.ValidateServe(); err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	migrate := fs.Bool("migrate", cfg.Database.AutoMigrate, "Sunucu başlamadan önce bekleyen migration'ları uygula")
+	fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *migrate {
+		if err := migrateUp(ctx, cfg); err != nil {
+			return err
+		}
+	}
+
+	// Redis için yeni bir Store oluştur
+	redisStore := redis.New(redis.Config{
+		URL:      cfg.Redis.URL,
+		Host:     cfg.Redis.Host,
+		Port:     cfg.Redis.Port,
+		Username: cfg.Redis.Username,
+		Password: cfg.Redis.Password,
+		Database: cfg.Redis.Database,
+	})
+	defer func() {
+		if err := redisStore.Close(); err != nil {
+			log.Printf("Redis bağlantısı kapatılamadı: %v\n", err)
+		}
+	}()
+
+	store := session.New(session.Config{
+		Storage: redisStore,
+	})
+	// Atomik sayaç ve hash işlemleri gereken paketler aynı Redis bağlantısını kullanır.
+	shared := kv.NewRedis(redisStore)
+
+	mailer, err := mail.New(cfg.Mail)
+	if err != nil {
+		return err
+	}
+
+	// Veritabanı bağlantı havuzu
+	db, err := database.Connect(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Arka plan işleri sinyal context'iyle durdurulur; kapanırken bitmeleri beklenir.
+	var workers sync.WaitGroup
+	defer workers.Wait()
+	if cfg.Database.StatsInterval > 0 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			database.LogStats(ctx, db, cfg.Database.StatsInterval)
+		}()
+	}
+
+	// İsteklerin context'i yalnızca kapanma süresi aşılırsa iptal edilir.
+	requestCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+
+	var inFlight middleware.InFlight
+
+	app := fiber.New(fiber.Config{
+		// Tüm hatalar, eşleşmeyen rotalar ve yakalanan panikler aynı JSON biçiminde döner.
+		ErrorHandler: apierr.Handler,
+		// Giriş kilitleri ve session listesi c.IP() kullanır; vekil arkasında istemcinin adresi
+		// yalnızca güvenilen vekillerin başlığından okunur.
+		ProxyHeader:             cfg.Server.ProxyHeader,
+		EnableTrustedProxyCheck: len(cfg.Server.TrustedProxies) > 0,
+		TrustedProxies:          cfg.Server.TrustedProxies,
+		EnableIPValidation:      true,
+	})
+	app.Use(inFlight.Handler())
+	app.Use(recover.New(recover.Config{EnableStackTrace: true}))
+	defaultLang, _ := i18n.Parse(cfg.Server.DefaultLanguage)
+	app.Use(i18n.Middleware(defaultLang))
+	app.Use(middleware.RequestContext(requestCtx, cfg.Server.RequestTimeout))
+
+	// Harici sağlayıcıyla giriş yalnızca yapılandırılmışsa açılır.
+	var oidcProvider *oidc.Provider
+	if cfg.OIDC.Enabled() {
+		redirectURL := cfg.OIDC.RedirectURL
+		if redirectURL == "" {
+			redirectURL = strings.TrimRight(cfg.Server.PublicURL, "/") + "/api/auth/oidc/callback"
+		}
+		oidcProvider = oidc.New(cfg.OIDC, redirectURL)
+	}
+
+	// Handler'lara repository'leri ve session store'u aktar
+	roles := postgres.NewRoleRepository(db)
+	h := handlers.New(handlers.Deps{
+		Users:     postgres.NewUserRepository(db),
+		Songs:     postgres.NewSongRepository(db),
+		Artists:   postgres.NewArtistRepository(db),
+		Albums:    postgres.NewAlbumRepository(db),
+		Playlists: postgres.NewPlaylistRepository(db),
+		Coupons:   postgres.NewCouponRepository(db),
+		Roles:     roles,
+		Store:     store,
+		KV:        shared,
+		Sessions:  sessions.New(shared, store.Expiration),
+		PoolStats: func() database.PoolStats { return database.Stats(db) },
+
+		Permissions: rbac.New(roles, cfg.Auth.RoleCacheTTL),
+
+		Tokens:        auth.NewTokenManager(cfg.Auth.JWTSecret, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL),
+		RefreshTokens: postgres.NewRefreshTokenRepository(db),
+		APIKeys:       postgres.NewAPIKeyRepository(db),
+		UserTokens:    postgres.NewUserTokenRepository(db),
+		TwoFactor:     postgres.NewTwoFactorRepository(db),
+
+		OIDC:       oidcProvider,
+		Identities: postgres.NewIdentityRepository(db),
+
+		Mailer:                     mailer,
+		PublicURL:                  cfg.Server.PublicURL,
+		PasswordResetTTL:           cfg.Auth.PasswordResetTTL,
+		EmailVerificationTTL:       cfg.Auth.EmailVerificationTTL,
+		VerificationResendInterval: cfg.Auth.VerificationResendInterval,
+		UnverifiedRestrictions:     cfg.Auth.UnverifiedRestrictions,
+		EmailChangeTTL:             cfg.Auth.EmailChangeTTL,
+		SuggestTimeout:             cfg.Search.SuggestTimeout,
+		PasswordPolicy: auth.PasswordPolicy{
+			MinLength:     cfg.Auth.PasswordMinLength,
+			RequireUpper:  cfg.Auth.PasswordRequireUpper,
+			RequireLower:  cfg.Auth.PasswordRequireLower,
+			RequireDigit:  cfg.Auth.PasswordRequireDigit,
+			RequireSymbol: cfg.Auth.PasswordRequireSymbol,
+		},
+
+		Lockout: lockout.New(shared, lockout.Policy{
+			FreeAttempts:  cfg.Login.FreeAttempts,
+			MaxAttempts:   cfg.Login.MaxAttempts,
+			IPMaxAttempts: cfg.Login.IPMaxAttempts,
+			BaseDelay:     cfg.Login.BaseDelay,
+			MaxDelay:      cfg.Login.MaxDelay,
+			Window:        cfg.Login.Window,
+			LockDuration:  cfg.Login.LockDuration,
+		}),
+	})
+
+	setupRoutes(app, h)
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.Listen(cfg.Server.Addr)
+	}()
+
+	select {
+	case err := <-listenErr:
+		stop() // arka plan işlerini durdur
+		if err != nil {
+			return fmt.Errorf("sunucu durdu: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+	// İkinci bir sinyal süreci varsayılan davranışla hemen sonlandırsın.
+	stop()
+
+	pending := inFlight.StartDrain()
+	log.Printf("Kapanma sinyali alındı, %d devam eden istek bekleniyor (en fazla %s).\n", pending, cfg.Server.ShutdownTimeout)
+
+	if err := app.ShutdownWithTimeout(cfg.Server.ShutdownTimeout); err != nil {
+		log.Printf("Kapanma süresi aşıldı, kalan %d istek iptal ediliyor: %v\n", inFlight.Active(), err)
+		cancelRequests()
+	}
+	if err := <-listenErr; err != nil {
+		log.Printf("Sunucu kapanırken hata: %v\n", err)
+	}
+
+	log.Printf("Sunucu durduruldu: %d istek tamamlanarak boşaltıldı.\n", inFlight.Drained())
+	return nil
+}
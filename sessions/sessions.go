@@ -0,0 +1,251 @@
+// Package sessions, kullanıcıların açık session'larını cihaz, IP ve son görülme bilgileriyle
+// listeleyebilmek için kullanıcı başına bir dizin tutar.
+//
+// Session'ların kendisi fiber'in session.Store'unda (üretimde Redis) durur ve storage anahtarı
+// session ID'sidir. fiber.Storage anahtar taraması desteklemediğinden bir kullanıcının
+// session'ları ancak bu dizin üzerinden bulunabilir. Dizin aynı storage'da kullanıcı başına
+// bir hash'tir (user_sessions:<kullanıcı>); her session kendi alanında tutulur ve HSET/HDEL
+// ile tek başına yazılır.
+package sessions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"spoti/kv"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFound, istenen session kullanıcının dizininde olmadığında döner.
+var ErrNotFound = errors.New("session bulunamadı")
+
+// touchInterval, son görülme zamanının en fazla ne sıklıkla yazılacağıdır; her istekte
+// dizini yeniden yazmamak için bu süreden yeni kayıtlar güncellenmez.
+const touchInterval = time.Minute
+
+// Entry, dizindeki bir session kaydıdır.
+type Entry struct {
+	// Key, session'ın storage anahtarıdır (çerezdeki session ID). İstemcilere gösterilmez;
+	// istemciler session'ları ID ile tanır.
+	Key       string    `json:"key"`
+	ID        string    `json:"id"`
+	Device    string    `json:"device"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Index, kullanıcıların session dizinlerini yönetir.
+type Index struct {
+	store kv.Store
+	ttl   time.Duration
+}
+
+// New, verilen store'u kullanan bir Index oluşturur. ttl, session'ların ömrüdür;
+// dizin her yazılışta bu süre kadar uzatılır.
+func New(store kv.Store, ttl time.Duration) *Index {
+	return &Index{store: store, ttl: ttl}
+}
+
+// PublicID, session anahtarından istemcilere gösterilecek ID'yi türetir. Anahtarın
+// kendisi çerez değeri olduğundan yanıtlarda yer almamalıdır.
+func PublicID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Add, yeni açılan bir session'ı dizine ekler; aynı anahtar zaten varsa kaydı yeniler.
+func (x *Index) Add(userID uuid.UUID, key, ip, userAgent string, now time.Time) error {
+	return x.put(userID, newEntry(key, ip, userAgent, now))
+}
+
+// Touch, session'ın son görülme zamanını ve IP adresini günceller. Dizinde olmayan
+// session'lar (ör. dizinden önce açılmış olanlar) eklenir.
+func (x *Index) Touch(userID uuid.UUID, key, ip, userAgent string, now time.Time) error {
+	raw, err := x.store.HGet(indexKey(userID), key)
+	if err != nil {
+		return err
+	}
+	var e Entry
+	if len(raw) == 0 || json.Unmarshal(raw, &e) != nil {
+		return x.put(userID, newEntry(key, ip, userAgent, now))
+	}
+	if now.Sub(e.LastSeen) < touchInterval && e.IP == ip {
+		return nil
+	}
+	e.LastSeen = now
+	e.IP = ip
+	return x.put(userID, e)
+}
+
+// List, kullanıcının açık session'larını en son görülenden başlayarak döndürür. Süresi
+// dolmuş veya başka bir yoldan silinmiş session'lar bu sırada dizinden temizlenir.
+func (x *Index) List(userID uuid.UUID) ([]Entry, error) {
+	list, err := x.read(userID)
+	if err != nil {
+		return nil, err
+	}
+	alive := make([]Entry, 0, len(list))
+	var dead []string
+	for _, e := range list {
+		raw, err := x.store.Get(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		if raw == nil {
+			dead = append(dead, e.Key)
+			continue
+		}
+		alive = append(alive, e)
+	}
+	if err := x.store.HDel(indexKey(userID), dead...); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(alive, func(a, b Entry) int { return b.LastSeen.Compare(a.LastSeen) })
+	return alive, nil
+}
+
+// Remove, session'ı yalnızca dizinden çıkarır; oturumu kapatan kod session'ı kendisi siler.
+func (x *Index) Remove(userID uuid.UUID, key string) error {
+	return x.store.HDel(indexKey(userID), key)
+}
+
+// Revoke, ID'si verilen session'ı sonlandırır ve dizinden çıkarır.
+func (x *Index) Revoke(userID uuid.UUID, id string) error {
+	list, err := x.read(userID)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(list, func(e Entry) bool { return e.ID == id })
+	if i < 0 {
+		return ErrNotFound
+	}
+	if err := x.store.Delete(list[i].Key); err != nil {
+		return err
+	}
+	return x.store.HDel(indexKey(userID), list[i].Key)
+}
+
+// RevokeOthers, anahtarı keep olan dışındaki tüm session'ları sonlandırır ve sonlandırılan
+// session sayısını döndürür. keep boşsa kullanıcının bütün session'ları sonlandırılır.
+func (x *Index) RevokeOthers(userID uuid.UUID, keep string) (int, error) {
+	list, err := x.read(userID)
+	if err != nil {
+		return 0, err
+	}
+	revoked := 0
+	for _, e := range list {
+		if e.Key == keep {
+			continue
+		}
+		if err := x.store.Delete(e.Key); err != nil {
+			return revoked, err
+		}
+		if err := x.store.HDel(indexKey(userID), e.Key); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+	return revoked, nil
+}
+
+// RevokeAll, kullanıcının bütün session'larını sonlandırır.
+func (x *Index) RevokeAll(userID uuid.UUID) error {
+	_, err := x.RevokeOthers(userID, "")
+	return err
+}
+
+func newEntry(key, ip, userAgent string, now time.Time) Entry {
+	return Entry{
+		Key:       key,
+		ID:        PublicID(key),
+		Device:    DeviceName(userAgent),
+		IP:        ip,
+		UserAgent: userAgent,
+		CreatedAt: now,
+		LastSeen:  now,
+	}
+}
+
+// read, kullanıcının dizinindeki bütün kayıtları okur. Bozuk kayıtlar atlanır; oturumları
+// engellememeleri için bir sonraki yazışta yerlerine yenisi gelir.
+func (x *Index) read(userID uuid.UUID) ([]Entry, error) {
+	fields, err := x.store.HGetAll(indexKey(userID))
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Entry, 0, len(fields))
+	for _, raw := range fields {
+		var e Entry
+		if err := json.Unmarshal(raw, &e); err == nil {
+			list = append(list, e)
+		}
+	}
+	return list, nil
+}
+
+// put, kaydı kullanıcının dizinine kendi alanı olarak yazar; diğer session'ların kayıtlarına
+// dokunmadığından eşzamanlı girişler birbirini ezmez.
+func (x *Index) put(userID uuid.UUID, e Entry) error {
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return x.store.HSet(indexKey(userID), e.Key, raw, x.ttl)
+}
+
+func indexKey(userID uuid.UUID) string {
+	return "user_sessions:" + userID.String()
+}
+
+// DeviceName, User-Agent başlığından "Firefox (Windows)" gibi kısa bir cihaz adı üretir.
+// Tarayıcı tanınmazsa başlığın ilk ürün adı (ör. "curl") kullanılır.
+func DeviceName(userAgent string) string {
+	browser := ""
+	for _, b := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	} {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	system := ""
+	for _, o := range []struct{ token, name string }{
+		{"Windows", "Windows"},
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, o.token) {
+			system = o.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " (" + system + ")"
+	case browser != "":
+		return browser
+	case system != "":
+		return system
+	}
+	product, _, _ := strings.Cut(userAgent, "/")
+	return strings.TrimSpace(product)
+}
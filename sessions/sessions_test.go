@@ -0,0 +1,120 @@
+package sessions_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"spoti/kv"
+	"spoti/sessions"
+
+	"github.com/google/uuid"
+)
+
+// newIndex, bellek içi store'u kullanan bir Index ve session'ların saklandığı store'u döndürür.
+func newIndex() (*sessions.Index, *kv.Memory) {
+	store := kv.NewMemory()
+	return sessions.New(store, time.Hour), store
+}
+
+// open, storage'a bir session yazar ve onu dizine ekler.
+func open(t *testing.T, x *sessions.Index, store *kv.Memory, userID uuid.UUID, key string) {
+	t.Helper()
+	if err := store.Set(key, []byte("session"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := x.Add(userID, key, "10.0.0.1", "curl/8.5.0", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentAddsKeepEverySession(t *testing.T) {
+	const logins = 50
+	x, store := newIndex()
+	userID := uuid.New()
+
+	var wg sync.WaitGroup
+	for i := range logins {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("session-%d", i)
+			if err := store.Set(key, []byte("session"), time.Hour); err != nil {
+				t.Error(err)
+			}
+			if err := x.Add(userID, key, "10.0.0.1", "curl/8.5.0", time.Now()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Eşzamanlı girişler birbirinin kaydını ezmemeli.
+	list, err := x.List(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != logins {
+		t.Fatalf("dizindeki session sayısı = %d, beklenen %d", len(list), logins)
+	}
+}
+
+func TestListPrunesEndedSessions(t *testing.T) {
+	x, store := newIndex()
+	userID := uuid.New()
+	open(t, x, store, userID, "a")
+	open(t, x, store, userID, "b")
+
+	// Süresi dolan veya başka yoldan silinen session listelenmez.
+	if err := store.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	list, err := x.List(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Key != "b" {
+		t.Fatalf("session'lar = %+v", list)
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	x, store := newIndex()
+	userID := uuid.New()
+	open(t, x, store, userID, "a")
+	open(t, x, store, userID, "b")
+	open(t, x, store, userID, "c")
+
+	if err := x.Revoke(userID, "bilinmeyen"); err != sessions.ErrNotFound {
+		t.Fatalf("bilinmeyen session'ın hatası = %v", err)
+	}
+	if err := x.Revoke(uuid.New(), sessions.PublicID("a")); err != sessions.ErrNotFound {
+		t.Fatalf("başka kullanıcının session'ı sonlandırıldı: %v", err)
+	}
+	if err := x.Revoke(userID, sessions.PublicID("a")); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := store.Get("a"); raw != nil {
+		t.Fatal("sonlandırılan session storage'da duruyor")
+	}
+
+	revoked, err := x.RevokeOthers(userID, "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revoked != 1 {
+		t.Fatalf("sonlandırılan session sayısı = %d, beklenen 1", revoked)
+	}
+	list, _ := x.List(userID)
+	if len(list) != 1 || list[0].Key != "c" {
+		t.Fatalf("kalan session'lar = %+v", list)
+	}
+
+	if err := x.RevokeAll(userID); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := store.Get("c"); raw != nil {
+		t.Fatal("RevokeAll geçerli session'ı bıraktı")
+	}
+}
@@ -0,0 +1,230 @@
+// Package validate, istek DTO'larını `validate` struct etiketlerine göre doğrular.
+//
+// Kurallar virgülle ayrılır ve soldan sağa uygulanır:
+//
+//	required      alan boş olamaz (boş veya yalnızca boşluk içeren metin, boş dilim, sıfır değer, uuid.Nil)
+//	omitempty     alan boşsa sonraki kurallar atlanır
+//	min=N, max=N  metinlerde karakter sayısı, sayılarda değer, dilimlerde eleman sayısı
+//	email         geçerli bir e-posta adresi
+//	oneof=a b c   değer boşlukla ayrılmış seçeneklerden biri olmalı
+//	uuid          metin geçerli bir UUID olmalı
+//	date          metin YYYY-AA-GG biçiminde geçerli bir tarih olmalı
+//	slug          metin yalnızca küçük harf, rakam ve tire içermeli; tireyle başlayıp bitemez
+//	gtefield=F    sayı aynı struct'taki F alanından küçük olamaz (ör. aralıkların üst sınırı)
+//	dive          sonraki kurallar dilimin her elemanına ayrı ayrı uygulanır
+//
+// Alan adı olarak json etiketi kullanılır; böylece hata ayrıntıları istemcinin
+// gönderdiği anahtarlarla eşleşir. Geçersiz bir etiket programlama hatasıdır ve panic'e yol açar.
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"spoti/apierr"
+)
+
+// Struct, v'nin (struct veya struct işaretçisi) alanlarını doğrular ve bulunan
+// tüm hataları döndürür. Hata yoksa nil döner.
+func Struct(v any) []apierr.FieldError {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: struct bekleniyordu, %s verildi", rv.Kind()))
+	}
+
+	var errs []apierr.FieldError
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, ok := field.Tag.Lookup("validate")
+		if !ok || !field.IsExported() {
+			continue
+		}
+		if fe, failed := checkField(fieldName(field), rv.Field(i), tag, rv); failed {
+			errs = append(errs, fe)
+		}
+	}
+	return errs
+}
+
+// fieldName, alanın json etiketindeki adını, yoksa Go adını döndürür.
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// checkField, alana kuralları sırayla uygular ve ilk başarısız kuralın hatasını döndürür.
+// parent, alanın bulunduğu struct'tır; alanlar arası kurallar onu kullanır.
+func checkField(name string, v reflect.Value, tag string, parent reflect.Value) (apierr.FieldError, bool) {
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch rule {
+		case "slug":
+			if !isSlug(v.String()) {
+				return fail(name, rule, "validation.slug")
+			}
+		case "dive":
+			return checkElems(name, v, strings.Join(rules[i+1:], ","), parent)
+		case "gtefield":
+			if fe, failed := checkGteField(name, v, param, parent); failed {
+				return fe, true
+			}
+		case "omitempty":
+			if isEmpty(v) {
+				return apierr.FieldError{}, false
+			}
+		case "required":
+			if isEmpty(v) {
+				return fail(name, rule, "validation.required")
+			}
+		case "min", "max":
+			if fe, failed := checkBound(name, v, rule, param); failed {
+				return fe, true
+			}
+		case "email":
+			if !isEmail(v.String()) {
+				return fail(name, rule, "validation.email")
+			}
+		case "oneof":
+			options := strings.Fields(param)
+			if !contains(options, fmt.Sprint(v.Interface())) {
+				return fail(name, rule, "validation.oneof", strings.Join(options, ", "))
+			}
+		case "uuid":
+			if _, err := uuid.Parse(v.String()); err != nil {
+				return fail(name, rule, "validation.uuid")
+			}
+		case "date":
+			if _, err := time.Parse(time.DateOnly, v.String()); err != nil {
+				return fail(name, rule, "validation.date")
+			}
+		default:
+			panic(fmt.Sprintf("validate: %s alanında bilinmeyen kural %q", name, rule))
+		}
+	}
+	return apierr.FieldError{}, false
+}
+
+// checkElems, dilimin her elemanını kalan kurallarla doğrular ve ilk hatayı
+// "alan[indeks]" adıyla döndürür.
+func checkElems(name string, v reflect.Value, tag string, parent reflect.Value) (apierr.FieldError, bool) {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		panic(fmt.Sprintf("validate: %s alanının türü (%s) dive kuralını desteklemiyor", name, v.Kind()))
+	}
+	for i := 0; i < v.Len(); i++ {
+		if fe, failed := checkField(fmt.Sprintf("%s[%d]", name, i), v.Index(i), tag, parent); failed {
+			return fe, true
+		}
+	}
+	return apierr.FieldError{}, false
+}
+
+// checkBound, min ve max kurallarını alanın türüne göre uygular.
+func checkBound(name string, v reflect.Value, rule, param string) (apierr.FieldError, bool) {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validate: %s alanında geçersiz %s değeri %q", name, rule, param))
+	}
+
+	var actual float64
+	suffix := ""
+	switch v.Kind() {
+	case reflect.String:
+		actual, suffix = float64(utf8.RuneCountInString(v.String())), "_length"
+	case reflect.Slice, reflect.Map:
+		actual, suffix = float64(v.Len()), "_items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = v.Float()
+	default:
+		panic(fmt.Sprintf("validate: %s alanının türü (%s) %s kuralını desteklemiyor", name, v.Kind(), rule))
+	}
+
+	if (rule == "min" && actual < limit) || (rule == "max" && actual > limit) {
+		return fail(name, rule, "validation."+rule+suffix, param)
+	}
+	return apierr.FieldError{}, false
+}
+
+// checkGteField, sayısal alanın aynı struct'taki other alanından küçük olmadığını kontrol eder.
+// Hata mesajında diğer alanın istemcinin gönderdiği adı kullanılır.
+func checkGteField(name string, v reflect.Value, other string, parent reflect.Value) (apierr.FieldError, bool) {
+	field, ok := parent.Type().FieldByName(other)
+	if !ok {
+		panic(fmt.Sprintf("validate: %s alanındaki gtefield kuralı bilinmeyen %q alanını gösteriyor", name, other))
+	}
+	ov := parent.FieldByIndex(field.Index)
+
+	var less bool
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		less = v.Int() < ov.Int()
+	case reflect.Float32, reflect.Float64:
+		less = v.Float() < ov.Float()
+	default:
+		panic(fmt.Sprintf("validate: %s alanının türü (%s) gtefield kuralını desteklemiyor", name, v.Kind()))
+	}
+	if less {
+		return fail(name, "gtefield", "validation.gtefield", fieldName(field))
+	}
+	return apierr.FieldError{}, false
+}
+
+func fail(name, rule, key string, args ...any) (apierr.FieldError, bool) {
+	return apierr.FieldError{Field: name, Code: rule, Key: key, Args: args}, true
+}
+
+// isEmpty, alanın sıfır değerde olup olmadığını döndürür. Yalnızca boşluk içeren
+// metinler de boş kabul edilir.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// isEmail, metnin görünen ad içermeyen yalın bir e-posta adresi olup olmadığını döndürür.
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s && strings.Contains(s[strings.LastIndexByte(s, '@'):], ".")
+}
+
+// isSlug, metnin "content-editor" gibi küçük harf, rakam ve tirelerden oluşan bir ad olup
+// olmadığını döndürür.
+func isSlug(s string) bool {
+	if s == "" || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(options []string, s string) bool {
+	for _, o := range options {
+		if o == s {
+			return true
+		}
+	}
+	return false
+}